@@ -7,10 +7,16 @@ import (
 	middleware "github.com/obada-foundation/client-helper/api/middleware/v1"
 	"github.com/obada-foundation/client-helper/api/v1"
 	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events/handlers"
+	"github.com/obada-foundation/client-helper/events/stream"
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/blockchain"
 	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/services/migration"
+	"github.com/obada-foundation/client-helper/services/multisig"
+	"github.com/obada-foundation/client-helper/services/webhook"
 	"github.com/obada-foundation/client-helper/system/web"
 	"github.com/obada-foundation/registry/client"
 	"go.uber.org/zap"
@@ -28,6 +34,14 @@ type APIMuxConfig struct { //nolint:revive //for future refactoring
 	DeviceSvc     *device.Service
 	ObitSvc       *services.ObitService
 	Registry      client.Client
+	WebhookSvc    *webhook.Service
+	AuditSvc      *audit.Service
+	MultisigSvc   *multisig.Service
+	MigrationSvc  *migration.Service
+
+	// Events
+	EventHub     *stream.Hub
+	EventManager *handlers.EventManager
 }
 
 // APIMux constructs a http.Handler with all application routes defined.
@@ -51,6 +65,14 @@ func APIMux(cfg APIMuxConfig) http.Handler { //nolint:revive //for future refact
 		DeviceSvc:     cfg.DeviceSvc,
 		ObitSvc:       cfg.ObitSvc,
 		Registry:      cfg.Registry,
+		WebhookSvc:    cfg.WebhookSvc,
+		AuditSvc:      cfg.AuditSvc,
+		MultisigSvc:   cfg.MultisigSvc,
+		MigrationSvc:  cfg.MigrationSvc,
+
+		// Events
+		EventHub:     cfg.EventHub,
+		EventManager: cfg.EventManager,
 	})
 
 	return app
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// Audit records the action of the route to the audit log, the account address or the obit key
+// of the route is the default target of the action
+func Audit(auditSvc *audit.Service, action string) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			target := web.Param(r, "address")
+			if target == "" {
+				target = web.Param(r, "key")
+			}
+
+			ctx = audit.NewContext(ctx, action, target)
+
+			err := handler(ctx, w, r)
+
+			// the response is already sent, so the failed record is only logged by the service
+			_ = auditSvc.Record(ctx, err)
+
+			return err
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/obada-foundation/client-helper/auth"
 	"github.com/obada-foundation/client-helper/services/account"
 	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/services/migration"
+	"github.com/obada-foundation/client-helper/services/multisig"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
 	"github.com/obada-foundation/client-helper/system/web"
 	"go.uber.org/zap"
@@ -40,14 +43,53 @@ func Errors(log *zap.SugaredLogger) web.Middleware {
 
 					status = http.StatusBadRequest
 
-					if errors.Is(err, account.ErrWalletNotExists) {
+					if errors.Is(err, account.ErrWalletNotExists) || errors.Is(err, account.ErrWalletRotationNotExists) {
 						status = http.StatusNotFound
 					}
 
-					if errors.Is(err, account.ErrWalletExists) {
+					if errors.Is(err, account.ErrWalletExists) || errors.Is(err, account.ErrAccountHasAssets) ||
+						errors.Is(err, account.ErrWalletRotationInProgress) {
 						status = http.StatusConflict
 					}
 
+					if errors.Is(err, account.ErrSigningPassphraseNotExists) {
+						status = http.StatusNotFound
+					}
+
+					if errors.Is(err, account.ErrSigningPassphraseRequired) || errors.Is(err, account.ErrInvalidSigningPassphrase) {
+						status = http.StatusForbidden
+					}
+
+					if errors.Is(err, account.ErrSigningLocked) {
+						status = http.StatusTooManyRequests
+					}
+
+				case migration.IsMigrationError(err):
+					er = appErrors.ErrorResponse{
+						Error: err.Error(),
+					}
+					status = http.StatusBadRequest
+
+					if errors.Is(err, migration.ErrRotationNotExists) {
+						status = http.StatusNotFound
+					}
+
+					if errors.Is(err, migration.ErrRotationNotCompleted) || errors.Is(err, migration.ErrRotationRunning) {
+						status = http.StatusConflict
+					}
+
+				case multisig.IsMultisigError(err):
+					er = appErrors.ErrorResponse{
+						Error: err.Error(),
+					}
+					status = http.StatusBadRequest
+
+				case signer.IsSignerError(err):
+					er = appErrors.ErrorResponse{
+						Error: err.Error(),
+					}
+					status = http.StatusBadGateway
+
 				case validate.IsFieldErrors(err):
 					fieldErrors := validate.GetFieldErrors(err)
 					er = appErrors.ErrorResponse{
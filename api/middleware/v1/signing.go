@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// HeaderSigningPassphrase carries the signing passphrase or PIN of the account
+const HeaderSigningPassphrase = "X-Signing-Passphrase"
+
+// SigningPassphrase marks the request as the signing operation, keys of accounts protected by
+// the signing passphrase are unlocked only with the passphrase from the header
+func SigningPassphrase() web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			ctx = account.WithSigningPassphrase(ctx, r.Header.Get(HeaderSigningPassphrase))
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -144,9 +144,9 @@ func TestAccount(t *testing.T) {
 		assert.Equal(t, "", acc.Name)
 	}
 
-	t.Log("Test that adding a new OBADA account to the profile will fail if last account has zero transactions")
+	t.Log("Test that adding new OBADA accounts to the profile will fail if unused accounts exceed the gap limit")
 	{
-		resp, err := postWithAuth(t, srv.URL+"/api/v1/accounts/new-account", "{}")
+		resp, err := postWithAuth(t, srv.URL+"/api/v1/accounts/new-account", `{"count":20}`)
 		require.NoError(t, err)
 
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
@@ -160,7 +160,7 @@ func TestAccount(t *testing.T) {
 		err = json.Unmarshal(b, &c)
 		assert.NoError(t, err)
 
-		assert.Equal(t, account.ErrAccountHasZeroTx.Error(), c["error"])
+		assert.Equal(t, account.ErrGapLimitReached.Error(), c["error"])
 	}
 }
 
@@ -318,6 +318,46 @@ func TestAccount_exportAccount(t *testing.T) {
 	}
 }
 
+func TestAccount_grantAuthzWithoutSigningPassphrase(t *testing.T) {
+	srv, teardown := startupT(t)
+	defer teardown()
+
+	resp, err := postWithAuth(
+		t,
+		srv.URL+"/api/v1/accounts/register", `{"email":"john.doe@supermail.com"}`,
+	)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	resp, err = postWithAuth(
+		t,
+		srv.URL+"/api/v1/accounts/new-wallet", `{"mnemonic":"`+defaultMnemonic+`"}`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	resp, err = putWithAuth(
+		t,
+		srv.URL+"/api/v1/accounts/signing-passphrase", `{"passphrase":"1234"}`, nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	t.Log("Authz grant is rejected without the signing passphrase")
+	{
+		resp, err := postWithAuth(
+			t,
+			srv.URL+"/api/v1/accounts/"+defaultAccount+"/authz",
+			`{"grantee":"obada1ka3mj6qa0nr8q2xrxqhdu7l7y4e3nvhpj8dmzw","msg_type":"mint_nft"}`,
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+}
+
 func TestAccount_newMnemonic(t *testing.T) {
 	srv, teardown := startupT(t)
 	defer teardown()
@@ -18,6 +18,7 @@ import (
 	"github.com/obada-foundation/client-helper/auth"
 	"github.com/obada-foundation/client-helper/events"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/pubkey"
 	"github.com/obada-foundation/client-helper/system/obadanode"
 	"github.com/obada-foundation/client-helper/system/validate"
@@ -106,6 +107,7 @@ func startupT(t *testing.T) (*httptest.Server, func()) {
 		Auth:     a,
 
 		AccountSvc: accountSvc,
+		AuditSvc:   audit.NewService(audit.Config{DB: database, Logger: logger}),
 	})
 
 	srv := httptest.NewServer(mux)
@@ -159,6 +161,18 @@ func post(t *testing.T, url, body string, headers map[string]string) (*http.Resp
 	return client.Do(req)
 }
 
+func putWithAuth(t *testing.T, url, body string, headers map[string]string) (*http.Response, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Add("authorization", fmt.Sprintf("bearer %s", devToken))
+	for header, headerVal := range headers {
+		req.Header.Add(header, headerVal)
+	}
+	return client.Do(req)
+}
+
 func getWithAuth(t *testing.T, url string) (*http.Response, error) {
 	headers := map[string]string{
 		"authorization": fmt.Sprintf("bearer %s", devToken),
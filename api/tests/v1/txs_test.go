@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxs_unsignedTx(t *testing.T) {
+	srv, teardown := startupT(t)
+	defer teardown()
+
+	resp, err := postWithAuth(
+		t,
+		srv.URL+"/api/v1/accounts/new-wallet", `{"mnemonic":"`+defaultMnemonic+`"}`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	t.Log("Unsigned tx is not built for the account of another profile")
+	{
+		resp, err := postWithAuth(
+			t,
+			srv.URL+"/api/v1/txs/unsigned",
+			`{"signer":"obada1ka3mj6qa0nr8q2xrxqhdu7l7y4e3nvhpj8dmzw","type":"send","receiver":"`+defaultAccount+`"}`,
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/obada-foundation/client-helper/auth"
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/services/migration"
 	"github.com/obada-foundation/client-helper/system/validate"
 	"github.com/obada-foundation/client-helper/system/web"
 )
@@ -17,6 +24,8 @@ import (
 type Handlers struct {
 	AccountSvc    *account.Service
 	BlockchainSvc *blockchain.Service
+	AuditSvc      *audit.Service
+	MigrationSvc  *migration.Service
 }
 
 // Account returns a single account
@@ -31,13 +40,17 @@ func (h Handlers) Account(ctx context.Context, w http.ResponseWriter, r *http.Re
 	return web.Respond(ctx, w, acc, http.StatusOK)
 }
 
-// Accounts returns a list of profile accounts
-func (h Handlers) Accounts(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+// Accounts returns a list of profile accounts, optionally only accounts with the given tag
+func (h Handlers) Accounts(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	profileAccounts, err := h.AccountSvc.GetProfileAccounts(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to fetch profile accounts: %w", err)
 	}
 
+	if tag := web.Query(r, "tag"); tag != "" {
+		profileAccounts = profileAccounts.WithTag(tag)
+	}
+
 	return web.Respond(ctx, w, profileAccounts, http.StatusOK)
 }
 
@@ -89,14 +102,17 @@ func (h Handlers) NewWallet(ctx context.Context, w http.ResponseWriter, r *http.
 	return web.RespondWithNoContent(ctx, w, http.StatusCreated)
 }
 
-// AccountRequest request body for creating a new account
-type AccountRequest struct {
+// NewAccountRequest request body for creating new accounts
+type NewAccountRequest struct {
 	AccountName string `json:"account_name"`
+
+	// Count how many accounts are created ahead of time, e.g. for receiving NFTs
+	Count uint `json:"count"`
 }
 
-// NewAccount creates a new OBADA account from HD wallet
+// NewAccount creates new OBADA accounts from HD wallet
 func (h Handlers) NewAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	var req AccountRequest
+	var req NewAccountRequest
 
 	if err := web.Decode(r, &req); err != nil {
 		return fmt.Errorf("unable to decode request data: %w", err)
@@ -106,16 +122,112 @@ func (h Handlers) NewAccount(ctx context.Context, w http.ResponseWriter, r *http
 		Name: req.AccountName,
 	}
 
-	if _, err := h.AccountSvc.NewAccount(ctx, acc); err != nil {
+	newAccounts, err := h.AccountSvc.NewAccounts(ctx, acc, req.Count)
+	if err != nil {
 		return err
 	}
 
+	addresses := make([]string, 0, len(newAccounts))
+	for _, newAccount := range newAccounts {
+		addresses = append(addresses, newAccount.Address)
+	}
+
+	audit.SetTarget(ctx, strings.Join(addresses, ","))
+
 	return web.RespondWithNoContent(ctx, w, http.StatusCreated)
 }
 
-// UpdateAccount updates an account
+// RescanResponse accounts found by the rescan
+type RescanResponse struct {
+	Accounts []services.Account `json:"accounts"`
+}
+
+// Rescan discovers accounts that were created elsewhere with the same mnemonic
+func (h Handlers) Rescan(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	accounts, err := h.AccountSvc.Rescan(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, RescanResponse{Accounts: accounts}, http.StatusOK)
+}
+
+// StartWalletRotation starts moving assets of the profile wallet to the wallet of the new mnemonic
+func (h Handlers) StartWalletRotation(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	rotation, err := h.MigrationSvc.StartWalletRotation(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the mnemonic is not sent when the rotation cannot be recorded
+	if err := h.AuditSvc.Record(ctx, nil); err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, rotation, http.StatusAccepted)
+}
+
+// ResumeWalletRotation resumes the failed wallet rotation
+func (h Handlers) ResumeWalletRotation(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	rotation, err := h.MigrationSvc.ResumeWalletRotation(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, rotation, http.StatusAccepted)
+}
+
+// WalletRotation returns the progress of the wallet rotation
+func (h Handlers) WalletRotation(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	rotation, err := h.MigrationSvc.WalletRotation(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, rotation, http.StatusOK)
+}
+
+// CreateMultisig creates the multisig account of profile accounts and external public keys
+func (h Handlers) CreateMultisig(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req services.NewMultisig
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	acc, err := h.AccountSvc.CreateMultisig(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	audit.SetTarget(ctx, acc.Address)
+
+	return web.Respond(ctx, w, acc, http.StatusCreated)
+}
+
+// AddWatchAccount adds the watch-only account of the external address
+func (h Handlers) AddWatchAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req services.NewWatchAccount
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	if err := h.AccountSvc.AddWatchAccount(ctx, req); err != nil {
+		return err
+	}
+
+	acc, err := h.AccountSvc.GetProfileAccount(ctx, req.Address)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, acc, http.StatusCreated)
+}
+
+// UpdateAccount updates account metadata, fields that are not set in the request stay the same
 func (h Handlers) UpdateAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	var req AccountRequest
+	var req services.UpdateAccount
 
 	address := web.Param(r, "address")
 
@@ -123,18 +235,39 @@ func (h Handlers) UpdateAccount(ctx context.Context, w http.ResponseWriter, r *h
 		return fmt.Errorf("unable to decode request data: %w", err)
 	}
 
-	if err := h.AccountSvc.UpdateAccountName(ctx, address, req.AccountName); err != nil {
+	if err := h.AccountSvc.UpdateAccountMetadata(ctx, address, req); err != nil {
 		return err
 	}
 
 	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
 }
 
-// DeleteAccount deletes an exported account
+// DeletionCheck reports on-chain assets of the account that would be deleted
+func (h Handlers) DeletionCheck(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	check, err := h.AccountSvc.DeletionCheck(ctx, web.Param(r, "address"))
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, check, http.StatusOK)
+}
+
+// DeleteAccount deletes the account. The account that holds assets is deleted when its key was exported,
+// the deletion is forced by ?force=true or assets are moved to another profile account by ?move_to=<address>.
 func (h Handlers) DeleteAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	address := web.Param(r, "address")
+	force := web.Query(r, "force") == "true"
+
+	if moveTo := web.Query(r, "move_to"); moveTo != "" {
+		if _, err := h.MigrationSvc.MoveAssets(ctx, address, moveTo); err != nil {
+			return err
+		}
 
-	if err := h.AccountSvc.DeleteAccount(ctx, address); err != nil {
+		// the fee of the last transfer stays on the account
+		force = true
+	}
+
+	if err := h.AccountSvc.DeleteAccount(ctx, address, force); err != nil {
 		return err
 	}
 
@@ -173,6 +306,7 @@ func (h Handlers) ImportWallet(ctx context.Context, w http.ResponseWriter, r *ht
 
 // ImportAccountRequest request body for importing an existing account
 type ImportAccountRequest struct {
+	// PrivateKey is armored, hex or keystore JSON, the format is detected
 	PrivateKey  string `json:"private_key"`
 	Passphrase  string `json:"passphrase"`
 	AccountName string `json:"account_name"`
@@ -210,6 +344,9 @@ func (h Handlers) ImportAccount(ctx context.Context, w http.ResponseWriter, r *h
 type ExportAccountRequest struct {
 	Address    string `json:"address"`
 	Passphrase string `json:"passphrase"`
+
+	// Format is armor (default), hex, keystore or pubkey
+	Format account.KeyFormat `json:"format"`
 }
 
 // ExportAccountResponse response body for exporting an account
@@ -217,7 +354,7 @@ type ExportAccountResponse struct {
 	PrivateKey string `json:"private_key"`
 }
 
-// ExportAccount exports armored private key
+// ExportAccount exports the private key in the requested format, the pubkey format exports the public key and address only
 func (h Handlers) ExportAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	var req ExportAccountRequest
 
@@ -225,11 +362,27 @@ func (h Handlers) ExportAccount(ctx context.Context, w http.ResponseWriter, r *h
 		return fmt.Errorf("unable to decode request data: %w", err)
 	}
 
-	exportedAccount, err := h.AccountSvc.ExportAccount(ctx, req.Address, req.Passphrase)
+	audit.SetTarget(ctx, req.Address)
+
+	if req.Format == account.KeyFormatPubKey {
+		bundle, err := h.AccountSvc.ExportPubKey(ctx, req.Address)
+		if err != nil {
+			return err
+		}
+
+		return web.Respond(ctx, w, bundle, http.StatusOK)
+	}
+
+	exportedAccount, err := h.AccountSvc.ExportAccount(ctx, req.Address, req.Passphrase, req.Format)
 	if err != nil {
 		return err
 	}
 
+	// the private key is not sent when the export cannot be recorded
+	if err := h.AuditSvc.Record(ctx, nil); err != nil {
+		return err
+	}
+
 	return web.Respond(ctx, w, &ExportAccountResponse{PrivateKey: exportedAccount}, http.StatusOK)
 }
 
@@ -250,12 +403,41 @@ func (h Handlers) NewMnemonic(ctx context.Context, w http.ResponseWriter, _ *htt
 
 // Mnemonic shows am existing mnemonic phrase
 func (h Handlers) Mnemonic(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
-	wallet, err := h.AccountSvc.GetWallet(ctx)
+	mnemonic, err := h.AccountSvc.Mnemonic(ctx)
 	if err != nil {
 		return err
 	}
 
-	return web.Respond(ctx, w, &GenerateMnemonicResponse{Mnemonic: wallet.Mnemonic}, http.StatusOK)
+	// the mnemonic is not sent when the reveal cannot be recorded
+	if err := h.AuditSvc.Record(ctx, nil); err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, &GenerateMnemonicResponse{Mnemonic: mnemonic}, http.StatusOK)
+}
+
+// SetSigningPassphrase sets the signing passphrase of the profile or of the account
+func (h Handlers) SetSigningPassphrase(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req services.SigningPassphrase
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	if err := h.AccountSvc.SetSigningPassphrase(ctx, web.Param(r, "address"), req); err != nil {
+		return err
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
+}
+
+// RemoveSigningPassphrase removes the signing passphrase of the profile or of the account
+func (h Handlers) RemoveSigningPassphrase(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if err := h.AccountSvc.RemoveSigningPassphrase(ctx, web.Param(r, "address")); err != nil {
+		return err
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
 }
 
 // SendCoinsRequest requestion body for seding OBD
@@ -280,16 +462,228 @@ func (h Handlers) SendCoins(ctx context.Context, w http.ResponseWriter, r *http.
 		return err
 	}
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, address)
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, address)
 	if err != nil {
 		return err
 	}
 
-	amount := fmt.Sprintf("%s%s", req.Amount, req.Denom)
+	amount, err := h.BlockchainSvc.ParseAmount(ctx, req.Amount, req.Denom)
+	if err != nil {
+		return err
+	}
 
-	if err := h.BlockchainSvc.Send(ctx, acc, req.RecipientAddress, amount, privKey); err != nil {
+	if err := h.BlockchainSvc.Send(ctx, acc, req.RecipientAddress, amount, signer); err != nil {
 		return err
 	}
 
 	return web.RespondWithNoContent(ctx, w, http.StatusCreated)
 }
+
+const (
+	defaultTxsPerPage = 20
+	maxTxsPerPage     = 100
+)
+
+// Txs returns a paginated list of account transactions
+func (h Handlers) Txs(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+	msgType := web.Query(r, "type")
+
+	page, err := queryInt(r, "page", 1)
+	if err != nil || page < 1 {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "page",
+				Error: "page should be a positive number",
+			},
+		}
+	}
+
+	perPage, err := queryInt(r, "per_page", defaultTxsPerPage)
+	if err != nil || perPage < 1 || perPage > maxTxsPerPage {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "per_page",
+				Error: fmt.Sprintf("per_page should be between 1 and %d", maxTxsPerPage),
+			},
+		}
+	}
+
+	history, err := h.BlockchainSvc.TxHistory(ctx, address, msgType, page, perPage)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, history, http.StatusOK)
+}
+
+func queryInt(r *http.Request, key string, defaultValue int) (int, error) {
+	v := web.Query(r, key)
+	if v == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(v)
+}
+
+// GrantAllowanceRequest request data for granting fee allowance to the account
+type GrantAllowanceRequest struct {
+	Amount     string     `json:"amount"`
+	Denom      string     `json:"denom"`
+	Expiration *time.Time `json:"expiration"`
+}
+
+// Allowance returns fee allowance granted by the sponsor to the account
+func (h Handlers) Allowance(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+
+	allowance, err := h.BlockchainSvc.Allowance(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, allowance, http.StatusOK)
+}
+
+// GrantAllowance grants or replaces fee allowance of the account, so the sponsor pays for mints and metadata updates
+func (h Handlers) GrantAllowance(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+
+	var req GrantAllowanceRequest
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	if req.Expiration != nil && req.Expiration.Before(time.Now()) {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "expiration",
+				Error: "expiration should be in the future",
+			},
+		}
+	}
+
+	var spendLimit sdk.Coins
+
+	if req.Amount != "" {
+		coin, err := h.BlockchainSvc.ParseAmount(ctx, req.Amount, req.Denom)
+		if err != nil {
+			return err
+		}
+
+		spendLimit = sdk.NewCoins(coin)
+	}
+
+	if err := h.BlockchainSvc.GrantAllowance(ctx, address, spendLimit, req.Expiration); err != nil {
+		return err
+	}
+
+	allowance, err := h.BlockchainSvc.Allowance(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, allowance, http.StatusCreated)
+}
+
+// RevokeAllowance revokes fee allowance of the account
+func (h Handlers) RevokeAllowance(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+
+	if _, err := h.BlockchainSvc.Allowance(ctx, address); err != nil {
+		return err
+	}
+
+	if err := h.BlockchainSvc.RevokeAllowance(ctx, address); err != nil {
+		return err
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
+}
+
+// GrantAuthzRequest request data for granting execution of messages to another account
+type GrantAuthzRequest struct {
+	Grantee    string     `json:"grantee"`
+	MsgType    string     `json:"msg_type"`
+	Expiration *time.Time `json:"expiration"`
+}
+
+// AuthzGrants returns authz grants given and received by the account
+func (h Handlers) AuthzGrants(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+
+	grants, err := h.BlockchainSvc.AuthzGrants(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, grants, http.StatusOK)
+}
+
+// GrantAuthz allows another account to mint and update NFTs on behalf of the account
+func (h Handlers) GrantAuthz(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+
+	var req GrantAuthzRequest
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	if req.Grantee == "" || req.Grantee == address {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "grantee",
+				Error: "grantee should be another account",
+			},
+		}
+	}
+
+	if req.Expiration != nil && req.Expiration.Before(time.Now()) {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "expiration",
+				Error: "expiration should be in the future",
+			},
+		}
+	}
+
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if err := h.BlockchainSvc.GrantAuthz(ctx, req.Grantee, req.MsgType, req.Expiration, signer); err != nil {
+		return err
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusCreated)
+}
+
+// RevokeAuthz revokes authz grant given by the account
+func (h Handlers) RevokeAuthz(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Param(r, "address")
+	grantee := web.Query(r, "grantee")
+	msgType := web.Query(r, "msg_type")
+
+	if grantee == "" {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "grantee",
+				Error: "grantee is required",
+			},
+		}
+	}
+
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if err := h.BlockchainSvc.RevokeAuthz(ctx, grantee, msgType, signer); err != nil {
+		return err
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/obada-foundation/client-helper/events/handlers"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// Handlers holds dependencies
+type Handlers struct {
+	EventManager *handlers.EventManager
+}
+
+// EventFailures returns events that were not handled after all attempts
+func (h Handlers) EventFailures(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	failures, err := h.EventManager.Failures(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, failures, http.StatusOK)
+}
+
+// RetryEvents queues failed events for handling, the request body is optional
+func (h Handlers) RetryEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req services.RetryEvents
+
+	if err := web.Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	retried, err := h.EventManager.Retry(ctx, req.EventIDs)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, retried, http.StatusAccepted)
+}
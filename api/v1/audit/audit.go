@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// Handlers holds dependencies
+type Handlers struct {
+	AuditSvc *audit.Service
+}
+
+// Entries returns audit log entries of the profile
+func (h Handlers) Entries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter := services.AuditFilter{
+		Action: web.Query(r, "action"),
+		Target: web.Query(r, "target"),
+		Result: web.Query(r, "result"),
+	}
+
+	var fieldErrors validate.FieldErrors
+
+	if filter.Result != "" && filter.Result != services.AuditResultSuccess && filter.Result != services.AuditResultFailure {
+		fieldErrors = append(fieldErrors, validate.FieldError{
+			Field: "result",
+			Error: fmt.Sprintf("result should be %s or %s", services.AuditResultSuccess, services.AuditResultFailure),
+		})
+	}
+
+	for _, q := range []struct {
+		field string
+		t     *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		v := web.Query(r, q.field)
+		if v == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			fieldErrors = append(fieldErrors, validate.FieldError{
+				Field: q.field,
+				Error: q.field + " should be RFC 3339 time",
+			})
+
+			continue
+		}
+
+		*q.t = t
+	}
+
+	if v := web.Query(r, "limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > audit.MaxLimit {
+			fieldErrors = append(fieldErrors, validate.FieldError{
+				Field: "limit",
+				Error: fmt.Sprintf("limit should be between 1 and %d", audit.MaxLimit),
+			})
+		}
+
+		filter.Limit = limit
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	entries, err := h.AuditSvc.Search(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, entries, http.StatusOK)
+}
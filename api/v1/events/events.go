@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	appErrors "github.com/obada-foundation/client-helper/api/errors"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events/stream"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+const (
+	// keepAlive how often the comment is sent to keep the idle connection open
+	keepAlive = 15 * time.Second
+
+	keepAliveMsg = ": keep-alive\n\n"
+)
+
+// ErrInvalidLastEventID is returned when the Last-Event-ID header is not an event ID
+var ErrInvalidLastEventID = errors.New("invalid Last-Event-ID header")
+
+// Handlers holds dependencies
+type Handlers struct {
+	Hub *stream.Hub
+}
+
+// Stream pushes events of the profile to the client as Server-Sent Events.
+// Clients that reconnect with the Last-Event-ID header receive events they missed.
+func (h Handlers) Stream(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var lastID uint64
+
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			return appErrors.NewRequestError(ErrInvalidLastEventID, http.StatusBadRequest)
+		}
+
+		lastID = id
+	}
+
+	rc := http.NewResponseController(w)
+
+	// the stream outlives the server write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	missed, evts, unsubscribe := h.Hub.Subscribe(auth.GetUserID(ctx), lastID)
+	defer unsubscribe()
+
+	web.SetStatusCode(ctx, http.StatusOK)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, evt := range missed {
+		if _, err := io.WriteString(w, evt.String()); err != nil {
+			return nil
+		}
+	}
+
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		var msg string
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case evt, ok := <-evts:
+			// the client was too slow, it resumes from the last received event after the reconnect
+			if !ok {
+				return nil
+			}
+
+			msg = evt.String()
+
+		case <-ticker.C:
+			msg = keepAliveMsg
+		}
+
+		// the client is gone
+		if _, err := io.WriteString(w, msg); err != nil {
+			return nil
+		}
+
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
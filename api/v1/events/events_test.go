@@ -0,0 +1,76 @@
+package events_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/api/v1/events"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events/stream"
+	"github.com/obada-foundation/client-helper/system/web"
+	"github.com/obada-foundation/client-helper/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers_Stream(t *testing.T) {
+	var fn bus.Next = func() string { return "afakeid" }
+	b, err := bus.NewBus(fn)
+	require.NoError(t, err)
+
+	logger, deleteLogFile := testutil.MakeLoger()
+	defer deleteLogFile()
+
+	hub := stream.NewHub(stream.Config{Bus: b, Logger: logger})
+
+	withProfile := func(handler web.Handler) web.Handler {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			return handler(auth.SetClaims(ctx, auth.Claims{UserID: "1"}), w, r)
+		}
+	}
+
+	app := web.NewApp(make(chan os.Signal, 1))
+	app.Handle(http.MethodGet, "", "/events", events.Handlers{Hub: hub}.Stream, withProfile)
+
+	srv := &http.Server{Handler: app, ReadHeaderTimeout: time.Second}
+	srv.RegisterOnShutdown(hub.Close)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.Serve(ln)
+	}()
+
+	t.Log("Ends open streams on the server shutdown")
+	{
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+ln.Addr().String()+"/events", nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		hub.Publish("1", "nft.minted", []byte(`"did:obada:1"`))
+
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		require.NoError(t, err)
+		require.Contains(t, line, "id: ")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		require.NoError(t, srv.Shutdown(ctx))
+		require.True(t, errors.Is(<-served, http.ErrServerClosed))
+	}
+}
@@ -0,0 +1,285 @@
+package multisig
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	appErrors "github.com/obada-foundation/client-helper/api/errors"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/services/multisig"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// Handlers holds dependencies
+type Handlers struct {
+	AccountSvc  *account.Service
+	DeviceSvc   *device.Service
+	MultisigSvc *multisig.Service
+}
+
+// SignRequest signature of the co-signer, either the profile account signs the transaction
+// or the external co-signer sends the signature of sign bytes
+type SignRequest struct {
+	// Signer the profile account that signs the transaction
+	Signer string `json:"signer"`
+
+	// PubKey hex encoded secp256k1 public key of the external co-signer
+	PubKey string `json:"pub_key"`
+
+	// Signature base64 encoded signature of sign bytes by the external co-signer
+	Signature []byte `json:"signature"`
+}
+
+// Txs returns transactions of multisig accounts that the profile owns or co-signs
+func (h Handlers) Txs(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	address := web.Query(r, "address")
+
+	if address != "" {
+		if _, err := h.AccountSvc.GetCosignedMultisigPubKey(ctx, address); err != nil {
+			return err
+		}
+	}
+
+	txs, err := h.MultisigSvc.List(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	cosigned := make(map[string]bool)
+	profileTxs := make([]services.MultisigTx, 0, len(txs))
+
+	for _, tx := range txs {
+		ok, checked := cosigned[tx.Address]
+		if !checked {
+			_, err := h.AccountSvc.GetCosignedMultisigPubKey(ctx, tx.Address)
+			ok = err == nil
+			cosigned[tx.Address] = ok
+		}
+
+		if ok {
+			profileTxs = append(profileTxs, tx)
+		}
+	}
+
+	return web.Respond(ctx, w, profileTxs, http.StatusOK)
+}
+
+// Tx returns the multisig transaction
+func (h Handlers) Tx(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	tx, _, err := h.tx(ctx, web.Param(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tx, http.StatusOK)
+}
+
+// Sign adds the co-signer signature, the transaction is broadcasted when the threshold is reached
+func (h Handlers) Sign(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req SignRequest
+
+	id := web.Param(r, "id")
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	audit.SetTarget(ctx, id)
+
+	tx, pubKey, err := h.tx(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// the profile signs only by co-signers that it owns
+	if req.Signer != "" {
+		signer, er := h.AccountSvc.GetAccountSigner(ctx, req.Signer)
+		if er != nil {
+			return er
+		}
+
+		tx, err = h.MultisigSvc.Sign(ctx, id, pubKey, signer)
+	} else {
+		b, er := hex.DecodeString(req.PubKey)
+		if er != nil || len(b) != secp256k1.PubKeySize {
+			return validate.FieldErrors{
+				validate.FieldError{
+					Field: "pub_key",
+					Error: "signer or hex encoded secp256k1 public key is required",
+				},
+			}
+		}
+
+		cosigner := &secp256k1.PubKey{Key: b}
+
+		// keys of the profile are limited to one signature per profile, external co-signers are not
+		profileID, er := h.AccountSvc.GetProfileByAddress(sdk.AccAddress(cosigner.Address()).String())
+		switch {
+		case er != nil:
+			tx, err = h.MultisigSvc.AddSignature(ctx, id, pubKey, cosigner, req.Signature)
+		case profileID != auth.GetUserID(ctx):
+			return fmt.Errorf("%w: the co-signer belongs to another profile", multisig.ErrNotCosigner)
+		default:
+			tx, err = h.MultisigSvc.AddProfileSignature(ctx, id, pubKey, cosigner, req.Signature)
+		}
+	}
+
+	if err != nil {
+		return notFound(err)
+	}
+
+	tx, err = h.handOver(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tx, http.StatusOK)
+}
+
+// Broadcast broadcasts the transaction that already has signatures of the threshold
+func (h Handlers) Broadcast(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := web.Param(r, "id")
+
+	audit.SetTarget(ctx, id)
+
+	_, pubKey, err := h.tx(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tx, err := h.MultisigSvc.Broadcast(ctx, id, pubKey)
+	if err != nil {
+		return notFound(err)
+	}
+
+	tx, err = h.handOver(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tx, http.StatusOK)
+}
+
+// HandOver rotates the registry key of the NFT that was transferred by the co-signer of another profile,
+// only the profile that owns the multisig account hands the NFT over
+func (h Handlers) HandOver(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := web.Param(r, "id")
+
+	audit.SetTarget(ctx, id)
+
+	tx, _, err := h.tx(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !tx.HandOverPending {
+		return multisig.ErrNoHandOver
+	}
+
+	profileID, err := h.AccountSvc.GetProfileByAddress(tx.Address)
+	if err != nil {
+		return err
+	}
+
+	if profileID != auth.GetUserID(ctx) {
+		return fmt.Errorf("%w: the multisig account belongs to another profile", multisig.ErrNotCosigner)
+	}
+
+	tx, err = h.handOver(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tx, http.StatusOK)
+}
+
+// Cancel deletes the pending multisig transaction
+func (h Handlers) Cancel(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := web.Param(r, "id")
+
+	audit.SetTarget(ctx, id)
+
+	if _, _, err := h.tx(ctx, id); err != nil {
+		return err
+	}
+
+	if err := h.MultisigSvc.Cancel(ctx, id); err != nil {
+		return notFound(err)
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
+}
+
+// tx fetches the multisig transaction and the public key of its multisig account, transactions of multisig
+// accounts that the profile neither owns nor co-signs are not found
+func (h Handlers) tx(ctx context.Context, id string) (services.MultisigTx, *kmultisig.LegacyAminoPubKey, error) {
+	tx, err := h.MultisigSvc.Get(ctx, id)
+	if err != nil {
+		return tx, nil, notFound(err)
+	}
+
+	pubKey, err := h.AccountSvc.GetCosignedMultisigPubKey(ctx, tx.Address)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotExists) || errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return services.MultisigTx{}, nil, notFound(multisig.ErrTxNotExists)
+		}
+
+		return services.MultisigTx{}, nil, err
+	}
+
+	return tx, pubKey, nil
+}
+
+// handOver hands the device over to the receiver once the NFT transfer was broadcasted, the same way as
+// transfers of regular accounts do. The device and the registry key belong to the profile of the multisig account,
+// the signing passphrase of the co-signer does not unlock them, so the hand over stays pending for that profile
+// when the co-signer of another profile broadcasted the transfer.
+func (h Handlers) handOver(ctx context.Context, tx services.MultisigTx) (services.MultisigTx, error) {
+	if !tx.HandOverPending {
+		return tx, nil
+	}
+
+	profileID, err := h.AccountSvc.GetProfileByAddress(tx.Address)
+	if err != nil {
+		return tx, err
+	}
+
+	if profileID != auth.GetUserID(ctx) {
+		return tx, nil
+	}
+
+	signer, err := h.AccountSvc.GetRegistrySigner(ctx, tx.Address)
+	if err != nil {
+		return tx, err
+	}
+
+	if err := h.DeviceSvc.HandOver(ctx, tx.DID, tx.Receiver, signer); err != nil {
+		return tx, err
+	}
+
+	if err := h.DeviceSvc.Delete(ctx, tx.DID); err != nil {
+		return tx, fmt.Errorf("cannot delete device after transfer: %w", err)
+	}
+
+	return h.MultisigSvc.CompleteHandOver(ctx, tx.ID)
+}
+
+func notFound(err error) error {
+	if errors.Is(err, multisig.ErrTxNotExists) {
+		return appErrors.NewRequestError(err, http.StatusNotFound)
+	}
+
+	return err
+}
@@ -2,17 +2,18 @@ package nft
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/blockchain"
 	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/services/multisig"
 	"github.com/obada-foundation/client-helper/system/web"
-	regapi "github.com/obada-foundation/registry/api"
-	pbacc "github.com/obada-foundation/registry/api/pb/v1/account"
-	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
 	registry "github.com/obada-foundation/registry/client"
 )
 
@@ -21,9 +22,40 @@ type Handlers struct {
 	AccountSvc    *account.Service
 	DeviceSvc     *device.Service
 	BlockchainSvc *blockchain.Service
+	MultisigSvc   *multisig.Service
 	Registry      registry.Client
 }
 
+// proposeMultisig proposes the transaction when the signer is the multisig account, co-signers sign it later.
+// It returns false when the signer is the regular account.
+func (h Handlers) proposeMultisig(ctx context.Context, w http.ResponseWriter, req blockchain.UnsignedTxRequest) (bool, error) {
+	pubKey, err := h.AccountSvc.GetMultisigPubKey(ctx, req.Signer)
+	if err != nil {
+		if errors.Is(err, account.ErrNotMultisigAccount) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	tx, err := h.MultisigSvc.Propose(ctx, pubKey, req)
+	if err != nil {
+		return true, err
+	}
+
+	return true, web.Respond(ctx, w, tx, http.StatusAccepted)
+}
+
+// signerAddress returns the account that signs NFT transaction, by default device owner signs it.
+// Another profile account can sign it on behalf of the owner when the owner granted authz to it.
+func signerAddress(r *http.Request, d services.Device) string {
+	if signer := web.Query(r, "signer"); signer != "" {
+		return signer
+	}
+
+	return d.Address
+}
+
 // NFT reponds NFT
 func (h Handlers) NFT(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	key := web.Param(r, "key")
@@ -55,12 +87,22 @@ func (h Handlers) Mint(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		return err
 	}
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, d.Address)
+	address := signerAddress(r, d)
+
+	if ok, er := h.proposeMultisig(ctx, w, blockchain.UnsignedTxRequest{
+		Signer: address,
+		Type:   blockchain.TxMsgMint,
+		Device: d,
+	}); ok || er != nil {
+		return er
+	}
+
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, address)
 	if err != nil {
 		return err
 	}
 
-	if err := h.BlockchainSvc.MintNFT(ctx, d, privKey); err != nil {
+	if err := h.BlockchainSvc.MintNFT(ctx, d, signer); err != nil {
 		return err
 	}
 
@@ -75,17 +117,19 @@ func (h Handlers) BatchMint(ctx context.Context, w http.ResponseWriter, r *http.
 		return fmt.Errorf("unable to decode request data: %w", err)
 	}
 
+	audit.SetTarget(ctx, strings.Join(req.Nfts, ","))
+
 	devices, err := h.DeviceSvc.GetByDIDs(ctx, req.Nfts)
 	if err != nil {
 		return err
 	}
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, devices[0].Address)
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, devices[0].Address)
 	if err != nil {
 		return err
 	}
 
-	if err := h.BlockchainSvc.BatchMintNFT(ctx, devices, privKey); err != nil {
+	if err := h.BlockchainSvc.BatchMintNFT(ctx, devices, signer); err != nil {
 		return err
 	}
 
@@ -105,12 +149,12 @@ func (h Handlers) UpdateMetadata(ctx context.Context, w http.ResponseWriter, r *
 		return er
 	}
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, d.Address)
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, signerAddress(r, d))
 	if err != nil {
 		return err
 	}
 
-	if er := h.BlockchainSvc.EditNFTMetadata(ctx, d, privKey); er != nil {
+	if er := h.BlockchainSvc.EditNFTMetadata(ctx, d, signer); er != nil {
 		return er
 	}
 
@@ -132,60 +176,25 @@ func (h Handlers) Transfer(ctx context.Context, w http.ResponseWriter, r *http.R
 		return err
 	}
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, d.Address)
-	if err != nil {
-		return err
-	}
-
-	if err := h.BlockchainSvc.TransferNFT(ctx, d.DID, req.ReceiverArr, privKey); err != nil {
-		return err
-	}
-
-	resp, err := h.Registry.GetPublicKey(ctx, &pbacc.GetPublicKeyRequest{
-		Address: req.ReceiverArr,
-	})
-	if err != nil {
-		return err
-	}
-
-	DIDDoc, err := h.Registry.Get(ctx, &diddoc.GetRequest{Did: d.DID})
-	if err != nil {
-		return err
-	}
-
-	vms := make([]*diddoc.VerificationMethod, 0)
-	authId := fmt.Sprintf("%s#keys-1", d.DID)
-
-	for _, doc := range DIDDoc.GetDocument().GetVerificationMethod() {
-		if doc.GetId() == authId {
-			doc.PublicKeyBase58 = resp.GetPubkey()
-		}
-
-		vms = append(vms, doc)
-	}
-
-	data := &diddoc.MsgSaveVerificationMethods_Data{
-		Did:                 d.DID,
-		AuthenticationKeyId: authId,
-		Authentication:      DIDDoc.Document.Authentication,
-		VerificationMethods: vms,
+	if ok, er := h.proposeMultisig(ctx, w, blockchain.UnsignedTxRequest{
+		Signer:   d.Address,
+		Type:     blockchain.TxMsgTransfer,
+		Receiver: req.ReceiverArr,
+		Device:   d,
+	}); ok || er != nil {
+		return er
 	}
 
-	hash, err := regapi.ProtoDeterministicChecksum(data)
+	signer, err := h.AccountSvc.GetAccountSigner(ctx, d.Address)
 	if err != nil {
 		return err
 	}
 
-	signature, err := privKey.Sign(hash[:])
-	if err != nil {
+	if err := h.BlockchainSvc.TransferNFT(ctx, d.DID, req.ReceiverArr, signer); err != nil {
 		return err
 	}
 
-	_, err = h.Registry.SaveVerificationMethods(ctx, &diddoc.MsgSaveVerificationMethods{
-		Data:      data,
-		Signature: signature,
-	})
-	if err != nil {
+	if err := h.DeviceSvc.HandOver(ctx, d.DID, req.ReceiverArr, signer); err != nil {
 		return err
 	}
 
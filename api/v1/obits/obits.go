@@ -10,6 +10,7 @@ import (
 
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/device"
 	"github.com/obada-foundation/client-helper/system/web"
 	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
@@ -43,16 +44,27 @@ func (h Handlers) Save(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		return fmt.Errorf("unable to decode request data: %w", err)
 	}
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, saveRequest.Address)
+	if saveRequest.Address == "" {
+		address, err := h.AccountSvc.MintingAccount(ctx)
+		if err != nil {
+			return err
+		}
+
+		saveRequest.Address = address
+	}
+
+	signer, err := h.AccountSvc.GetRegistrySigner(ctx, saveRequest.Address)
 	if err != nil {
 		return err
 	}
 
-	d, err := h.DeviceSvc.Save(ctx, saveRequest, privKey)
+	d, err := h.DeviceSvc.Save(ctx, saveRequest, signer)
 	if err != nil {
 		return err
 	}
 
+	audit.SetTarget(ctx, d.DID)
+
 	return web.Respond(ctx, w, d, http.StatusOK)
 }
 
@@ -64,11 +76,22 @@ func (h Handlers) BatchSave(ctx context.Context, w http.ResponseWriter, r *http.
 		return fmt.Errorf("unable to decode request data: %w", err)
 	}
 
+	if batchSaveRequest.Address == "" {
+		address, err := h.AccountSvc.MintingAccount(ctx)
+		if err != nil {
+			return err
+		}
+
+		batchSaveRequest.Address = address
+	}
+
+	audit.SetTarget(ctx, batchSaveRequest.Address)
+
 	numCPU := runtime.NumCPU()
 	errs := make(chan error, numCPU)
 	results := make(chan services.Device, numCPU)
 
-	privKey, err := h.AccountSvc.GetAccountPrivateKey(ctx, batchSaveRequest.Address)
+	signer, err := h.AccountSvc.GetRegistrySigner(ctx, batchSaveRequest.Address)
 	if err != nil {
 		return err
 	}
@@ -79,7 +102,7 @@ func (h Handlers) BatchSave(ctx context.Context, w http.ResponseWriter, r *http.
 		go func(i int, saveRequest services.SaveDevice) {
 			defer wg.Done()
 			saveRequest.Address = batchSaveRequest.Address
-			d, err := h.DeviceSvc.Save(ctx, saveRequest, privKey)
+			d, err := h.DeviceSvc.Save(ctx, saveRequest, signer)
 			if err != nil {
 				errs <- err
 				return
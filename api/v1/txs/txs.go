@@ -0,0 +1,144 @@
+package txs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// Handlers holds dependencies
+type Handlers struct {
+	AccountSvc    *account.Service
+	DeviceSvc     *device.Service
+	BlockchainSvc *blockchain.Service
+}
+
+// UnsignedTxRequest request data for building unsigned transaction
+type UnsignedTxRequest struct {
+	Signer   string `json:"signer"`
+	Type     string `json:"type"`
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+	Denom    string `json:"denom"`
+	DID      string `json:"did"`
+}
+
+// BroadcastTxRequest request data for broadcasting signed transaction
+type BroadcastTxRequest struct {
+	Tx      json.RawMessage `json:"tx"`
+	TxBytes []byte          `json:"tx_bytes"`
+}
+
+// BroadcastTxResponse response data of broadcasted transaction
+type BroadcastTxResponse struct {
+	Hash string `json:"hash"`
+}
+
+// UnsignedTx builds a transaction that should be signed outside of client-helper. The signer should be
+// the account of the profile, e.g. the watch-only account which key is held outside, and the device is looked up
+// among devices of the profile.
+func (h Handlers) UnsignedTx(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req UnsignedTxRequest
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	if req.Signer == "" {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "signer",
+				Error: "signer is required",
+			},
+		}
+	}
+
+	if ok := h.AccountSvc.HasAccount(ctx, req.Signer); !ok {
+		return auth.NewAuthError("permission denied")
+	}
+
+	utxReq := blockchain.UnsignedTxRequest{
+		Signer:   req.Signer,
+		Type:     req.Type,
+		Receiver: req.Receiver,
+		Amount:   req.Amount,
+		Denom:    req.Denom,
+	}
+
+	switch req.Type {
+	case blockchain.TxMsgMint, blockchain.TxMsgTransfer, blockchain.TxMsgUpdateURIHash:
+		if req.DID == "" {
+			return validate.FieldErrors{
+				validate.FieldError{
+					Field: "did",
+					Error: fmt.Sprintf("did is required for %q transaction", req.Type),
+				},
+			}
+		}
+
+		d, err := h.DeviceSvc.Get(ctx, req.DID)
+		if err != nil {
+			return err
+		}
+
+		utxReq.Device = d
+	case blockchain.TxMsgSend:
+	default:
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "type",
+				Error: fmt.Sprintf("unsupported transaction type %q", req.Type),
+			},
+		}
+	}
+
+	utx, err := h.BlockchainSvc.BuildUnsignedTx(ctx, utxReq)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, utx, http.StatusOK)
+}
+
+// Broadcast broadcasts a transaction signed outside of client-helper
+func (h Handlers) Broadcast(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req BroadcastTxRequest
+
+	if err := web.Decode(r, &req); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	var (
+		txData []byte
+		isJSON bool
+	)
+
+	switch {
+	case len(req.TxBytes) > 0:
+		txData = req.TxBytes
+	case len(req.Tx) > 0:
+		txData, isJSON = req.Tx, true
+	default:
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "tx",
+				Error: "either tx or tx_bytes is required",
+			},
+		}
+	}
+
+	hash, err := h.BlockchainSvc.BroadcastTx(ctx, txData, isJSON)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, BroadcastTxResponse{Hash: hash}, http.StatusCreated)
+}
@@ -5,14 +5,26 @@ import (
 
 	middleware "github.com/obada-foundation/client-helper/api/middleware/v1"
 	"github.com/obada-foundation/client-helper/api/v1/accounts"
+	"github.com/obada-foundation/client-helper/api/v1/admin"
+	auditapi "github.com/obada-foundation/client-helper/api/v1/audit"
+	"github.com/obada-foundation/client-helper/api/v1/events"
+	multisigapi "github.com/obada-foundation/client-helper/api/v1/multisig"
 	"github.com/obada-foundation/client-helper/api/v1/nft"
 	"github.com/obada-foundation/client-helper/api/v1/obit"
 	"github.com/obada-foundation/client-helper/api/v1/obits"
+	"github.com/obada-foundation/client-helper/api/v1/txs"
+	"github.com/obada-foundation/client-helper/api/v1/webhooks"
 	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events/handlers"
+	"github.com/obada-foundation/client-helper/events/stream"
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/blockchain"
 	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/services/migration"
+	"github.com/obada-foundation/client-helper/services/multisig"
+	"github.com/obada-foundation/client-helper/services/webhook"
 	"github.com/obada-foundation/client-helper/system/web"
 	"github.com/obada-foundation/registry/client"
 	"go.uber.org/zap"
@@ -29,6 +41,14 @@ type Config struct {
 	DeviceSvc     *device.Service
 	ObitSvc       *services.ObitService
 	Registry      client.Client
+	WebhookSvc    *webhook.Service
+	AuditSvc      *audit.Service
+	MultisigSvc   *multisig.Service
+	MigrationSvc  *migration.Service
+
+	// Events
+	EventHub     *stream.Hub
+	EventManager *handlers.EventManager
 }
 
 // Routes binds all the version 1 routes.
@@ -37,25 +57,68 @@ func Routes(app *web.App, cfg Config) {
 
 	authenticate := middleware.Authenticate(cfg.Auth)
 	accountMw := middleware.Account(cfg.AccountSvc)
+	signing := middleware.SigningPassphrase()
+	adminOnly := middleware.Authorize(cfg.Auth, auth.RuleAdminOnly)
+
+	audited := func(action string) web.Middleware {
+		return middleware.Audit(cfg.AuditSvc, action)
+	}
 
 	accountsGrp := accounts.Handlers{
 		AccountSvc:    cfg.AccountSvc,
 		BlockchainSvc: cfg.BlockchainSvc,
+		AuditSvc:      cfg.AuditSvc,
+		MigrationSvc:  cfg.MigrationSvc,
 	}
 
 	app.Handle(http.MethodGet, version, "/accounts", accountsGrp.Accounts, authenticate)
 	app.Handle(http.MethodPost, version, "/accounts/register", accountsGrp.Register, authenticate)
-	app.Handle(http.MethodPost, version, "/accounts/new-wallet", accountsGrp.NewWallet, authenticate)
-	app.Handle(http.MethodPost, version, "/accounts/import-wallet", accountsGrp.ImportWallet, authenticate)
-	app.Handle(http.MethodPost, version, "/accounts/new-account", accountsGrp.NewAccount, authenticate)
-	app.Handle(http.MethodPost, version, "/accounts/import-account", accountsGrp.ImportAccount, authenticate)
-	app.Handle(http.MethodPost, version, "/accounts/export-account", accountsGrp.ExportAccount, authenticate)
+	app.Handle(http.MethodPost, version, "/accounts/new-wallet", accountsGrp.NewWallet, authenticate, audited(audit.ActionWalletCreate))
+	app.Handle(http.MethodPost, version, "/accounts/import-wallet", accountsGrp.ImportWallet,
+		authenticate, audited(audit.ActionWalletImport))
+	app.Handle(http.MethodPost, version, "/accounts/multisig", accountsGrp.CreateMultisig,
+		authenticate, audited(audit.ActionMultisigCreate))
+	app.Handle(http.MethodPost, version, "/accounts/watch", accountsGrp.AddWatchAccount, authenticate, audited(audit.ActionAccountWatch))
+	app.Handle(http.MethodPost, version, "/accounts/rescan", accountsGrp.Rescan, authenticate, audited(audit.ActionWalletRescan))
+	app.Handle(http.MethodGet, version, "/accounts/wallet-rotation", accountsGrp.WalletRotation, authenticate)
+	app.Handle(http.MethodPost, version, "/accounts/wallet-rotation", accountsGrp.StartWalletRotation,
+		authenticate, audited(audit.ActionWalletRotate), signing)
+	app.Handle(http.MethodPost, version, "/accounts/wallet-rotation/resume", accountsGrp.ResumeWalletRotation,
+		authenticate, audited(audit.ActionWalletRotateResume), signing)
+	app.Handle(http.MethodPost, version, "/accounts/new-account", accountsGrp.NewAccount, authenticate, audited(audit.ActionAccountCreate))
+	app.Handle(http.MethodPost, version, "/accounts/import-account", accountsGrp.ImportAccount,
+		authenticate, audited(audit.ActionAccountImport))
+	app.Handle(http.MethodPost, version, "/accounts/export-account", accountsGrp.ExportAccount,
+		authenticate, audited(audit.ActionAccountExport), signing)
 	app.Handle(http.MethodGet, version, "/accounts/new-mnemonic", accountsGrp.NewMnemonic, authenticate)
-	app.Handle(http.MethodGet, version, "/accounts/mnemonic", accountsGrp.Mnemonic, authenticate)
+	app.Handle(http.MethodGet, version, "/accounts/mnemonic", accountsGrp.Mnemonic,
+		authenticate, audited(audit.ActionWalletMnemonicShow), signing)
+	app.Handle(http.MethodPut, version, "/accounts/signing-passphrase", accountsGrp.SetSigningPassphrase,
+		authenticate, audited(audit.ActionSigningPassphraseSet), signing)
+	app.Handle(http.MethodDelete, version, "/accounts/signing-passphrase", accountsGrp.RemoveSigningPassphrase,
+		authenticate, audited(audit.ActionSigningPassphraseRemove), signing)
 	app.Handle(http.MethodGet, version, "/accounts/:address", accountsGrp.Account, authenticate, accountMw)
 	app.Handle(http.MethodPost, version, "/accounts/:address", accountsGrp.UpdateAccount, authenticate, accountMw)
-	app.Handle(http.MethodDelete, version, "/accounts/:address", accountsGrp.DeleteAccount, authenticate, accountMw)
-	app.Handle(http.MethodPost, version, "/accounts/:address/send-coins", accountsGrp.SendCoins, authenticate, accountMw)
+	app.Handle(http.MethodDelete, version, "/accounts/:address", accountsGrp.DeleteAccount,
+		authenticate, accountMw, audited(audit.ActionAccountDelete), signing)
+	app.Handle(http.MethodGet, version, "/accounts/:address/deletion-check", accountsGrp.DeletionCheck, authenticate, accountMw)
+	app.Handle(http.MethodPost, version, "/accounts/:address/send-coins", accountsGrp.SendCoins,
+		authenticate, accountMw, audited(audit.ActionCoinsSend), signing)
+	app.Handle(http.MethodPut, version, "/accounts/:address/signing-passphrase", accountsGrp.SetSigningPassphrase,
+		authenticate, accountMw, audited(audit.ActionSigningPassphraseSet), signing)
+	app.Handle(http.MethodDelete, version, "/accounts/:address/signing-passphrase", accountsGrp.RemoveSigningPassphrase,
+		authenticate, accountMw, audited(audit.ActionSigningPassphraseRemove), signing)
+	app.Handle(http.MethodGet, version, "/accounts/:address/txs", accountsGrp.Txs, authenticate, accountMw)
+	app.Handle(http.MethodGet, version, "/accounts/:address/allowance", accountsGrp.Allowance, authenticate, accountMw)
+	app.Handle(http.MethodPost, version, "/accounts/:address/allowance", accountsGrp.GrantAllowance,
+		authenticate, adminOnly, audited(audit.ActionAllowanceGrant))
+	app.Handle(http.MethodDelete, version, "/accounts/:address/allowance", accountsGrp.RevokeAllowance,
+		authenticate, adminOnly, audited(audit.ActionAllowanceRevoke))
+	app.Handle(http.MethodGet, version, "/accounts/:address/authz", accountsGrp.AuthzGrants, authenticate, accountMw)
+	app.Handle(http.MethodPost, version, "/accounts/:address/authz", accountsGrp.GrantAuthz,
+		authenticate, accountMw, audited(audit.ActionAuthzGrant), signing)
+	app.Handle(http.MethodDelete, version, "/accounts/:address/authz", accountsGrp.RevokeAuthz,
+		authenticate, accountMw, audited(audit.ActionAuthzRevoke), signing)
 
 	obitsGrp := obits.Handlers{
 		AccountSvc: cfg.AccountSvc,
@@ -66,8 +129,8 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodGet, version, "/obits/:key", obitsGrp.Obit, authenticate)
 	app.Handle(http.MethodGet, version, "/obits/:key/history", obitsGrp.History, authenticate)
 	app.Handle(http.MethodGet, version, "/obits", obitsGrp.Search, authenticate)
-	app.Handle(http.MethodPost, version, "/obits", obitsGrp.Save, authenticate)
-	app.Handle(http.MethodPost, version, "/obits/batch", obitsGrp.BatchSave, authenticate)
+	app.Handle(http.MethodPost, version, "/obits", obitsGrp.Save, authenticate, audited(audit.ActionObitSave), signing)
+	app.Handle(http.MethodPost, version, "/obits/batch", obitsGrp.BatchSave, authenticate, audited(audit.ActionObitSave), signing)
 
 	obitGrp := obit.Handlers{
 		ObitSvc: cfg.ObitSvc,
@@ -80,12 +143,68 @@ func Routes(app *web.App, cfg Config) {
 		AccountSvc:    cfg.AccountSvc,
 		DeviceSvc:     cfg.DeviceSvc,
 		BlockchainSvc: cfg.BlockchainSvc,
+		MultisigSvc:   cfg.MultisigSvc,
 		Registry:      cfg.Registry,
 	}
 
 	app.Handle(http.MethodGet, version, "/nft/:key", nftGrp.NFT, authenticate)
-	app.Handle(http.MethodPost, version, "/nft/:key/mint", nftGrp.Mint, authenticate)
-	app.Handle(http.MethodPost, version, "/nft/batch-mint", nftGrp.BatchMint, authenticate)
-	app.Handle(http.MethodPost, version, "/nft/:key/metadata", nftGrp.UpdateMetadata, authenticate)
-	app.Handle(http.MethodPost, version, "/nft/:key/send", nftGrp.Transfer, authenticate)
+	app.Handle(http.MethodPost, version, "/nft/:key/mint", nftGrp.Mint, authenticate, audited(audit.ActionNFTMint), signing)
+	app.Handle(http.MethodPost, version, "/nft/batch-mint", nftGrp.BatchMint, authenticate, audited(audit.ActionNFTMint), signing)
+	app.Handle(http.MethodPost, version, "/nft/:key/metadata", nftGrp.UpdateMetadata,
+		authenticate, audited(audit.ActionNFTMetadataUpdate), signing)
+	app.Handle(http.MethodPost, version, "/nft/:key/send", nftGrp.Transfer, authenticate, audited(audit.ActionNFTTransfer), signing)
+
+	multisigGrp := multisigapi.Handlers{
+		AccountSvc:  cfg.AccountSvc,
+		DeviceSvc:   cfg.DeviceSvc,
+		MultisigSvc: cfg.MultisigSvc,
+	}
+
+	app.Handle(http.MethodGet, version, "/multisig-txs", multisigGrp.Txs, authenticate)
+	app.Handle(http.MethodGet, version, "/multisig-txs/:id", multisigGrp.Tx, authenticate)
+	app.Handle(http.MethodPost, version, "/multisig-txs/:id/sign", multisigGrp.Sign,
+		authenticate, audited(audit.ActionMultisigTxSign), signing)
+	app.Handle(http.MethodPost, version, "/multisig-txs/:id/broadcast", multisigGrp.Broadcast,
+		authenticate, audited(audit.ActionMultisigTxBroadcast), signing)
+	app.Handle(http.MethodPost, version, "/multisig-txs/:id/hand-over", multisigGrp.HandOver,
+		authenticate, audited(audit.ActionMultisigTxHandOver), signing)
+	app.Handle(http.MethodDelete, version, "/multisig-txs/:id", multisigGrp.Cancel, authenticate, audited(audit.ActionMultisigTxCancel))
+
+	txsGrp := txs.Handlers{
+		AccountSvc:    cfg.AccountSvc,
+		DeviceSvc:     cfg.DeviceSvc,
+		BlockchainSvc: cfg.BlockchainSvc,
+	}
+
+	app.Handle(http.MethodPost, version, "/txs/unsigned", txsGrp.UnsignedTx, authenticate)
+	app.Handle(http.MethodPost, version, "/txs/broadcast", txsGrp.Broadcast, authenticate, audited(audit.ActionTxBroadcast))
+
+	eventsGrp := events.Handlers{
+		Hub: cfg.EventHub,
+	}
+
+	app.Handle(http.MethodGet, version, "/events/stream", eventsGrp.Stream, authenticate)
+
+	webhooksGrp := webhooks.Handlers{
+		WebhookSvc: cfg.WebhookSvc,
+	}
+
+	app.Handle(http.MethodGet, version, "/webhooks", webhooksGrp.Webhooks, authenticate)
+	app.Handle(http.MethodPost, version, "/webhooks", webhooksGrp.Create, authenticate)
+	app.Handle(http.MethodGet, version, "/webhooks/:id", webhooksGrp.Webhook, authenticate)
+	app.Handle(http.MethodDelete, version, "/webhooks/:id", webhooksGrp.Delete, authenticate)
+	app.Handle(http.MethodGet, version, "/webhooks/:id/deliveries", webhooksGrp.Deliveries, authenticate)
+
+	auditGrp := auditapi.Handlers{
+		AuditSvc: cfg.AuditSvc,
+	}
+
+	app.Handle(http.MethodGet, version, "/audit", auditGrp.Entries, authenticate)
+
+	adminGrp := admin.Handlers{
+		EventManager: cfg.EventManager,
+	}
+
+	app.Handle(http.MethodGet, version, "/admin/events/failures", adminGrp.EventFailures, authenticate, adminOnly)
+	app.Handle(http.MethodPost, version, "/admin/events/retry", adminGrp.RetryEvents, authenticate, adminOnly)
 }
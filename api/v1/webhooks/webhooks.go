@@ -0,0 +1,81 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	appErrors "github.com/obada-foundation/client-helper/api/errors"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/webhook"
+	"github.com/obada-foundation/client-helper/system/web"
+)
+
+// Handlers holds dependencies
+type Handlers struct {
+	WebhookSvc *webhook.Service
+}
+
+// Create registers a new webhook of the profile
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var saveRequest services.SaveWebhook
+
+	if err := web.Decode(r, &saveRequest); err != nil {
+		return fmt.Errorf("unable to decode request data: %w", err)
+	}
+
+	wh, err := h.WebhookSvc.Create(ctx, saveRequest)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, wh, http.StatusCreated)
+}
+
+// Webhooks returns webhooks of the profile
+func (h Handlers) Webhooks(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	webhooks, err := h.WebhookSvc.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, webhooks, http.StatusOK)
+}
+
+// Webhook returns the profile webhook
+func (h Handlers) Webhook(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	wh, err := h.WebhookSvc.Get(ctx, web.Param(r, "id"))
+	if err != nil {
+		return notFound(err)
+	}
+
+	return web.Respond(ctx, w, wh, http.StatusOK)
+}
+
+// Delete deletes the profile webhook
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if err := h.WebhookSvc.Delete(ctx, web.Param(r, "id")); err != nil {
+		return notFound(err)
+	}
+
+	return web.RespondWithNoContent(ctx, w, http.StatusNoContent)
+}
+
+// Deliveries returns the delivery log of the webhook
+func (h Handlers) Deliveries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	deliveries, err := h.WebhookSvc.Deliveries(ctx, web.Param(r, "id"))
+	if err != nil {
+		return notFound(err)
+	}
+
+	return web.Respond(ctx, w, deliveries, http.StatusOK)
+}
+
+func notFound(err error) error {
+	if errors.Is(err, webhook.ErrWebhookNotExists) {
+		return appErrors.NewRequestError(err, http.StatusNotFound)
+	}
+
+	return err
+}
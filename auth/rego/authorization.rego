@@ -2,9 +2,11 @@ package obada.rego
 
 default allowAny = false
 default allowOnlyUser = false
+default allowOnlyAdmin = false
 
 roleUser := "USER"
-roleAll := {roleUser}
+roleAdmin := "ADMIN"
+roleAll := {roleUser, roleAdmin}
 
 allowAny {
 	roles_from_claims := {role | role := input.Roles[_]}
@@ -17,3 +19,9 @@ allowOnlyUser {
 	input_role_is_in_claim := {roleUser} & roles_from_claims
 	count(input_role_is_in_claim) > 0
 }
+
+allowOnlyAdmin {
+	roles_from_claims := {role | role := input.Roles[_]}
+	input_role_is_in_claim := {roleAdmin} & roles_from_claims
+	count(input_role_is_in_claim) > 0
+}
@@ -9,6 +9,7 @@ const (
 	RuleAuthenticate = "auth"
 	RuleAny          = "allowAny"
 	RuleUserOnly     = "allowOnlyUser"
+	RuleAdminOnly    = "allowOnlyAdmin"
 )
 
 // Package name of our rego code.
@@ -8,10 +8,15 @@ import (
 	"github.com/mustafaturan/monoton/v3/sequencer"
 )
 
+// epoch is subtracted from the time part of event IDs, it is fixed so IDs keep growing across restarts
+var epoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
 // NewBus creating new bus instance
 func NewBus() (*bus.Bus, error) {
 	node := uint64(1)
-	initialTime := uint64(time.Now().UnixNano())
+
+	// the initial time is in units of the sequencer
+	initialTime := uint64(epoch.UnixMilli())
 	m, err := monoton.New(sequencer.NewMillisecond(), node, initialTime)
 	if err != nil {
 		return nil, err
@@ -26,3 +26,9 @@ func (c *CommonOpts) SetCommon(commonOpts CommonOpts) {
 	c.Logger = commonOpts.Logger
 	c.DB = commonOpts.DB
 }
+
+// OfflineCommander is implemented by commands that can run on the air-gapped machine
+// and don't need access to the database
+type OfflineCommander interface {
+	Offline() bool
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	dkeyring "github.com/99designs/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	chkeyring "github.com/obada-foundation/client-helper/system/keyring"
+)
+
+// KeyringCommand groups keyring subcommands
+type KeyringCommand struct {
+	Migrate KeyringMigrateCommand `command:"migrate" description:"copy keys of the test backend into the file backend"`
+}
+
+// KeyringMigrateCommand re-imports keys stored unencrypted by the test backend into the encrypted file backend
+type KeyringMigrateCommand struct {
+	Keyring      KeyringGroup `group:"keyring" namespace:"keyring" env-namespace:"KEYRING"`
+	FromDir      string       `long:"from-dir" description:"directory of the test backend, the keyring directory by default"`
+	DeleteSource bool         `long:"delete-source" description:"delete keys from the test backend after the migration"`
+
+	CommonOpts
+}
+
+// Offline satisfies OfflineCommander interface
+func (k *KeyringMigrateCommand) Offline() bool {
+	return true
+}
+
+// Execute is the entry point for "keyring migrate" command, called by flag parser
+func (k *KeyringMigrateCommand) Execute(_ []string) error {
+	if k.Keyring.Backend != chkeyring.BackendFile {
+		return fmt.Errorf("keys are migrated into the %s backend, got %s", chkeyring.BackendFile, k.Keyring.Backend)
+	}
+
+	fromDir := k.FromDir
+	if fromDir == "" {
+		fromDir = k.Keyring.Dir
+	}
+
+	from, err := chkeyring.New(chkeyring.BackendTest, fromDir, "")
+	if err != nil {
+		return fmt.Errorf("cannot open test keyring: %w", err)
+	}
+
+	to, err := k.Keyring.open()
+	if err != nil {
+		return fmt.Errorf("cannot open file keyring: %w", err)
+	}
+
+	migrated, err := chkeyring.Migrate(from, to)
+	if err != nil {
+		return err
+	}
+
+	k.Logger.Infow("keyring migrated", "keys", len(migrated))
+
+	if !k.DeleteSource {
+		return nil
+	}
+
+	// only keys that are in the file backend now, migrated earlier or right now, are deleted
+	for _, name := range migrated {
+		if err := from.Delete(name); err != nil {
+			return fmt.Errorf("cannot delete key %q from test keyring: %w", name, err)
+		}
+	}
+
+	k.Logger.Infow("test keyring keys deleted", "keys", len(migrated))
+
+	return nil
+}
+
+// open opens the keyring of the configured backend, the test backend requires the dev flag
+func (k KeyringGroup) open() (keyring.Keyring, error) {
+	if k.Backend == chkeyring.BackendTest {
+		if !k.Dev {
+			return nil, errors.New("test keyring backend stores keys unencrypted, it is allowed only with --keyring.dev")
+		}
+
+		return chkeyring.New(chkeyring.BackendTest, k.Dir, "")
+	}
+
+	passphrase, err := k.passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	return chkeyring.New(k.Backend, k.Dir, passphrase)
+}
+
+// checkMigrated fails when the file backend starts empty next to keys of the test backend, so the upgraded
+// deployment does not run with accounts that resolve to missing keys
+func (k KeyringGroup) checkMigrated(kr keyring.Keyring) error {
+	if k.Backend != chkeyring.BackendFile {
+		return nil
+	}
+
+	if err := chkeyring.CheckMigrated(k.Dir, kr); err != nil {
+		if errors.Is(err, chkeyring.ErrNotMigrated) {
+			return fmt.Errorf("%w, run \"keyring migrate\" to copy them into the %s backend or start with --keyring.backend=%s --keyring.dev",
+				err, chkeyring.BackendFile, chkeyring.BackendTest)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// openWallets opens the store of HD wallet mnemonics of the keyring
+func (k KeyringGroup) openWallets() (dkeyring.Keyring, error) {
+	if k.Backend == chkeyring.BackendTest {
+		return chkeyring.OpenWallets(chkeyring.BackendTest, k.Dir, "")
+	}
+
+	passphrase, err := k.passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	return chkeyring.OpenWallets(k.Backend, k.Dir, passphrase)
+}
+
+// passphrase reads the passphrase from the secret file, or takes it from the option
+func (k KeyringGroup) passphrase() (string, error) {
+	if k.PassphraseFile == "" {
+		if k.Passphrase == "" {
+			return "", errors.New("keyring passphrase is not configured, please use --keyring.passphrase-file")
+		}
+
+		return k.Passphrase, nil
+	}
+
+	b, err := os.ReadFile(k.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("cannot read keyring passphrase: %w", err)
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,28 +10,29 @@ import (
 	"syscall"
 	"time"
 
-	tmjson "github.com/cometbft/cometbft/libs/json"
-	ctypes "github.com/cometbft/cometbft/rpc/core/types"
-	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
-	tmtypes "github.com/cometbft/cometbft/types"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
-	"github.com/cosmos/cosmos-sdk/types/module/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/getsentry/sentry-go"
-	eb "github.com/mustafaturan/bus/v3"
 	"github.com/obada-foundation/client-helper/api"
 	"github.com/obada-foundation/client-helper/auth"
 	"github.com/obada-foundation/client-helper/bus"
-	"github.com/obada-foundation/client-helper/events"
 	"github.com/obada-foundation/client-helper/events/handlers"
+	"github.com/obada-foundation/client-helper/events/listener"
+	"github.com/obada-foundation/client-helper/events/sinks"
+	"github.com/obada-foundation/client-helper/events/stream"
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/services/blockchain"
 	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/services/migration"
+	"github.com/obada-foundation/client-helper/services/multisig"
 	"github.com/obada-foundation/client-helper/services/pubkey"
+	"github.com/obada-foundation/client-helper/services/webhook"
 	"github.com/obada-foundation/client-helper/system/ipfs"
 	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
-	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
 	registry "github.com/obada-foundation/registry/client"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -54,6 +56,14 @@ type ServerCommand struct {
 	Node            NodeGroup     `group:"node" namespace:"node" env-namespace:"NODE"`
 	IPFS            IPFSGroup     `group:"ipfs" namespace:"ipfs" env-namespace:"IPFS"`
 	Keyring         KeyringGroup  `group:"keyring" namespace:"keyring" env-namespace:"KEYRING"`
+	Signer          SignerGroup   `group:"signer" namespace:"signer" env-namespace:"SIGNER"`
+	Sponsor         SponsorGroup  `group:"sponsor" namespace:"sponsor" env-namespace:"SPONSOR"`
+	Accounts        AccountsGroup `group:"accounts" namespace:"accounts" env-namespace:"ACCOUNTS"`
+	EventStream     StreamGroup   `group:"event-stream" namespace:"event-stream" env-namespace:"EVENT_STREAM"`
+	Webhooks        WebhooksGroup `group:"webhooks" namespace:"webhooks" env-namespace:"WEBHOOKS"`
+	NATS            NATSGroup     `group:"nats" namespace:"nats" env-namespace:"NATS"`
+	Kafka           KafkaGroup    `group:"kafka" namespace:"kafka" env-namespace:"KAFKA"`
+	EventHandlers   HandlersGroup `group:"event-handlers" namespace:"event-handlers" env-namespace:"EVENT_HANDLERS"`
 
 	CommonOpts
 }
@@ -63,11 +73,49 @@ type RedisGroup struct {
 	Addr     string `long:"addr" env:"ADDR" default:"redis:6379" description:"redis address"`
 	Password string `long:"password" env:"PASSWORD" default:"" description:"redis password"`
 	DB       int    `long:"db" env:"DB" default:"0" description:"redis db"`
+
+	Sink         string `long:"sink" env:"SINK" choice:"pubsub" choice:"streams" choice:"both" choice:"none" default:"pubsub" description:"event sink"` // nolint
+	Stream       string `long:"stream" env:"STREAM" default:"client-helper:events" description:"stream of the events"`
+	StreamMaxLen int64  `long:"stream-max-len" env:"STREAM_MAX_LEN" default:"100000" description:"approximate length of the stream"`
+}
+
+// NATSGroup defines options of the NATS event sink
+type NATSGroup struct {
+	URL           string       `long:"url" env:"URL" default:"" description:"NATS url, empty disables the sink"`
+	SubjectPrefix string       `long:"subject-prefix" env:"SUBJECT_PREFIX" default:"client-helper" description:"prefix of the event subjects"`
+	JetStream     bool         `long:"jetstream" env:"JETSTREAM" description:"wait for the JetStream acknowledgement"`
+	CredsFile     string       `long:"creds" env:"CREDS" description:"credentials file with the user JWT and NKey seed"`
+	TLS           SinkTLSGroup `group:"tls" namespace:"tls" env-namespace:"TLS"`
+}
+
+// KafkaGroup defines options of the Kafka event sink
+type KafkaGroup struct {
+	Brokers      []string     `long:"brokers" env:"BROKERS" env-delim:"," description:"bootstrap brokers, empty disables the sink"`
+	Topic        string       `long:"topic" env:"TOPIC" default:"client-helper.events" description:"topic of the events"`
+	SASLUser     string       `long:"sasl-user" env:"SASL_USER" description:"SASL PLAIN user, empty disables the authentication"`
+	SASLPassword string       `long:"sasl-password" env:"SASL_PASSWORD" description:"SASL PLAIN password"`
+	TLS          SinkTLSGroup `group:"tls" namespace:"tls" env-namespace:"TLS"`
+}
+
+// SinkTLSGroup defines TLS options of the event sink connection
+type SinkTLSGroup struct {
+	Enabled bool   `long:"enabled" env:"ENABLED" description:"connect with TLS"`
+	CA      string `long:"ca" env:"CA" description:"CA file that verifies the server certificate instead of system roots"`
+	Cert    string `long:"cert" env:"CERT" description:"client certificate file of the mutual TLS"`
+	Key     string `long:"key" env:"KEY" description:"client key file of the mutual TLS"`
+}
+
+func (g SinkTLSGroup) config() sinks.TLSConfig {
+	return sinks.TLSConfig{Enabled: g.Enabled, CA: g.CA, Cert: g.Cert, Key: g.Key}
 }
 
 // KeyringGroup keyring config options
 type KeyringGroup struct {
-	Dir string `long:"dir" env:"DIR" default:"/home/obada/keyring" description:"keyring directory"`
+	Dir            string `long:"dir" env:"DIR" default:"/home/obada/keyring" description:"keyring directory"`
+	Backend        string `long:"backend" env:"BACKEND" choice:"file" choice:"test" default:"file" description:"keyring backend"` // nolint
+	Passphrase     string `long:"passphrase" env:"PASSPHRASE" description:"passphrase of the file backend"`
+	PassphraseFile string `long:"passphrase-file" env:"PASSPHRASE_FILE" description:"file with the passphrase of the file backend"`
+	Dev            bool   `long:"dev" env:"DEV" description:"allow the test backend that stores keys unencrypted"`
 }
 
 // AuthGroup auth config options
@@ -88,6 +136,46 @@ type NodeGroup struct {
 	ChainID string `long:"chain-id" env:"CHAIN_ID" description:"" default:"obada-testnet"`
 	RPCURL  string `long:"rpc-url" env:"RPC_URL" description:"" default:"tcp://52.206.218.105:26657"`
 	GrpcURL string `long:"grpc-url" env:"GRPC_URL" description:"" default:"52.206.218.105:9090"`
+
+	EventSource  string        `long:"event-source" env:"EVENT_SOURCE" choice:"websocket" choice:"polling" default:"websocket" description:"tx source"` // nolint
+	PollInterval time.Duration `long:"poll-interval" env:"POLL_INTERVAL" default:"5s" description:"interval of the polling event source"`
+}
+
+// SponsorGroup defines options of the treasury account that pays fees for the managed accounts
+type SponsorGroup struct {
+	Key        string        `long:"key" env:"KEY" default:"" description:"treasury keyring key or address, empty disables sponsor mode"`
+	SpendLimit string        `long:"spend-limit" env:"SPEND_LIMIT" default:"" description:"spend limit of auto granted allowance, required with auto-grant"`
+	Expiration time.Duration `long:"expiration" env:"EXPIRATION" default:"0s" description:"expiration of auto granted allowance"`
+	AutoGrant  bool          `long:"auto-grant" env:"AUTO_GRANT" description:"grant allowance on the first sponsored tx"`
+}
+
+// AccountsGroup defines options of the HD wallet accounts
+type AccountsGroup struct {
+	GapLimit uint `long:"gap-limit" env:"GAP_LIMIT" default:"20" description:"how many unused accounts in a row end the discovery"`
+}
+
+// StreamGroup defines options of the client event stream
+type StreamGroup struct {
+	BufferSize int `long:"buffer-size" env:"BUFFER_SIZE" default:"1000" description:"how many latest events are kept for resume"`
+}
+
+// HandlersGroup defines options of the event handlers worker pool
+type HandlersGroup struct {
+	Workers     int           `long:"workers" env:"WORKERS" default:"4" description:"how many events are handled concurrently"`
+	QueueSize   int           `long:"queue-size" env:"QUEUE_SIZE" default:"1000" description:"how many events wait for a worker"`
+	Timeout     time.Duration `long:"timeout" env:"TIMEOUT" default:"1m" description:"timeout of the handler attempt"`
+	MaxAttempts int           `long:"max-attempts" env:"MAX_ATTEMPTS" default:"5" description:"attempts before the event is kept as failure"`
+	MinBackoff  time.Duration `long:"min-backoff" env:"MIN_BACKOFF" default:"1s" description:"delay before the first retry"`
+	MaxBackoff  time.Duration `long:"max-backoff" env:"MAX_BACKOFF" default:"1m" description:"maximum delay between retries"`
+}
+
+// WebhooksGroup defines options of the webhook deliveries
+type WebhooksGroup struct {
+	MaxAttempts int           `long:"max-attempts" env:"MAX_ATTEMPTS" default:"10" description:"attempts before the delivery is dead-lettered"`
+	MinBackoff  time.Duration `long:"min-backoff" env:"MIN_BACKOFF" default:"10s" description:"delay before the first retry"`
+	MaxBackoff  time.Duration `long:"max-backoff" env:"MAX_BACKOFF" default:"1h" description:"maximum delay between retries"`
+
+	AllowedHosts []string `long:"allowed-host" env:"ALLOWED_HOSTS" env-delim:"," description:"hosts that may use http and private addresses"`
 }
 
 // IPFSGroup defines options for connection to the IPFS node
@@ -132,14 +220,46 @@ func (s *ServerCommand) Execute(_ []string) error {
 		return err
 	}
 
-	kr, err := keyring.New("client-helper", keyring.BackendTest, s.Keyring.Dir, nil, testutil.MakeTestEncodingConfig().Codec)
+	kr, err := s.Keyring.open()
 	if err != nil {
 		return fmt.Errorf("creating keyring error: %w", err)
 	}
 
+	if err := s.Keyring.checkMigrated(kr); err != nil {
+		return err
+	}
+
 	accountSvc := account.NewService(validator, s.DB, nodeClient, kr, eventBus)
+	accountSvc.SetGapLimit(s.Accounts.GapLimit)
+
+	var signers signer.Provider = signer.NewKeyring(kr)
+
+	if s.Signer.isRemote() {
+		remote, er := s.Signer.makeRemote()
+		if er != nil {
+			return fmt.Errorf("initialize signer: %w", er)
+		}
+
+		// keys of the accounts are generated, imported and kept by the signer
+		accountSvc.SetRemoteSigner(remote)
+		signers = remote
+	}
+
+	s.Logger.Infow("startup", "status", "signer initialized", "signer", s.Signer.Type)
+
 	blockchainSvc := blockchain.NewService(nodeClient, s.Logger, s.Registry.HTTPUrl)
 
+	if s.Sponsor.Key != "" {
+		sponsor, er := s.makeSponsor(ctx, kr, signers)
+		if er != nil {
+			return fmt.Errorf("initialize sponsor: %w", er)
+		}
+
+		blockchainSvc.SetSponsor(sponsor)
+
+		s.Logger.Infow("startup", "status", "sponsor mode enabled", "sponsor", signer.Address(sponsor.Signer).String())
+	}
+
 	// IPFS client init
 	ipfsShell := ipfs.NewIPFS(s.IPFS.RPCURL)
 
@@ -180,16 +300,71 @@ func (s *ServerCommand) Execute(_ []string) error {
 		DB:       s.Redis.DB,
 	})
 
+	eventSink, err := s.makeEventSink(rdb)
+	if err != nil {
+		return fmt.Errorf("initialize event sink: %w", err)
+	}
+
 	// Initialize system events
-	handlers.Initialize(handlers.Config{
+	eventManager := handlers.Initialize(handlers.Config{
+		Bus:      eventBus,
+		Logger:   s.Logger,
+		Sink:     eventSink,
+		Registry: regClient,
+		DB:       s.DB,
+
+		Workers:     s.EventHandlers.Workers,
+		QueueSize:   s.EventHandlers.QueueSize,
+		Timeout:     s.EventHandlers.Timeout,
+		MaxAttempts: s.EventHandlers.MaxAttempts,
+		MinBackoff:  s.EventHandlers.MinBackoff,
+		MaxBackoff:  s.EventHandlers.MaxBackoff,
+
+		AccountSvc:    accountSvc,
+		DeviceSvc:     deviceSvc,
+		BlockchainSvc: blockchainSvc,
+	})
+
+	// Event hub pushes events to the connected clients
+	eventHub := stream.NewHub(stream.Config{
+		Bus:        eventBus,
+		Logger:     s.Logger,
+		BufferSize: s.EventStream.BufferSize,
+	})
+
+	// Webhooks deliver events to the profile URLs
+	webhookSvc := webhook.NewService(webhook.Config{
+		Validator:   validator,
+		DB:          s.DB,
 		Bus:         eventBus,
 		Logger:      s.Logger,
-		RedisClient: rdb,
-		Registry:    regClient,
-		Keyring:     kr,
+		MaxAttempts: s.Webhooks.MaxAttempts,
+		MinBackoff:  s.Webhooks.MinBackoff,
+		MaxBackoff:  s.Webhooks.MaxBackoff,
 
-		DeviceSvc:     deviceSvc,
+		AllowedHosts: s.Webhooks.AllowedHosts,
+	})
+
+	// Audit log records state-changing operations of the profiles
+	auditSvc := audit.NewService(audit.Config{
+		DB:     s.DB,
+		Logger: s.Logger,
+	})
+
+	// Multisig transactions wait for signatures of co-signers
+	multisigSvc := multisig.NewService(multisig.Config{
+		DB:            s.DB,
+		BlockchainSvc: blockchainSvc,
+		Logger:        s.Logger,
+	})
+
+	// Migrations move NFTs and coins between accounts of the profile and rotate the wallet
+	migrationSvc := migration.NewService(migration.Config{
+		DB:            s.DB,
+		AccountSvc:    accountSvc,
 		BlockchainSvc: blockchainSvc,
+		DeviceSvc:     deviceSvc,
+		Logger:        s.Logger,
 	})
 
 	// Auth manager verifies JWT tokens
@@ -201,16 +376,22 @@ func (s *ServerCommand) Execute(_ []string) error {
 		return err
 	}
 
-	wsClient, err := s.makeWsClient(ctx, wsClientConfig{
-		nodeClient: nodeClient,
-		deviceSvc:  deviceSvc,
-		accountSvc: accountSvc,
-		obitSvc:    obitSvc,
-		bus:        eventBus,
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	chainListener := listener.New(listener.Config{
+		Source:     s.makeEventSource(nodeClient),
+		DB:         s.DB,
+		Logger:     s.Logger,
+		NodeClient: nodeClient,
+		DeviceSvc:  deviceSvc,
+		AccountSvc: accountSvc,
+		Bus:        eventBus,
 	})
-	if err != nil {
-		return fmt.Errorf("cannot initialize node ws client: %w", err)
-	}
+
+	go eventManager.Run(workersCtx)
+	go chainListener.Run(workersCtx)
+	go webhookSvc.Run(workersCtx)
 
 	apiServer := s.makeAPIServer(api.APIMuxConfig{
 		Shutdown: shutdown,
@@ -222,8 +403,17 @@ func (s *ServerCommand) Execute(_ []string) error {
 		DeviceSvc:     deviceSvc,
 		ObitSvc:       obitSvc,
 		Registry:      regClient,
+		WebhookSvc:    webhookSvc,
+		AuditSvc:      auditSvc,
+		MultisigSvc:   multisigSvc,
+		MigrationSvc:  migrationSvc,
+		EventHub:      eventHub,
+		EventManager:  eventManager,
 	})
 
+	// Shutdown does not cancel requests, open event streams are ended by the hub
+	apiServer.RegisterOnShutdown(eventHub.Close)
+
 	serverErrors := make(chan error, 1)
 
 	go func() {
@@ -247,12 +437,29 @@ func (s *ServerCommand) Execute(_ []string) error {
 			return fmt.Errorf("could not stop server gracefully: %w", err)
 		}
 
-		if err := wsClient.UnsubscribeAll(shutdownCtx); err != nil {
-			return fmt.Errorf("cannot unsubscribe : %w", err)
+		stopWorkers()
+
+		select {
+		case <-chainListener.Done():
+		case <-shutdownCtx.Done():
+			return fmt.Errorf("could not stop chain listener gracefully: %w", shutdownCtx.Err())
+		}
+
+		select {
+		case <-eventManager.Done():
+		case <-shutdownCtx.Done():
+			return fmt.Errorf("could not stop event handlers gracefully: %w", shutdownCtx.Err())
+		}
+
+		// handlers are stopped, nothing is published to the sinks anymore
+		if err := eventSink.Close(); err != nil {
+			return fmt.Errorf("could not close event sinks: %w", err)
 		}
 
-		if err := wsClient.Stop(); err != nil {
-			return fmt.Errorf("could not stop node ws client gracefully: %w", err)
+		select {
+		case <-webhookSvc.Done():
+		case <-shutdownCtx.Done():
+			return fmt.Errorf("could not stop webhook deliveries gracefully: %w", shutdownCtx.Err())
 		}
 
 		if err := s.DB.Close(); err != nil {
@@ -276,133 +483,78 @@ func (s *ServerCommand) makeAPIServer(cfg api.APIMuxConfig) *http.Server {
 	}
 }
 
-type wsClientConfig struct {
-	nodeClient obadanode.Client
-	deviceSvc  *device.Service
-	accountSvc *account.Service
-	obitSvc    *services.ObitService
-	bus        *eb.Bus
+func (s *ServerCommand) makeEventSource(nodeClient obadanode.Client) listener.EventSource {
+	if s.Node.EventSource == "polling" {
+		return listener.NewPollingSource(s.Node.PollInterval, nodeClient, s.Logger)
+	}
+
+	return listener.NewWebsocketSource(s.Node.RPCURL, nodeClient, s.Logger)
 }
 
-func (s *ServerCommand) makeWsClient(ctx context.Context, cfg wsClientConfig) (*jsonrpcclient.WSClient, error) {
-	client, err := jsonrpcclient.NewWS(s.Node.RPCURL, "/websocket")
-	if err != nil {
-		return nil, err
+// makeEventSink combines sinks that are enabled by the configuration
+func (s *ServerCommand) makeEventSink(rdb *redis.Client) (sinks.Multi, error) {
+	var eventSinks sinks.Multi
+
+	if s.Redis.Sink == "pubsub" || s.Redis.Sink == "both" {
+		eventSinks = append(eventSinks, sinks.NewRedisPubSub(rdb))
 	}
 
-	if er := client.Start(); er != nil {
-		return nil, er
+	if s.Redis.Sink == "streams" || s.Redis.Sink == "both" {
+		eventSinks = append(eventSinks, sinks.NewRedisStream(rdb, s.Redis.Stream, s.Redis.StreamMaxLen))
 	}
 
-	if er := client.Subscribe(ctx, "tm.event = 'Tx'"); er != nil {
-		return nil, er
+	if s.NATS.URL != "" {
+		natsSink, err := sinks.NewNATS(sinks.NATSConfig{
+			URL:           s.NATS.URL,
+			SubjectPrefix: s.NATS.SubjectPrefix,
+			JetStream:     s.NATS.JetStream,
+			CredsFile:     s.NATS.CredsFile,
+			TLS:           s.NATS.TLS.config(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		eventSinks = append(eventSinks, natsSink)
 	}
 
-	go func() {
-		for { //nolint:gosimple // ignore for now requires further refactoring
-			select {
-			case resp, ok := <-client.ResponsesCh:
-				if ok {
-					result := ctypes.ResultEvent{}
-
-					if err = tmjson.Unmarshal(resp.Result, &result); err != nil {
-						s.Logger.Errorw("unmarshal tx", "error", err)
-					}
-
-					for event, val := range result.Events {
-						if event == "message.action" { //nolint:gocritic
-
-							dataTx, ok := result.Data.(tmtypes.EventDataTx)
-							if !ok {
-								s.Logger.Error("decoding tx")
-								break
-							}
-
-							tx, err := cfg.nodeClient.DecodeTx(dataTx.GetTx())
-							if err != nil {
-								s.Logger.Errorw("ecoding tx", "error", err)
-								break
-							}
-
-							switch val[0] {
-							case "mint_nft":
-								for _, msg := range tx.GetMsgs() {
-									msg, ok := msg.(*obadatypes.MsgMintNFT)
-									if ok {
-										s.Logger.Infow("obit metadata were updated", "data", result)
-
-										// for future refactoring
-										_ = cfg.bus.Emit(ctx, events.NftMinted, msg.Id)
-
-										s.Logger.Infow("obit metadata were updated", "data", msg)
-									}
-								}
-								s.Logger.Infow("obit was minted", "data", result.Data)
-							case "update_uri_hash":
-								for _, msg := range tx.GetMsgs() {
-									msg, ok := msg.(*obadatypes.MsgUpdateUriHash)
-									if ok {
-										// for future refactoring
-										_ = cfg.bus.Emit(ctx, events.NftMetadataUpdated, msg.Id)
-
-										nft, err := cfg.nodeClient.GetNFT(ctx, msg.Id)
-										if err != nil {
-											s.Logger.Errorw("cannot get NFT by DID", "did", msg.Id, "error", err)
-											break
-										}
-
-										profileID, err := cfg.accountSvc.GetProfileByAddress(msg.Editor)
-										if err != nil {
-											s.Logger.Errorw("caanot find profile", "address", msg.Editor, "error", err)
-											break
-										}
-
-										authCtx := auth.SetClaims(ctx, auth.Claims{UserID: profileID})
-
-										if err := cfg.deviceSvc.ImportDevice(authCtx, *nft, msg.Editor); err != nil {
-											s.Logger.Errorw("cannot import device updates", "msg", msg, "error", err)
-										}
-
-										s.Logger.Infow("obit metadata were updated", "data", msg)
-									}
-								}
-
-							case "transfer_nft":
-								for _, msg := range tx.GetMsgs() {
-									msg, ok := msg.(*obadatypes.MsgTransferNFT)
-									if ok {
-										// for future refactoring
-										_ = cfg.bus.Emit(ctx, events.NftTransfered, msg.Id)
-
-										nft, err := cfg.nodeClient.GetNFT(ctx, msg.Id)
-										if err != nil {
-											s.Logger.Errorw("cannot get NFT by DID", "did", msg.Id, "error", err)
-											break
-										}
-
-										profileID, err := cfg.accountSvc.GetProfileByAddress(msg.Receiver)
-										if err != nil {
-											s.Logger.Errorw("cannot find profile", "address", msg.Receiver, "error", err)
-											break
-										}
-
-										authCtx := auth.SetClaims(ctx, auth.Claims{UserID: profileID})
-
-										if err := cfg.deviceSvc.ImportDevice(authCtx, *nft, msg.Receiver); err != nil {
-											s.Logger.Errorw("cannot import device updates", "msg", msg, "error", err)
-											break
-										}
-
-										s.Logger.Infow("nft was received", "nft", nft.Id)
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+	if len(s.Kafka.Brokers) > 0 {
+		kafkaSink, err := sinks.NewKafka(sinks.KafkaConfig{
+			Brokers: s.Kafka.Brokers,
+			Topic:   s.Kafka.Topic,
+			TLS:     s.Kafka.TLS.config(),
+			SASL:    sinks.SASLConfig{User: s.Kafka.SASLUser, Password: s.Kafka.SASLPassword},
+		})
+		if err != nil {
+			return nil, err
 		}
-	}()
 
-	return client, nil
+		eventSinks = append(eventSinks, kafkaSink)
+	}
+
+	return eventSinks, nil
+}
+
+func (s *ServerCommand) makeSponsor(ctx context.Context, kr keyring.Keyring, signers signer.Provider) (*blockchain.Sponsor, error) {
+	treasury, err := sponsorSigner(ctx, s.Sponsor.Key, kr, signers)
+	if err != nil {
+		return nil, err
+	}
+
+	spendLimit, err := sdk.ParseCoinsNormalized(s.Sponsor.SpendLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spend limit: %w", err)
+	}
+
+	// the auto granted allowance must not let every managed account spend the treasury
+	if s.Sponsor.AutoGrant && spendLimit.Empty() {
+		return nil, errors.New("spend limit is required when the allowance is granted automatically")
+	}
+
+	return &blockchain.Sponsor{
+		Signer:     treasury,
+		SpendLimit: spendLimit,
+		Expiration: s.Sponsor.Expiration,
+		AutoGrant:  s.Sponsor.AutoGrant,
+	}, nil
 }
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/obada-foundation/client-helper/system/signer"
+	"go.uber.org/zap"
+)
+
+// SignerGroup defines where transactions and registry documents are signed
+type SignerGroup struct {
+	Type    string        `long:"type" env:"TYPE" choice:"keyring" choice:"remote" default:"keyring" description:"signer of the account keys"` // nolint
+	URL     string        `long:"url" env:"URL" description:"remote signer url"`
+	Token   string        `long:"token" env:"TOKEN" description:"remote signer token"`
+	Timeout time.Duration `long:"timeout" env:"TIMEOUT" default:"10s" description:"remote signer request timeout"`
+}
+
+// SignerCommand runs the remote signer, so account keys are kept on the separate host and never reach the API
+type SignerCommand struct {
+	Listen          string        `long:"listen" env:"SIGNER_LISTEN" default:":9443" description:"listening address"`
+	Token           string        `long:"token" env:"SIGNER_TOKEN" description:"token that authorizes requests"`
+	TokenFile       string        `long:"token-file" env:"SIGNER_TOKEN_FILE" description:"file with the token that authorizes requests"`
+	ShutdownTimeout time.Duration `long:"shutdown-timeout" env:"SHUTDOWN_TIMEOUT" default:"20s" description:"shutdown timeout"`
+	Keyring         KeyringGroup  `group:"keyring" namespace:"keyring" env-namespace:"KEYRING"`
+	SSL             SSLGroup      `group:"ssl" namespace:"ssl" env-namespace:"SSL"`
+
+	CommonOpts
+}
+
+// Offline satisfies OfflineCommander interface
+func (s *SignerCommand) Offline() bool {
+	return true
+}
+
+// Execute is the entry point for "signer" command, called by flag parser
+func (s *SignerCommand) Execute(_ []string) error {
+	token, err := s.token()
+	if err != nil {
+		return err
+	}
+
+	kr, err := s.Keyring.open()
+	if err != nil {
+		return fmt.Errorf("creating keyring error: %w", err)
+	}
+
+	if err := s.Keyring.checkMigrated(kr); err != nil {
+		return err
+	}
+
+	wallets, err := s.Keyring.openWallets()
+	if err != nil {
+		return fmt.Errorf("opening wallets error: %w", err)
+	}
+
+	srv := &http.Server{
+		Addr: s.Listen,
+		Handler: signer.NewServer(signer.ServerConfig{
+			Provider: signer.NewKeyring(kr),
+			Keyring:  kr,
+			Wallets:  wallets,
+			Token:    token,
+			Logger:   s.Logger,
+		}),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		ErrorLog:     zap.NewStdLog(s.Logger.Desugar()),
+	}
+
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+
+	serverErrors := make(chan error, 1)
+
+	go func() {
+		s.Logger.Infow("startup", "status", "signer started", "host", srv.Addr, "ssl", s.SSL.Type)
+
+		if s.SSL.Type == "static" {
+			serverErrors <- srv.ListenAndServeTLS(s.SSL.Cert, s.SSL.Key)
+			return
+		}
+
+		serverErrors <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErrors:
+		return fmt.Errorf("signer error: %w", err)
+
+	case sig := <-shutdown:
+		s.Logger.Infow("shutdown", "status", "shutdown started", "signal", sig)
+		defer s.Logger.Infow("shutdown", "status", "shutdown complete", "signal", sig)
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(ctx); err != nil {
+			_ = srv.Close()
+			return fmt.Errorf("could not stop signer gracefully: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// token reads the token from the secret file, or takes it from the option
+func (s *SignerCommand) token() (string, error) {
+	if s.TokenFile == "" {
+		if s.Token == "" {
+			return "", errors.New("signer token is not configured, please use --token-file")
+		}
+
+		return s.Token, nil
+	}
+
+	b, err := os.ReadFile(s.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("cannot read signer token: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// isRemote returns true when keys of the accounts are kept by the remote signer
+func (g SignerGroup) isRemote() bool {
+	return g.Type == "remote"
+}
+
+// makeRemote returns the remote signer
+func (g SignerGroup) makeRemote() (*signer.Remote, error) {
+	return signer.NewRemote(signer.RemoteConfig{
+		URL:     g.URL,
+		Token:   g.Token,
+		Timeout: g.Timeout,
+	})
+}
+
+// sponsorSigner returns the signer of the treasury account, the account address is resolved by the signers provider,
+// otherwise the key is taken from the keyring by name
+func sponsorSigner(ctx context.Context, key string, kr keyring.Keyring, signers signer.Provider) (signer.Signer, error) {
+	if _, err := sdk.AccAddressFromBech32(key); err == nil {
+		return signers.Signer(ctx, key)
+	}
+
+	return signer.NewKeyringSigner(kr, key)
+}
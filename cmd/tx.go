@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
+)
+
+// TxCommand groups transaction subcommands
+type TxCommand struct {
+	Sign TxSignCommand `command:"sign" description:"sign an unsigned transaction file with a keyring key"`
+}
+
+// TxSignCommand signs unsigned transaction built by the client-helper without connection to the node
+type TxSignCommand struct {
+	Keyring       KeyringGroup `group:"keyring" namespace:"keyring" env-namespace:"KEYRING"`
+	From          string       `long:"from" required:"true" description:"name or address of the key that signs the transaction"`
+	ChainID       string       `long:"chain-id" description:"chain id, overrides the sign doc value"`
+	AccountNumber uint64       `long:"account-number" description:"account number, overrides the sign doc value"`
+	Sequence      uint64       `long:"sequence" description:"account sequence, overrides the sign doc value"`
+	Output        string       `long:"output" default:"signed-tx.json" description:"file where signed transaction will be written"`
+	Args          struct {
+		File string `positional-arg-name:"FILE" required:"true" description:"unsigned transaction file"`
+	} `positional-args:"yes"`
+
+	CommonOpts
+}
+
+// SignedTx is a signed transaction that can be broadcasted by the client-helper
+type SignedTx struct {
+	Tx      json.RawMessage `json:"tx"`
+	TxBytes []byte          `json:"tx_bytes"`
+}
+
+// Offline satisfies OfflineCommander interface
+func (t *TxSignCommand) Offline() bool {
+	return true
+}
+
+// Execute is the entry point for "tx sign" command, called by flag parser
+func (t *TxSignCommand) Execute(_ []string) error {
+	data, err := os.ReadFile(t.Args.File)
+	if err != nil {
+		return fmt.Errorf("cannot read unsigned transaction: %w", err)
+	}
+
+	var utx obadanode.UnsignedTx
+
+	if er := json.Unmarshal(data, &utx); er != nil {
+		return fmt.Errorf("cannot parse unsigned transaction: %w", er)
+	}
+
+	signDoc := utx.SignDoc
+
+	if t.ChainID != "" {
+		signDoc.ChainID = t.ChainID
+	}
+
+	if t.AccountNumber != 0 {
+		signDoc.AccountNumber = t.AccountNumber
+	}
+
+	if t.Sequence != 0 {
+		signDoc.Sequence = t.Sequence
+	}
+
+	if signDoc.ChainID == "" {
+		return fmt.Errorf("chain id is missing in the sign doc, please use --chain-id")
+	}
+
+	kr, err := t.Keyring.open()
+	if err != nil {
+		return err
+	}
+
+	record, err := t.key(kr)
+	if err != nil {
+		return err
+	}
+
+	keySigner, err := signer.NewKeyringSigner(kr, record.Name)
+	if err != nil {
+		return err
+	}
+
+	enc := obadanode.NewTxEncoding()
+
+	var transaction sdk.Tx
+
+	if len(utx.Tx) > 0 {
+		transaction, err = enc.TxConfig.TxJSONDecoder()(utx.Tx)
+	} else {
+		transaction, err = enc.TxConfig.TxDecoder()(utx.TxBytes)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot decode unsigned transaction: %w", err)
+	}
+
+	txBuilder, err := enc.TxConfig.WrapTxBuilder(transaction)
+	if err != nil {
+		return err
+	}
+
+	signers, err := txBuilder.GetTx().GetSigners()
+	if err != nil {
+		return err
+	}
+
+	if len(signers) != 1 || !bytes.Equal(signers[0], signer.Address(keySigner)) {
+		return fmt.Errorf("transaction should be signed by %s only", signer.Address(keySigner))
+	}
+
+	if er := obadanode.SignTx(context.Background(), enc.TxConfig, txBuilder, keySigner, signDoc); er != nil {
+		return fmt.Errorf("cannot sign transaction: %w", er)
+	}
+
+	var stx SignedTx
+
+	if stx.Tx, err = enc.TxConfig.TxJSONEncoder()(txBuilder.GetTx()); err != nil {
+		return err
+	}
+
+	if stx.TxBytes, err = enc.TxConfig.TxEncoder()(txBuilder.GetTx()); err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(stx, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if er := os.WriteFile(t.Output, out, 0o600); er != nil {
+		return er
+	}
+
+	t.Logger.Infof("signed transaction was written to %s", t.Output)
+
+	return nil
+}
+
+func (t *TxSignCommand) key(kr keyring.Keyring) (*keyring.Record, error) {
+	if addr, err := sdk.AccAddressFromBech32(t.From); err == nil {
+		return kr.KeyByAddress(addr)
+	}
+
+	return kr.Key(t.From)
+}
@@ -0,0 +1,46 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/services/device"
+)
+
+// Envelope is the bus event in the form delivered outside of client-helper
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	ProfileID  string          `json:"profile_id"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// New wraps the bus event. The profile is taken from the event data if it has one,
+// otherwise from the claims of the context the event was emitted with.
+func New(ctx context.Context, e bus.Event) (Envelope, error) {
+	profileID := auth.GetUserID(ctx)
+
+	var data any = e.Data
+
+	if evt, ok := e.Data.(device.DeviceSaved); ok {
+		profileID = evt.ProfileID
+		data = evt.Device
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{
+		ID:         e.ID,
+		Type:       e.Topic,
+		ProfileID:  profileID,
+		Payload:    payload,
+		OccurredAt: e.OccurredAt,
+	}, nil
+}
@@ -1,5 +1,11 @@
 package events
 
+import (
+	"context"
+
+	"github.com/mustafaturan/bus/v3"
+)
+
 const (
 	// AccountDeleted is the event name for when an account is deleted
 	AccountDeleted = "account.deleted"
@@ -25,6 +31,9 @@ const (
 	// AccountCreatedHandler is the handler for the account created event
 	AccountCreatedHandler = "handlers:" + AccountCreated
 
+	// AccountSyncHandler is the handler that registers the created account and imports its NFTs
+	AccountSyncHandler = "handlers:account.sync"
+
 	// DeviceSavedHandler is the event handler for when a device is saved
 	DeviceSavedHandler = "handlers:" + DeviceSaved
 
@@ -37,3 +46,8 @@ const (
 	// NftMetadataUpdatedHandler is the event handler for when an nft metadata is updated
 	NftMetadataUpdatedHandler = "handlers:" + NftMetadataUpdated
 )
+
+// EventSink publishes events outside of client-helper
+type EventSink interface {
+	Publish(ctx context.Context, e bus.Event) error
+}
@@ -0,0 +1,11 @@
+package handlers
+
+import "errors"
+
+var (
+	// ErrQueueFull is returned when all workers are busy and the event queue has no room for the event
+	ErrQueueFull = errors.New("event queue is full")
+
+	// ErrUnknownHandler is returned when the failure refers to the handler that is not registered
+	ErrUnknownHandler = errors.New("event handler is not registered")
+)
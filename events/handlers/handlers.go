@@ -2,19 +2,20 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
-	"github.com/cosmos/cosmos-sdk/crypto/keyring"
-	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/mustafaturan/bus/v3"
 	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/services/account"
 	"github.com/obada-foundation/client-helper/services/blockchain"
 	"github.com/obada-foundation/client-helper/services/device"
 	pbacc "github.com/obada-foundation/registry/api/pb/v1/account"
 	registry "github.com/obada-foundation/registry/client"
 	"github.com/obada-foundation/sdkgo/base58"
-	"github.com/redis/go-redis/v9"
+	db "github.com/tendermint/tm-db"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -24,42 +25,111 @@ import (
 type Config struct {
 	Bus           *bus.Bus
 	Logger        *zap.SugaredLogger
-	RedisClient   *redis.Client
+	Sink          events.EventSink
+	AccountSvc    *account.Service
 	DeviceSvc     *device.Service
 	BlockchainSvc *blockchain.Service
 	Registry      registry.Client
-	Keyring       keyring.Keyring
+
+	// DB keeps events that were not handled after all attempts
+	DB db.DB
+
+	// Workers how many events are handled concurrently, events of the same profile are handled by one worker in order
+	Workers int
+
+	// QueueSize how many events wait for every worker, events above it are kept as failures
+	QueueSize int
+
+	// Timeout of the single handler attempt
+	Timeout time.Duration
+
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
 }
 
-// EventManager manages ClientHelper events
+// EventManager manages ClientHelper events. Handlers run on the worker pool with their own context,
+// so they are not cancelled with the request that emitted the event.
 type EventManager struct {
 	b             *bus.Bus
 	logger        *zap.SugaredLogger
-	redis         *redis.Client
+	sink          events.EventSink
+	accountSvc    *account.Service
 	deviceSvc     *device.Service
 	blockchainSvc *blockchain.Service
 	registry      registry.Client
-	kr            keyring.Keyring
+	db            db.DB
+
+	workers     int
+	timeout     time.Duration
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	handlers map[string]handlerFunc
+	jobs     []chan job
+	pending  sync.WaitGroup
+	done     chan struct{}
 }
 
-// Initialize initializes handlers
-func Initialize(cfg Config) {
+// Initialize initializes handlers, events are handled after Run is started
+func Initialize(cfg Config) *EventManager {
 	manager := &EventManager{
 		b:             cfg.Bus,
 		logger:        cfg.Logger,
-		redis:         cfg.RedisClient,
+		sink:          cfg.Sink,
+		accountSvc:    cfg.AccountSvc,
 		deviceSvc:     cfg.DeviceSvc,
 		blockchainSvc: cfg.BlockchainSvc,
 		registry:      cfg.Registry,
-		kr:            cfg.Keyring,
+		db:            cfg.DB,
+		workers:       cfg.Workers,
+		timeout:       cfg.Timeout,
+		maxAttempts:   cfg.MaxAttempts,
+		minBackoff:    cfg.MinBackoff,
+		maxBackoff:    cfg.MaxBackoff,
+		handlers:      make(map[string]handlerFunc),
+		done:          make(chan struct{}),
+	}
+
+	if manager.workers <= 0 {
+		manager.workers = DefaultWorkers
+	}
+
+	if manager.timeout <= 0 {
+		manager.timeout = DefaultTimeout
+	}
+
+	if manager.maxAttempts <= 0 {
+		manager.maxAttempts = DefaultMaxAttempts
+	}
+
+	if manager.minBackoff <= 0 {
+		manager.minBackoff = DefaultMinBackoff
+	}
+
+	if manager.maxBackoff <= 0 {
+		manager.maxBackoff = DefaultMaxBackoff
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	manager.jobs = make([]chan job, manager.workers)
+	for i := range manager.jobs {
+		manager.jobs[i] = make(chan job, queueSize)
 	}
 
 	manager.RegisterEvents()
 	manager.RegisterHandlers()
+
+	return manager
 }
 
 // RegisterEvents registers events
-func (em EventManager) RegisterEvents() {
+func (em *EventManager) RegisterEvents() {
 	em.b.RegisterTopics(
 		// Account events
 		events.AccountDeleted,
@@ -75,138 +145,143 @@ func (em EventManager) RegisterEvents() {
 	)
 }
 
-func (em EventManager) accountDeletedHandler(ctx context.Context, e bus.Event) {
+func (em *EventManager) accountDeletedHandler(ctx context.Context, e bus.Event) error {
 	accAddress := fmt.Sprintf("%v", e.Data)
 
 	if _, err := em.deviceSvc.DeleteByAddress(ctx, accAddress); err != nil {
-		em.logger.Errorw("failed to delete devices", "EVENT", events.AccountDeleted, "address", accAddress, "error", err)
-		return
+		return fmt.Errorf("failed to delete devices of %s: %w", accAddress, err)
 	}
 
-	em.redis.Publish(ctx, events.AccountDeleted, accAddress)
+	if err := em.publish(ctx, e); err != nil {
+		return err
+	}
 
 	em.logger.Infow("account deleted", "EVENT", events.AccountDeleted, "account address", accAddress)
+
+	return nil
 }
 
-func (em EventManager) accountCreatedHandler(ctx context.Context, e bus.Event) {
+// accountSyncHandler registers the account key in the registry and imports NFTs of the account.
+// Both steps are idempotent, so the handler is retried as a whole. Watch-only accounts have no key
+// to register, their NFTs are imported read-only.
+func (em *EventManager) accountSyncHandler(ctx context.Context, e bus.Event) error {
 	accAddress := fmt.Sprintf("%v", e.Data)
 
-	if _, err := em.registry.GetPublicKey(ctx, &pbacc.GetPublicKeyRequest{Address: accAddress}); err != nil {
-		er, ok := status.FromError(err)
-		if !ok || er.Code() != codes.NotFound {
-			em.logger.Errorw("failed to check if account key is in the registry", "EVENT", events.AccountCreated, "address", accAddress, "error", err)
-		}
-
-		addr, _ := types.AccAddressFromBech32(accAddress)
-
-		key, err := em.kr.KeyByAddress(addr)
-		if err != nil {
-			em.logger.Errorw("cannot find key in keyring", "EVENT", events.AccountCreated, "address", accAddress, "error", err)
-		}
-
-		if err == nil {
-			pubKey, _ := key.GetPubKey()
+	watchOnly, err := em.accountSvc.IsWatchAccount(ctx, accAddress)
+	if err != nil {
+		return fmt.Errorf("failed to check if %s is watch-only: %w", accAddress, err)
+	}
 
-			msg := &pbacc.RegisterAccountRequest{
-				Pubkey: base58.Encode(pubKey.Bytes()),
-			}
+	importDevice := em.deviceSvc.ImportDevice
 
-			_, err := em.registry.RegisterAccount(ctx, msg)
-			if err != nil {
-				em.logger.Errorw("failed to register account in the registry", "EVENT", events.AccountCreated, "address", accAddress, "error", er)
-			} else {
-				em.logger.Infow("added to the registry", "EVENT", events.AccountCreated, "account address", accAddress)
-			}
-		}
+	if watchOnly {
+		importDevice = em.deviceSvc.ImportReadOnlyDevice
+	} else if err := em.registerAccount(ctx, accAddress); err != nil {
+		return err
 	}
 
 	nfts, err := em.blockchainSvc.GetNFTByAddress(ctx, accAddress)
 	if err != nil {
-		em.logger.Errorw("failed to fetch nfts from blockchain", "EVENT", events.AccountCreated, "address", accAddress, "error", err)
-		return
+		return fmt.Errorf("failed to fetch nfts of %s from blockchain: %w", accAddress, err)
 	}
 
+	var errs []error
+
 	for _, NFT := range nfts {
-		if err := em.deviceSvc.ImportDevice(ctx, NFT, accAddress); err != nil {
-			em.logger.Errorw("failed to import nft", "EVENT", events.AccountCreated, "address", accAddress, "nft", NFT.Id, "error", err)
-			continue
+		if err := importDevice(ctx, NFT, accAddress); err != nil {
+			errs = append(errs, fmt.Errorf("failed to import nft %s: %w", NFT.Id, err))
 		}
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
 	em.logger.Infow("assets imported", "EVENT", events.AccountCreated, "account address", accAddress)
+
+	return nil
 }
 
-// RegisterHandlers registers handlers
-func (em EventManager) RegisterHandlers() {
+// registerAccount adds the account public key to the registry when it is not there yet
+func (em *EventManager) registerAccount(ctx context.Context, accAddress string) error {
+	_, err := em.registry.GetPublicKey(ctx, &pbacc.GetPublicKeyRequest{Address: accAddress})
+	if err == nil {
+		return nil
+	}
 
-	em.b.RegisterHandler(events.AccountDeletedHandler, bus.Handler{
-		Handle:  em.accountDeletedHandler,
-		Matcher: events.AccountDeleted,
-	})
+	if er, ok := status.FromError(err); !ok || er.Code() != codes.NotFound {
+		return fmt.Errorf("failed to check if account key of %s is in the registry: %w", accAddress, err)
+	}
 
-	em.b.RegisterHandler(events.AccountCreatedHandler, bus.Handler{
-		Handle:  em.accountCreatedHandler,
-		Matcher: events.AccountCreated,
-	})
+	pubKey, err := em.accountSvc.GetPubKeyByAddress(accAddress)
+	if err != nil {
+		return fmt.Errorf("cannot find key of %s in keyring: %w", accAddress, err)
+	}
 
-	{
-		h := bus.Handler{
-			Handle: func(ctx context.Context, e bus.Event) {
-				jsonData, err := json.Marshal(e.Data)
-				if err != nil {
-					em.logger.Errorw("failed to marshal json data", "EVENT", events.DeviceSaved, "error", err)
-					return
-				}
+	msg := &pbacc.RegisterAccountRequest{
+		Pubkey: base58.Encode(pubKey.Bytes()),
+	}
 
-				em.redis.Publish(ctx, events.DeviceSaved, string(jsonData))
+	if _, err := em.registry.RegisterAccount(ctx, msg); err != nil {
+		return fmt.Errorf("failed to register account %s in the registry: %w", accAddress, err)
+	}
 
-				em.logger.Infow("device saved", "EVENT", events.DeviceSaved, "device", e.Data)
-			},
-			Matcher: events.DeviceSaved,
-		}
+	em.logger.Infow("added to the registry", "EVENT", events.AccountCreated, "account address", accAddress)
+
+	return nil
+}
 
-		em.b.RegisterHandler(events.DeviceSavedHandler, h)
+// publish sends the event to the sink, the failed handler is retried and kept as failure after all attempts,
+// so events are not lost while the sink is down
+func (em *EventManager) publish(ctx context.Context, e bus.Event) error {
+	if err := em.sink.Publish(ctx, e); err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", e.Topic, err)
 	}
 
-	// NFT event handlers
-	{
-		h := bus.Handler{
-			Handle: func(ctx context.Context, e bus.Event) {
-				DID := fmt.Sprintf("%v", e.Data)
+	return nil
+}
 
-				em.redis.Publish(ctx, events.NftMinted, DID)
+// publishHandler sends the event to the sink
+func (em *EventManager) publishHandler(ctx context.Context, e bus.Event) error {
+	return em.publish(ctx, e)
+}
 
-				em.logger.Infow("nft minted", "EVENT", events.NftMinted, "DID", DID)
-			},
-			Matcher: events.NftMinted,
+// RegisterHandlers registers handlers
+func (em *EventManager) RegisterHandlers() {
+	em.handle(events.AccountDeletedHandler, events.AccountDeleted, em.accountDeletedHandler)
+	em.handle(events.AccountCreatedHandler, events.AccountCreated, em.publishHandler)
+	em.handle(events.AccountSyncHandler, events.AccountCreated, em.accountSyncHandler)
+
+	em.handle(events.DeviceSavedHandler, events.DeviceSaved, func(ctx context.Context, e bus.Event) error {
+		if err := em.publish(ctx, e); err != nil {
+			return err
 		}
 
-		em.b.RegisterHandler(events.NftMintedHandler, h)
-	}
-	{
-		h := bus.Handler{
-			Handle: func(ctx context.Context, e bus.Event) {
-				DID := fmt.Sprintf("%v", e.Data)
+		em.logger.Infow("device saved", "EVENT", events.DeviceSaved, "device", e.Data)
+
+		return nil
+	})
 
-				em.redis.Publish(ctx, events.NftMetadataUpdated, DID)
-			},
-			Matcher: events.NftMetadataUpdated,
+	// NFT event handlers
+	em.handle(events.NftMintedHandler, events.NftMinted, func(ctx context.Context, e bus.Event) error {
+		if err := em.publish(ctx, e); err != nil {
+			return err
 		}
 
-		em.b.RegisterHandler(events.NftMetadataUpdatedHandler, h)
-	}
-	{
-		h := bus.Handler{
-			Handle: func(ctx context.Context, e bus.Event) {
-				DID := fmt.Sprintf("%v", e.Data)
+		em.logger.Infow("nft minted", "EVENT", events.NftMinted, "DID", e.Data)
+
+		return nil
+	})
 
-				em.redis.Publish(ctx, events.NftTransfered, DID)
+	em.handle(events.NftMetadataUpdatedHandler, events.NftMetadataUpdated, em.publishHandler)
 
-				em.logger.Infow("nft received", "EVENT", events.NftTransfered, "DID", DID)
-			},
-			Matcher: events.NftTransfered,
+	em.handle(events.NftTransferedHandler, events.NftTransfered, func(ctx context.Context, e bus.Event) error {
+		if err := em.publish(ctx, e); err != nil {
+			return err
 		}
 
-		em.b.RegisterHandler(events.NftTransferedHandler, h)
-	}
+		em.logger.Infow("nft received", "EVENT", events.NftTransfered, "DID", e.Data)
+
+		return nil
+	})
 }
@@ -2,43 +2,54 @@ package handlers_test
 
 import (
 	"context"
+	"errors"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	dkeyring "github.com/99designs/keyring"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/go-redis/redismock/v9"
+	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
 	"github.com/golang/mock/gomock"
 	"github.com/mustafaturan/bus/v3"
 	"github.com/obada-foundation/client-helper/auth"
 	"github.com/obada-foundation/client-helper/events"
 	"github.com/obada-foundation/client-helper/events/handlers"
 	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
 	"github.com/obada-foundation/client-helper/services/blockchain"
 	"github.com/obada-foundation/client-helper/services/device"
 	ipfsclinet "github.com/obada-foundation/client-helper/system/ipfs/mocks"
 	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
 	"github.com/obada-foundation/client-helper/testutil"
 	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
-	"github.com/obada-foundation/registry/api/pb/v1/account"
+	pbacc "github.com/obada-foundation/registry/api/pb/v1/account"
 	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
 	regclient "github.com/obada-foundation/registry/client/mock"
 	"github.com/obada-foundation/sdkgo/asset"
+	"github.com/obada-foundation/sdkgo/base58"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tm-db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func GenKeys(t *testing.T) (cryptotypes.PrivKey, cryptotypes.PubKey, string) {
+func GenKeys(t *testing.T) (signer.Signer, cryptotypes.PubKey, string) {
 	privKey := secp256k1.GenPrivKey()
 	pubKey := privKey.PubKey()
 	addr, err := sdk.AccAddressFromHexUnsafe(pubKey.Address().String())
 	require.NoError(t, err)
 
-	return privKey, pubKey, addr.String()
+	return signer.NewPrivKeySigner(privKey), pubKey, addr.String()
 }
 
 func Test_EventManager(t *testing.T) {
@@ -46,11 +57,15 @@ func Test_EventManager(t *testing.T) {
 
 	t.Run("accountDeletedHandler", accountDeletedHandler)
 	t.Run("accountCreatedHandler", accountCreatedHandler)
+	t.Run("watchAccountCreatedHandler", watchAccountCreatedHandler)
+	t.Run("remoteAccountCreatedHandler", remoteAccountCreatedHandler)
+	t.Run("failedEventsRetry", failedEventsRetry)
+	t.Run("failedPublishRetry", failedPublishRetry)
 }
 
 // nolint:gocritic
 func accountCreatedHandler(t *testing.T) {
-	b, deviceSvc, nodeClientMock, regClient, ipfs, teardown := startupT(t)
+	b, em, _, deviceSvc, nodeClientMock, regClient, ipfs, teardown := startupT(t)
 	defer teardown()
 
 	ctx := context.Background()
@@ -83,8 +98,8 @@ func accountCreatedHandler(t *testing.T) {
 			nil,
 		).Once()
 
-	regClient.EXPECT().GetPublicKey(gomock.Any(), gomock.Eq(&account.GetPublicKeyRequest{Address: accountAddress})).Times(1).
-		Return(&account.GetPublicKeyResponse{}, nil)
+	regClient.EXPECT().GetPublicKey(gomock.Any(), gomock.Eq(&pbacc.GetPublicKeyRequest{Address: accountAddress})).Times(1).
+		Return(&pbacc.GetPublicKeyResponse{}, nil)
 
 	regClient.EXPECT().Get(gomock.Any(), gomock.Eq(&diddoc.GetRequest{Did: nfts[0].Id})).Times(1).
 		Return(&diddoc.GetResponse{
@@ -105,6 +120,8 @@ func accountCreatedHandler(t *testing.T) {
 	err = b.Emit(ctx, events.AccountCreated, accountAddress)
 	require.NoError(t, err)
 
+	em.Wait()
+
 	devices, err := deviceSvc.GetByAddress(ctx, accountAddress)
 	require.NoError(t, err)
 
@@ -116,9 +133,139 @@ func accountCreatedHandler(t *testing.T) {
 	assert.Equal(t, 1, len(devices))
 }
 
+// nolint:gocritic
+func watchAccountCreatedHandler(t *testing.T) {
+	_, em, accountSvc, deviceSvc, nodeClientMock, regClient, ipfs, teardown := startupT(t)
+	defer teardown()
+
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	accountAddress := "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg"
+
+	data, err := codectypes.NewAnyWithValue(&obadatypes.NFTData{
+		Usn: "25rc8AxGbLSr",
+	})
+	require.NoError(t, err)
+
+	nft := obadatypes.NFT{
+		ClassId: "OBD",
+		Id:      "did:obada:64925be84b586363670c1f7e5ada86a37904e590d1f6570d834436331dd3eb88",
+		Data:    data,
+	}
+
+	nodeClientMock.On("GetNFTByAddress", mock.Anything, accountAddress).
+		Return([]obadatypes.NFT{nft}, nil).
+		Once()
+
+	ipfs.On("GetDocument", "bafkreibdklgsqwqv5xci6cmx46j2y3to5y5sqbuoqz7g2qpgjthzgwrz4i").
+		Return(
+			[]byte(`{"serial_number":"SN123456X", "manufacturer":"Sony", "part_number":"PN123456S"}`),
+			nil,
+		).Once()
+
+	// the watch-only account has no key, so it is not registered in the registry
+	regClient.EXPECT().GetPublicKey(gomock.Any(), gomock.Any()).Times(0)
+
+	regClient.EXPECT().Get(gomock.Any(), gomock.Eq(&diddoc.GetRequest{Did: nft.Id})).Times(1).
+		Return(&diddoc.GetResponse{
+			Document: &diddoc.DIDDocument{
+				Id: nft.Id,
+				Metadata: &diddoc.Metadata{
+					Objects: []*diddoc.Object{{
+						Url: "ipfs://bafkreibdklgsqwqv5xci6cmx46j2y3to5y5sqbuoqz7g2qpgjthzgwrz4i",
+						Metadata: map[string]string{
+							"type": string(asset.PhysicalAssetIdentifiers),
+							"name": string(asset.PhysicalAssetIdentifiers),
+						},
+					}},
+				},
+			},
+		}, nil)
+
+	err = accountSvc.AddWatchAccount(ctx, svcs.NewWatchAccount{Address: accountAddress, Name: "Partner"})
+	require.NoError(t, err)
+
+	em.Wait()
+
+	d, err := deviceSvc.GetByUSN(ctx, "25rc8AxGbLSr")
+	require.NoError(t, err)
+
+	assert.Equal(t, accountAddress, d.Address)
+	assert.True(t, d.ReadOnly)
+
+	t.Log("Signing by the watch-only account is rejected")
+	{
+		_, err := accountSvc.GetAccountSigner(ctx, accountAddress)
+		require.ErrorIs(t, err, account.ErrWatchOnlyAccount)
+
+		_, err = accountSvc.GetRegistrySigner(ctx, accountAddress)
+		require.ErrorIs(t, err, account.ErrWatchOnlyAccount)
+
+		err = accountSvc.AddWatchAccount(ctx, svcs.NewWatchAccount{Address: accountAddress})
+		require.ErrorIs(t, err, account.ErrAccountExists)
+	}
+}
+
+// nolint:gocritic
+func remoteAccountCreatedHandler(t *testing.T) {
+	b, em, accountSvc, _, nodeClientMock, regClient, _, teardown := startupT(t)
+	defer teardown()
+
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	logger, deleteLogFile := testutil.MakeLoger()
+	defer deleteLogFile()
+
+	// keys live only on the signer
+	signerKr := keyring.NewInMemory(cosmostestutil.MakeTestEncodingConfig().Codec)
+
+	key, _, err := signerKr.NewMnemonic("1_0", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+
+	pubKey, err := key.GetPubKey()
+	require.NoError(t, err)
+
+	address, err := key.GetAddress()
+	require.NoError(t, err)
+
+	accountAddress := address.String()
+
+	srv := httptest.NewServer(signer.NewServer(signer.ServerConfig{
+		Provider: signer.NewKeyring(signerKr),
+		Keyring:  signerKr,
+		Wallets:  dkeyring.NewArrayKeyring(nil),
+		Logger:   logger,
+	}))
+	defer srv.Close()
+
+	remote, err := signer.NewRemote(signer.RemoteConfig{URL: srv.URL})
+	require.NoError(t, err)
+
+	accountSvc.SetRemoteSigner(remote)
+
+	regClient.EXPECT().GetPublicKey(gomock.Any(), gomock.Eq(&pbacc.GetPublicKeyRequest{Address: accountAddress})).Times(1).
+		Return(nil, status.Error(codes.NotFound, "not found"))
+
+	regClient.EXPECT().RegisterAccount(gomock.Any(), gomock.Eq(&pbacc.RegisterAccountRequest{
+		Pubkey: base58.Encode(pubKey.Bytes()),
+	})).Times(1).Return(&pbacc.RegisterAccountResponse{}, nil)
+
+	nodeClientMock.On("GetNFTByAddress", mock.Anything, accountAddress).
+		Return([]obadatypes.NFT{}, nil).
+		Once()
+
+	require.NoError(t, b.Emit(ctx, events.AccountCreated, accountAddress))
+
+	em.Wait()
+
+	failures, err := em.Failures(ctx)
+	require.NoError(t, err)
+	require.Empty(t, failures)
+}
+
 // nolint:gocritic
 func accountDeletedHandler(t *testing.T) {
-	b, deviceSvc, _, regClient, ipfs, teardown := startupT(t)
+	b, em, _, deviceSvc, _, regClient, ipfs, teardown := startupT(t)
 
 	ctx := context.Background()
 	ctx = auth.SetClaims(ctx, auth.Claims{
@@ -159,6 +306,8 @@ func accountDeletedHandler(t *testing.T) {
 	err = b.Emit(ctx, events.AccountDeleted, accountAddress)
 	require.NoError(t, err)
 
+	em.Wait()
+
 	devices, err = deviceSvc.GetByAddress(ctx, accountAddress)
 	require.NoError(t, err)
 
@@ -168,7 +317,119 @@ func accountDeletedHandler(t *testing.T) {
 }
 
 // nolint:gocritic
-func startupT(t *testing.T) (*bus.Bus, *device.Service, *mocks.Client, *regclient.MockClient, *ipfsclinet.IPFS, func()) {
+func failedEventsRetry(t *testing.T) {
+	b, em, _, deviceSvc, nodeClientMock, regClient, _, teardown := startupT(t)
+	defer teardown()
+
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	accountAddress := "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg"
+
+	regClient.EXPECT().GetPublicKey(gomock.Any(), gomock.Eq(&pbacc.GetPublicKeyRequest{Address: accountAddress})).AnyTimes().
+		Return(&pbacc.GetPublicKeyResponse{}, nil)
+
+	t.Log("Keeps the event after all attempts failed")
+	{
+		nodeClientMock.On("GetNFTByAddress", mock.Anything, accountAddress).
+			Return(nil, errors.New("node is not available")).
+			Twice()
+
+		require.NoError(t, b.Emit(ctx, events.AccountCreated, accountAddress))
+
+		em.Wait()
+
+		failures, err := em.Failures(ctx)
+		require.NoError(t, err)
+		require.Len(t, failures, 1)
+		require.Equal(t, events.AccountSyncHandler, failures[0].Handler)
+		require.Equal(t, "1", failures[0].ProfileID)
+		require.Equal(t, 2, failures[0].Attempts)
+		require.Contains(t, failures[0].Error, "node is not available")
+	}
+
+	t.Log("Handles the event again on retry")
+	{
+		nodeClientMock.On("GetNFTByAddress", mock.Anything, accountAddress).
+			Return([]obadatypes.NFT{}, nil).
+			Once()
+
+		retried, err := em.Retry(ctx, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, retried.Queued)
+
+		em.Wait()
+
+		failures, err := em.Failures(ctx)
+		require.NoError(t, err)
+		require.Empty(t, failures)
+
+		devices, err := deviceSvc.GetByAddress(ctx, accountAddress)
+		require.NoError(t, err)
+		require.Empty(t, devices)
+	}
+}
+
+// nolint:gocritic
+func failedPublishRetry(t *testing.T) {
+	sink := &testSink{}
+
+	b, em, _, _, _, _, _, teardown := startupWithSinkT(t, sink)
+	defer teardown()
+
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	t.Log("Publishes the event again when the sink failed and keeps the order of the profile events")
+	{
+		sink.setFails(1)
+
+		require.NoError(t, b.Emit(ctx, events.NftMinted, "did:obada:1"))
+		require.NoError(t, b.Emit(ctx, events.NftMinted, "did:obada:2"))
+
+		em.Wait()
+
+		require.Equal(t, []any{"did:obada:1", "did:obada:2"}, sink.published(events.NftMinted))
+
+		failures, err := em.Failures(ctx)
+		require.NoError(t, err)
+		require.Empty(t, failures)
+	}
+
+	t.Log("Keeps the event when the sink is down and publishes it on retry")
+	{
+		sink.setFails(100)
+
+		require.NoError(t, b.Emit(ctx, events.NftMinted, "did:obada:3"))
+
+		em.Wait()
+
+		failures, err := em.Failures(ctx)
+		require.NoError(t, err)
+		require.Len(t, failures, 1)
+		require.Equal(t, events.NftMintedHandler, failures[0].Handler)
+		require.Contains(t, failures[0].Error, "sink is not available")
+
+		sink.setFails(0)
+
+		_, err = em.Retry(ctx, nil)
+		require.NoError(t, err)
+
+		em.Wait()
+
+		require.Equal(t, []any{"did:obada:1", "did:obada:2", "did:obada:3"}, sink.published(events.NftMinted))
+	}
+}
+
+// nolint:gocritic
+func startupT(t *testing.T) (*bus.Bus, *handlers.EventManager, *account.Service, *device.Service, *mocks.Client, *regclient.MockClient,
+	*ipfsclinet.IPFS, func(),
+) {
+	return startupWithSinkT(t, &testSink{})
+}
+
+// nolint:gocritic
+func startupWithSinkT(t *testing.T, sink events.EventSink) (*bus.Bus, *handlers.EventManager, *account.Service, *device.Service,
+	*mocks.Client, *regclient.MockClient, *ipfsclinet.IPFS, func(),
+) {
 	var fn bus.Next = func() string { return "afakeid" }
 	b, err := bus.NewBus(fn)
 	require.NoError(t, err, "Cannot initialize event bus")
@@ -198,22 +459,35 @@ func startupT(t *testing.T) (*bus.Bus, *device.Service, *mocks.Client, *regclien
 
 	blockchainSvc := blockchain.NewService(nodeClient, logger, "")
 
-	dbs, _ := redismock.NewClientMock()
+	kr := keyring.NewInMemory(cosmostestutil.MakeTestEncodingConfig().Codec)
+
+	accountSvc := account.NewService(validator, database, nodeClient, kr, b)
 
-	handlers.Initialize(handlers.Config{
-		Bus:         b,
-		Logger:      logger,
-		RedisClient: dbs,
+	em := handlers.Initialize(handlers.Config{
+		Bus:    b,
+		Logger: logger,
+		Sink:   sink,
+		DB:     database,
+
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
 
 		// Services
+		AccountSvc:    accountSvc,
 		DeviceSvc:     deviceSvc,
 		BlockchainSvc: blockchainSvc,
 		Registry:      mockclient,
 	})
 
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go em.Run(ctx)
+
 	teardown := func() {
+		cancel()
+		<-em.Done()
 		lgDefer()
 	}
 
-	return b, deviceSvc, nodeClient, mockclient, ipfs, teardown
+	return b, em, accountSvc, deviceSvc, nodeClient, mockclient, ipfs, teardown
 }
@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/mustafaturan/bus/v3"
+)
+
+//nolint:gochecknoinits //needed for the test
+func init() {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("obada", "obada"+sdk.PrefixPublic)
+	config.Seal()
+}
+
+// testSink keeps published events, publishing fails while fails is above zero
+type testSink struct {
+	mu     sync.Mutex
+	fails  int
+	events []bus.Event
+}
+
+func (s *testSink) Publish(_ context.Context, e bus.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("sink is not available")
+	}
+
+	s.events = append(s.events, e)
+
+	return nil
+}
+
+func (s *testSink) setFails(fails int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fails = fails
+}
+
+// published returns data of the published events of the topic
+func (s *testSink) published(topic string) []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data []any
+
+	for _, e := range s.events {
+		if e.Topic == topic {
+			data = append(data, e.Data)
+		}
+	}
+
+	return data
+}
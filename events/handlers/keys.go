@@ -0,0 +1,9 @@
+package handlers
+
+import "fmt"
+
+const failurePrefix = "event-failures:"
+
+func makeFailureKey(eventID, handler string) []byte {
+	return []byte(fmt.Sprintf(failurePrefix+"%s:%s", eventID, handler))
+}
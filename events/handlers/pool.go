@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/device"
+	db "github.com/tendermint/tm-db"
+)
+
+const (
+	// DefaultWorkers how many events are handled concurrently
+	DefaultWorkers = 4
+
+	// DefaultQueueSize how many events wait for every worker
+	DefaultQueueSize = 1000
+
+	// DefaultTimeout of the single handler attempt, the account sync imports every NFT of the account
+	DefaultTimeout = time.Minute
+
+	// DefaultMaxAttempts how many times the handler is attempted before the event is kept as failure
+	DefaultMaxAttempts = 5
+
+	// DefaultMinBackoff delay before the first retry, doubled with each attempt
+	DefaultMinBackoff = time.Second
+
+	// DefaultMaxBackoff maximum delay between retries
+	DefaultMaxBackoff = time.Minute
+)
+
+type handlerFunc func(ctx context.Context, e bus.Event) error
+
+// job is the event waiting for the handler, the claims of the emitter identify the profile of the event
+type job struct {
+	handler string
+	event   bus.Event
+	claims  auth.Claims
+}
+
+// handle registers the bus handler that queues events for the worker pool
+func (em *EventManager) handle(key, matcher string, fn handlerFunc) {
+	em.handlers[key] = fn
+
+	em.b.RegisterHandler(key, bus.Handler{
+		Handle: func(ctx context.Context, e bus.Event) {
+			em.enqueue(job{handler: key, event: e, claims: auth.GetClaims(ctx)})
+		},
+		Matcher: matcher,
+	})
+}
+
+// enqueue never blocks the emitter, the event is kept as failure when the queue is full
+func (em *EventManager) enqueue(j job) {
+	em.pending.Add(1)
+
+	select {
+	case em.queue(j.claims.UserID) <- j:
+	default:
+		em.pending.Done()
+		em.saveFailure(j, 0, ErrQueueFull)
+	}
+}
+
+// queue returns the queue of the worker that handles events of the profile, so events of the same profile
+// reach the sink in the order they were emitted. Retries of the failed handler delay next events of the profile.
+func (em *EventManager) queue(profileID string) chan job {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(profileID))
+
+	return em.jobs[h.Sum32()%uint32(len(em.jobs))]
+}
+
+// Run handles queued events until the context is cancelled, events that are still queued are kept as failures
+func (em *EventManager) Run(ctx context.Context) {
+	defer close(em.done)
+
+	workers := make(chan struct{}, len(em.jobs))
+
+	for _, jobs := range em.jobs {
+		go func(jobs chan job) {
+			defer func() { workers <- struct{}{} }()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-jobs:
+					em.process(ctx, j)
+					em.pending.Done()
+				}
+			}
+		}(jobs)
+	}
+
+	for range em.jobs {
+		<-workers
+	}
+
+	for _, jobs := range em.jobs {
+		em.drain(ctx, jobs)
+	}
+}
+
+// drain keeps events that are still queued as failures
+func (em *EventManager) drain(ctx context.Context, jobs chan job) {
+	for {
+		select {
+		case j := <-jobs:
+			em.saveFailure(j, 0, ctx.Err())
+			em.pending.Done()
+		default:
+			return
+		}
+	}
+}
+
+// Done is closed when Run returns
+func (em *EventManager) Done() <-chan struct{} {
+	return em.done
+}
+
+// Wait blocks until all queued events are handled
+func (em *EventManager) Wait() {
+	em.pending.Wait()
+}
+
+// process attempts the handler with the exponential backoff
+func (em *EventManager) process(ctx context.Context, j job) {
+	backoff := em.minBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := em.attempt(ctx, j)
+		if err == nil {
+			return
+		}
+
+		if attempt >= em.maxAttempts || ctx.Err() != nil {
+			em.saveFailure(j, attempt, err)
+			return
+		}
+
+		em.logger.Warnw("event handler failed", "EVENT", j.event.Topic, "handler", j.handler, "attempt", attempt, "error", err)
+
+		select {
+		case <-ctx.Done():
+			em.saveFailure(j, attempt, err)
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > em.maxBackoff {
+			backoff = em.maxBackoff
+		}
+	}
+}
+
+func (em *EventManager) attempt(ctx context.Context, j job) error {
+	fn, ok := em.handlers[j.handler]
+	if !ok {
+		return ErrUnknownHandler
+	}
+
+	ctx, cancel := context.WithTimeout(auth.SetClaims(ctx, j.claims), em.timeout)
+	defer cancel()
+
+	return fn(ctx, j.event)
+}
+
+// saveFailure keeps the event for the manual retry
+func (em *EventManager) saveFailure(j job, attempts int, cause error) {
+	em.logger.Errorw("event handler failed", "EVENT", j.event.Topic, "handler", j.handler, "attempts", attempts, "error", cause)
+
+	data, err := json.Marshal(j.event.Data)
+	if err != nil {
+		em.logger.Errorw("cannot encode failed event", "EVENT", j.event.Topic, "handler", j.handler, "error", err)
+		return
+	}
+
+	failure := svcs.EventFailure{
+		EventID:    j.event.ID,
+		Event:      j.event.Topic,
+		Handler:    j.handler,
+		ProfileID:  j.claims.UserID,
+		Data:       data,
+		Attempts:   attempts,
+		OccurredAt: j.event.OccurredAt,
+		FailedAt:   time.Now().UTC(),
+	}
+
+	if cause != nil {
+		failure.Error = cause.Error()
+	}
+
+	b, err := json.Marshal(failure)
+	if err != nil {
+		em.logger.Errorw("cannot encode failed event", "EVENT", j.event.Topic, "handler", j.handler, "error", err)
+		return
+	}
+
+	if err := em.db.SetSync(makeFailureKey(failure.EventID, failure.Handler), b); err != nil {
+		em.logger.Errorw("cannot save failed event", "EVENT", j.event.Topic, "handler", j.handler, "error", err)
+	}
+}
+
+// Failures returns events that were not handled, ordered by the event ID
+func (em *EventManager) Failures(_ context.Context) ([]svcs.EventFailure, error) {
+	failures := make([]svcs.EventFailure, 0)
+
+	prefixDB := db.NewPrefixDB(em.db, []byte(failurePrefix))
+
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return failures, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		var failure svcs.EventFailure
+
+		if err := json.Unmarshal(itr.Value(), &failure); err != nil {
+			return failures, err
+		}
+
+		failures = append(failures, failure)
+	}
+
+	return failures, nil
+}
+
+// Retry queues failed events again, all failures are retried when eventIDs is empty
+func (em *EventManager) Retry(ctx context.Context, eventIDs []string) (svcs.RetriedEvents, error) {
+	var retried svcs.RetriedEvents
+
+	failures, err := em.Failures(ctx)
+	if err != nil {
+		return retried, err
+	}
+
+	selected := make(map[string]bool, len(eventIDs))
+	for _, id := range eventIDs {
+		selected[id] = true
+	}
+
+	for _, failure := range failures {
+		if len(selected) > 0 && !selected[failure.EventID] {
+			continue
+		}
+
+		j, err := failedJob(failure)
+		if err != nil {
+			return retried, err
+		}
+
+		// the failure is saved again when the handler fails
+		if err := em.db.DeleteSync(makeFailureKey(failure.EventID, failure.Handler)); err != nil {
+			return retried, err
+		}
+
+		em.enqueue(j)
+
+		retried.Queued++
+	}
+
+	return retried, nil
+}
+
+// failedJob restores the job of the failure, device events carry the device, other events carry the address or DID
+func failedJob(failure svcs.EventFailure) (job, error) {
+	e := bus.Event{
+		ID:         failure.EventID,
+		Topic:      failure.Event,
+		OccurredAt: failure.OccurredAt,
+	}
+
+	if failure.Event == events.DeviceSaved {
+		var evt device.DeviceSaved
+
+		if err := json.Unmarshal(failure.Data, &evt); err != nil {
+			return job{}, err
+		}
+
+		e.Data = evt
+	} else {
+		var data string
+
+		if err := json.Unmarshal(failure.Data, &data); err != nil {
+			return job{}, err
+		}
+
+		e.Data = data
+	}
+
+	return job{
+		handler: failure.Handler,
+		event:   e,
+		claims:  auth.Claims{UserID: failure.ProfileID},
+	}, nil
+}
@@ -0,0 +1,172 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/device"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+)
+
+func (l *Listener) registerHandlers() {
+	l.handlers.Register(&obadatypes.MsgMintNFT{}, l.mintHandler)
+	l.handlers.Register(&obadatypes.MsgBatchMintNFT{}, l.batchMintHandler)
+	l.handlers.Register(&obadatypes.MsgUpdateUriHash{}, l.updateURIHashHandler)
+	l.handlers.Register(&obadatypes.MsgTransferNFT{}, l.transferHandler)
+	l.handlers.Register(&obadatypes.MsgBatchTransferNFT{}, l.batchTransferHandler)
+}
+
+func (l *Listener) mintHandler(ctx context.Context, _ Tx, m sdk.Msg) error {
+	msg := m.(*obadatypes.MsgMintNFT)
+
+	// for future refactoring
+	_ = l.bus.Emit(l.ownerCtx(ctx, msg.Creator), events.NftMinted, msg.Id)
+
+	l.logger.Infow("obit was minted", "data", msg)
+
+	return nil
+}
+
+func (l *Listener) batchMintHandler(ctx context.Context, _ Tx, m sdk.Msg) error {
+	msg := m.(*obadatypes.MsgBatchMintNFT)
+
+	var errs []error
+
+	for _, nft := range msg.Nft {
+		_ = l.bus.Emit(l.ownerCtx(ctx, msg.Creator), events.NftMinted, nft.Id)
+
+		errs = append(errs, l.importDevice(ctx, nft.Id, msg.Creator))
+	}
+
+	l.logger.Infow("batch of obits was minted", "count", len(msg.Nft), "creator", msg.Creator)
+
+	return errors.Join(errs...)
+}
+
+func (l *Listener) updateURIHashHandler(ctx context.Context, _ Tx, m sdk.Msg) error {
+	msg := m.(*obadatypes.MsgUpdateUriHash)
+
+	// for future refactoring
+	_ = l.bus.Emit(l.ownerCtx(ctx, msg.Editor), events.NftMetadataUpdated, msg.Id)
+
+	if err := l.importDevice(ctx, msg.Id, msg.Editor); err != nil {
+		return err
+	}
+
+	l.logger.Infow("obit metadata were updated", "data", msg)
+
+	return nil
+}
+
+func (l *Listener) transferHandler(ctx context.Context, tx Tx, m sdk.Msg) error {
+	msg := m.(*obadatypes.MsgTransferNFT)
+
+	// for future refactoring
+	_ = l.bus.Emit(l.ownerCtx(ctx, msg.Receiver, msg.Sender), events.NftTransfered, msg.Id)
+
+	if err := l.markTransferred(ctx, tx, msg.Id, msg.Sender, msg.Receiver); err != nil {
+		return err
+	}
+
+	if err := l.importDevice(ctx, msg.Id, msg.Receiver); err != nil {
+		return err
+	}
+
+	l.logger.Infow("nft was received", "nft", msg.Id)
+
+	return nil
+}
+
+func (l *Listener) batchTransferHandler(ctx context.Context, tx Tx, m sdk.Msg) error {
+	msg := m.(*obadatypes.MsgBatchTransferNFT)
+
+	var errs []error
+
+	for _, id := range msg.Ids {
+		_ = l.bus.Emit(l.ownerCtx(ctx, msg.Receiver, msg.Sender), events.NftTransfered, id)
+
+		errs = append(errs, l.markTransferred(ctx, tx, id, msg.Sender, msg.Receiver))
+		errs = append(errs, l.importDevice(ctx, id, msg.Receiver))
+	}
+
+	l.logger.Infow("batch of nfts was received", "count", len(msg.Ids), "receiver", msg.Receiver)
+
+	return errors.Join(errs...)
+}
+
+// markTransferred marks the device of the local sender as transferred, senders
+// managed outside of client-helper are skipped
+func (l *Listener) markTransferred(ctx context.Context, tx Tx, did, sender, receiver string) error {
+	profileID, err := l.accountSvc.GetProfileByAddress(sender)
+	if err != nil {
+		if errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot find profile of %s: %w", sender, err)
+	}
+
+	authCtx := auth.SetClaims(ctx, auth.Claims{UserID: profileID})
+
+	err = l.deviceSvc.MarkTransferred(authCtx, did, services.DeviceHistory{
+		Counterparty: receiver,
+		TxHash:       tx.Hash,
+		Height:       tx.Height,
+	})
+	if err != nil {
+		// the device was already removed, e.g. transfer was done through the API
+		if errors.Is(err, device.ErrDeviceNotExists) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot mark device %s as transferred: %w", did, err)
+	}
+
+	l.logger.Infow("nft was sent", "nft", did, "receiver", receiver)
+
+	return nil
+}
+
+// importDevice imports the current NFT state to the profile that owns the address, addresses
+// managed outside of client-helper are skipped
+func (l *Listener) importDevice(ctx context.Context, did, address string) error {
+	profileID, err := l.accountSvc.GetProfileByAddress(address)
+	if err != nil {
+		if errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot find profile of %s: %w", address, err)
+	}
+
+	nft, err := l.nodeClient.GetNFT(ctx, did)
+	if err != nil {
+		return fmt.Errorf("cannot get NFT %s: %w", did, err)
+	}
+
+	authCtx := auth.SetClaims(ctx, auth.Claims{UserID: profileID})
+
+	if err := l.deviceSvc.ImportDevice(authCtx, *nft, address); err != nil {
+		return fmt.Errorf("cannot import device %s: %w", did, err)
+	}
+
+	return nil
+}
+
+// ownerCtx sets claims of the profile that owns the first local address, so event
+// subscribers know whom the event belongs to
+func (l *Listener) ownerCtx(ctx context.Context, addresses ...string) context.Context {
+	for _, address := range addresses {
+		if profileID, err := l.accountSvc.GetProfileByAddress(address); err == nil {
+			return auth.SetClaims(ctx, auth.Claims{UserID: profileID})
+		}
+	}
+
+	return ctx
+}
@@ -0,0 +1,351 @@
+package listener
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+const (
+	// pageSize how many transactions are fetched per request during the catch-up
+	pageSize = 100
+
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+
+	// maxAttempts how many times handlers get the transaction before it is stored as failed and skipped
+	maxAttempts = 5
+
+	// failedPrefix is the key prefix of transactions that handlers failed to handle
+	failedPrefix = "listener:failed:"
+)
+
+// cursorKey stores position of the last processed transaction
+var cursorKey = []byte("listener:cursor")
+
+// ErrDisconnected is returned when the event source was disconnected from the node
+var ErrDisconnected = errors.New("event source was disconnected from the node")
+
+// Config listener dependencies
+type Config struct {
+	Source     EventSource
+	DB         db.DB
+	Logger     *zap.SugaredLogger
+	NodeClient obadanode.Client
+	DeviceSvc  *device.Service
+	AccountSvc *account.Service
+	Bus        *bus.Bus
+}
+
+// Listener listens the event source for committed transactions and passes their messages to the registered handlers.
+// The position of the last processed transaction is persisted, so transactions committed while
+// client-helper was offline or the websocket was disconnected are replayed on (re)connect.
+// A transaction that handlers failed to handle is passed to them again on the next catch-up, after
+// maxAttempts failures it is stored under the failedPrefix key and skipped. The delivery is at-least-once:
+// messages of the retried transaction that were handled already are handled again and their bus events
+// are emitted again, so handlers and event subscribers must tolerate duplicates.
+type Listener struct {
+	source     EventSource
+	txDecoder  sdk.TxDecoder
+	db         db.DB
+	logger     *zap.SugaredLogger
+	nodeClient obadanode.Client
+	deviceSvc  *device.Service
+	accountSvc *account.Service
+	bus        *bus.Bus
+
+	handlers *Registry
+	cursor   txCursor
+	done     chan struct{}
+
+	// failing is the position of the transaction that handlers failed to handle, attempts are counted per position
+	failing  txCursor
+	attempts int
+}
+
+// New creates a listener with handlers of the obit messages registered
+func New(cfg Config) *Listener {
+	l := &Listener{
+		source:     cfg.Source,
+		txDecoder:  obadanode.NewTxEncoding().TxConfig.TxDecoder(),
+		db:         cfg.DB,
+		logger:     cfg.Logger,
+		nodeClient: cfg.NodeClient,
+		deviceSvc:  cfg.DeviceSvc,
+		accountSvc: cfg.AccountSvc,
+		bus:        cfg.Bus,
+		handlers:   NewRegistry(),
+		done:       make(chan struct{}),
+	}
+
+	l.registerHandlers()
+
+	return l
+}
+
+// Handlers returns the registry of message handlers
+func (l *Listener) Handlers() *Registry {
+	return l.handlers
+}
+
+// txCursor is the position of the last processed transaction in the chain
+type txCursor struct {
+	Height int64 `json:"height"`
+	Index  int64 `json:"index"`
+}
+
+// processed reports whether the transaction at the given position was already processed
+func (c txCursor) processed(height int64, index uint32) bool {
+	return height < c.Height || (height == c.Height && int64(index) <= c.Index)
+}
+
+// failedTx is the transaction that handlers failed to handle in all attempts, it is kept for the investigation
+type failedTx struct {
+	Hash   string `json:"hash"`
+	Height int64  `json:"height"`
+	Index  uint32 `json:"index"`
+	Error  string `json:"error"`
+}
+
+// Run listens the node until the context is canceled, reconnecting with exponential backoff
+func (l *Listener) Run(ctx context.Context) {
+	defer close(l.done)
+
+	backoff := minBackoff
+
+	for {
+		live, err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if live {
+			backoff = minBackoff
+		}
+
+		l.logger.Errorw("chain listener", "error", err, "retry_in", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// Done is closed when the listener is stopped
+func (l *Listener) Done() <-chan struct{} {
+	return l.done
+}
+
+// listen subscribes to the committed transactions, replays missed transactions and then streams live transactions.
+// Returns true if the listener reached the live streaming before the error happened.
+func (l *Listener) listen(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscription happens before the catch-up, so transactions committed during the catch-up are not lost
+	txs, err := l.source.Subscribe(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if er := l.CatchUp(ctx, 0); er != nil {
+		return false, er
+	}
+
+	l.logger.Infow("chain listener", "status", "streaming", "height", l.cursor.Height)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+
+		case tx, ok := <-txs:
+			if !ok {
+				return true, ErrDisconnected
+			}
+
+			// some blocks were missed, e.g. the node was restarted between the catch-up and the subscription
+			if tx.Height > l.cursor.Height+1 {
+				if er := l.CatchUp(ctx, tx.Height-1); er != nil {
+					return true, er
+				}
+			}
+
+			if er := l.process(ctx, tx); er != nil {
+				return true, er
+			}
+		}
+	}
+}
+
+// CatchUp processes committed transactions from the last processed one up to the given height,
+// zero height means the latest block. On the first run the listener starts from the latest block.
+func (l *Listener) CatchUp(ctx context.Context, toHeight int64) error {
+	if err := l.loadCursor(ctx); err != nil {
+		return err
+	}
+
+	if toHeight == 0 {
+		latest, err := l.source.LatestHeight(ctx)
+		if err != nil {
+			return err
+		}
+
+		toHeight = latest
+	}
+
+	fromHeight := l.cursor.Height
+	if fromHeight > toHeight {
+		return nil
+	}
+
+	l.logger.Infow("chain listener", "status", "catching up", "from", fromHeight, "to", toHeight)
+
+	if err := l.source.Txs(ctx, fromHeight, toHeight, func(tx TxEvent) error {
+		return l.process(ctx, tx)
+	}); err != nil {
+		return err
+	}
+
+	// blocks without transactions were scanned too, so they will not be requested again
+	if l.cursor.Height < toHeight {
+		return l.saveCursor(txCursor{Height: toHeight, Index: -1})
+	}
+
+	return nil
+}
+
+// process handles the transaction once and moves the cursor forward. The cursor stays at the transaction
+// that handlers failed to handle, so the next catch-up passes it to handlers again, messages that were
+// handled already are passed again too.
+func (l *Listener) process(ctx context.Context, tx TxEvent) error {
+	if l.cursor.processed(tx.Height, tx.Index) {
+		return nil
+	}
+
+	position := txCursor{Height: tx.Height, Index: int64(tx.Index)}
+
+	// failed transactions did not change the state
+	if tx.Code == 0 {
+		if err := l.handle(ctx, tx); err != nil {
+			if l.failing != position {
+				l.failing, l.attempts = position, 0
+			}
+
+			if l.attempts++; l.attempts < maxAttempts {
+				return fmt.Errorf("cannot handle tx at height %d: %w", tx.Height, err)
+			}
+
+			if err := l.saveFailed(tx, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return l.saveCursor(position)
+}
+
+func (l *Listener) handle(ctx context.Context, txe TxEvent) error {
+	tx, err := l.txDecoder(txe.Tx)
+	if err != nil {
+		// the transaction cannot be decoded on the next attempt either
+		l.logger.Errorw("decoding tx", "error", err)
+		return nil
+	}
+
+	t := Tx{
+		Hash:   txHash(txe),
+		Height: txe.Height,
+	}
+
+	var errs []error
+
+	for _, msg := range tx.GetMsgs() {
+		if err := l.handlers.Handle(ctx, t, msg); err != nil {
+			l.logger.Errorw("cannot handle tx message", "hash", t.Hash, "msg", msg, "error", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// saveFailed stores the transaction that handlers failed to handle in all attempts, so the listener can move on
+func (l *Listener) saveFailed(txe TxEvent, handleErr error) error {
+	failed := failedTx{
+		Hash:   txHash(txe),
+		Height: txe.Height,
+		Index:  txe.Index,
+		Error:  handleErr.Error(),
+	}
+
+	b, err := json.Marshal(failed)
+	if err != nil {
+		return err
+	}
+
+	if err := l.db.SetSync([]byte(failedPrefix+failed.Hash), b); err != nil {
+		return fmt.Errorf("cannot save failed tx: %w", err)
+	}
+
+	l.logger.Errorw("tx is skipped after failed attempts", "hash", failed.Hash, "height", failed.Height, "attempts", l.attempts)
+
+	return nil
+}
+
+func txHash(txe TxEvent) string {
+	return fmt.Sprintf("%X", tmtypes.Tx(txe.Tx).Hash())
+}
+
+// loadCursor reads the persisted cursor, the listener starts from the latest block on the first run
+func (l *Listener) loadCursor(ctx context.Context) error {
+	b, err := l.db.Get(cursorKey)
+	if err != nil {
+		return err
+	}
+
+	if b != nil {
+		return json.Unmarshal(b, &l.cursor)
+	}
+
+	latest, err := l.source.LatestHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	// transactions of the latest block were committed before the listener started
+	return l.saveCursor(txCursor{Height: latest, Index: math.MaxUint32})
+}
+
+func (l *Listener) saveCursor(cursor txCursor) error {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	if err := l.db.SetSync(cursorKey, b); err != nil {
+		return fmt.Errorf("cannot save listener cursor: %w", err)
+	}
+
+	l.cursor = cursor
+
+	return nil
+}
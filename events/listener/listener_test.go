@@ -0,0 +1,447 @@
+package listener_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/golang/mock/gomock"
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/events/listener"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/device"
+	ipfsmocks "github.com/obada-foundation/client-helper/system/ipfs/mocks"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/obada-foundation/client-helper/testutil"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
+	regclient "github.com/obada-foundation/registry/client/mock"
+	"github.com/obada-foundation/sdkgo/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tm-db"
+)
+
+const (
+	testDID = "did:obada:64925be84b586363670c1f7e5ada86a37904e590d1f6570d834436331dd3eb88"
+	testUSN = "25rc8AxGbLSr"
+	testCID = "bafkreibdklgsqwqv5xci6cmx46j2y3to5y5sqbuoqz7g2qpgjthzgwrz4i"
+
+	eventTimeout = 5 * time.Second
+)
+
+//nolint:gochecknoinits //needed for the test
+func init() {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("obada", "obada"+sdk.PrefixPublic)
+	config.Seal()
+}
+
+type testListener struct {
+	*listener.Listener
+
+	source    *listener.MemorySource
+	deviceSvc *device.Service
+	events    chan bus.Event
+	sender    string
+	receiver  string
+}
+
+func startupT(t *testing.T) (*testListener, func()) {
+	var fn bus.Next = func() string { return "afakeid" }
+	b, err := bus.NewBus(fn)
+	require.NoError(t, err)
+
+	b.RegisterTopics(events.DeviceSaved, events.NftMinted, events.NftTransfered, events.NftMetadataUpdated)
+
+	evts := make(chan bus.Event, 100)
+
+	b.RegisterHandler("test", bus.Handler{
+		Handle: func(_ context.Context, e bus.Event) {
+			evts <- e
+		},
+		Matcher: ".*",
+	})
+
+	logger, deleteLogFile := testutil.MakeLoger()
+
+	database := db.NewMemDB()
+
+	validator, err := validate.NewValidator()
+	require.NoError(t, err)
+
+	kr := keyring.NewInMemory(cosmostestutil.MakeTestEncodingConfig().Codec)
+
+	// keys are named by the profile that owns them
+	sender, _, err := kr.NewMnemonic("1_0", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+
+	receiver, _, err := kr.NewMnemonic("2_0", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+
+	senderAddress, err := sender.GetAddress()
+	require.NoError(t, err)
+
+	receiverAddress, err := receiver.GetAddress()
+	require.NoError(t, err)
+
+	nodeClient := mocks.NewClient(t)
+
+	data, err := codectypes.NewAnyWithValue(&obadatypes.NFTData{Usn: testUSN})
+	require.NoError(t, err)
+
+	nodeClient.On("GetNFT", mock.Anything, testDID).Return(&obadatypes.NFT{
+		ClassId: "OBD",
+		Id:      testDID,
+		Data:    data,
+	}, nil).Maybe()
+
+	ipfs := &ipfsmocks.IPFS{}
+	ipfs.On("GetDocument", testCID).
+		Return([]byte(`{"serial_number":"SN123456X", "manufacturer":"Sony", "part_number":"PN123456S"}`), nil).
+		Maybe()
+
+	regClient := regclient.NewMockClient(gomock.NewController(t))
+	regClient.EXPECT().Get(gomock.Any(), gomock.Eq(&diddoc.GetRequest{Did: testDID})).AnyTimes().
+		Return(&diddoc.GetResponse{
+			Document: &diddoc.DIDDocument{
+				Id: testDID,
+				Metadata: &diddoc.Metadata{
+					Objects: []*diddoc.Object{{
+						Url: "ipfs://" + testCID,
+						Metadata: map[string]string{
+							"type": string(asset.PhysicalAssetIdentifiers),
+							"name": string(asset.PhysicalAssetIdentifiers),
+						},
+					}},
+				},
+			},
+		}, nil)
+
+	deviceSvc := device.NewService(device.Config{
+		Validator: validator,
+		DB:        database,
+		IPFS:      ipfs,
+		Bus:       b,
+		Registry:  regClient,
+	})
+
+	source := listener.NewMemorySource()
+
+	l := listener.New(listener.Config{
+		Source:     source,
+		DB:         database,
+		Logger:     logger,
+		NodeClient: nodeClient,
+		DeviceSvc:  deviceSvc,
+		AccountSvc: account.NewService(validator, database, nodeClient, kr, b),
+		Bus:        b,
+	})
+
+	return &testListener{
+		Listener:  l,
+		source:    source,
+		deviceSvc: deviceSvc,
+		events:    evts,
+		sender:    senderAddress.String(),
+		receiver:  receiverAddress.String(),
+	}, deleteLogFile
+}
+
+// waitEvent waits for the bus event of the given topic, other events are skipped
+func (tl *testListener) waitEvent(t *testing.T, topic string) bus.Event {
+	timeout := time.After(eventTimeout)
+
+	for {
+		select {
+		case e := <-tl.events:
+			if e.Topic == topic {
+				return e
+			}
+		case <-timeout:
+			require.FailNowf(t, "event was not emitted", "topic: %s", topic)
+		}
+	}
+}
+
+func (tl *testListener) importDevice(ctx context.Context, address string) error {
+	data, err := codectypes.NewAnyWithValue(&obadatypes.NFTData{Usn: testUSN})
+	if err != nil {
+		return err
+	}
+
+	return tl.deviceSvc.ImportDevice(ctx, obadatypes.NFT{ClassId: "OBD", Id: testDID, Data: data}, address)
+}
+
+func TestListener_CatchUp(t *testing.T) {
+	ctx := context.Background()
+
+	tl, teardown := startupT(t)
+	defer teardown()
+
+	var (
+		handled []listener.Tx
+		fails   int
+	)
+
+	tl.Handlers().Register(&banktypes.MsgSend{}, func(_ context.Context, tx listener.Tx, _ sdk.Msg) error {
+		if fails > 0 {
+			fails--
+			return errors.New("handler failed")
+		}
+
+		handled = append(handled, tx)
+		return nil
+	})
+
+	send := banktypes.NewMsgSend(sdk.MustAccAddressFromBech32(tl.sender), sdk.MustAccAddressFromBech32(tl.receiver), sdk.NewCoins())
+
+	_, err := tl.source.Commit(send)
+	require.NoError(t, err)
+
+	t.Log("Starts from the latest block on the first run")
+	{
+		require.NoError(t, tl.CatchUp(ctx, 0))
+		require.Empty(t, handled)
+	}
+
+	t.Log("Replays transactions committed while the listener was stopped")
+	{
+		_, err := tl.source.Commit(send)
+		require.NoError(t, err)
+
+		height, err := tl.source.Commit(send)
+		require.NoError(t, err)
+
+		require.NoError(t, tl.CatchUp(ctx, 0))
+		require.Len(t, handled, 2)
+		require.Equal(t, height, handled[1].Height)
+		require.NotEmpty(t, handled[1].Hash)
+	}
+
+	t.Log("Does not process the same transaction twice")
+	{
+		require.NoError(t, tl.CatchUp(ctx, 0))
+		require.Len(t, handled, 2)
+	}
+
+	t.Log("Skips NFTs of addresses that are managed outside of client-helper")
+	{
+		foreign := sdk.AccAddress("foreign_____________").String()
+
+		_, err := tl.source.Commit(
+			&obadatypes.MsgTransferNFT{Id: testDID, Sender: tl.sender, Receiver: foreign},
+			&obadatypes.MsgUpdateUriHash{Editor: foreign, Id: testDID},
+			&obadatypes.MsgBatchMintNFT{Creator: foreign, Nft: []obadatypes.MsgBatchNFT{{Id: testDID}}},
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, tl.CatchUp(ctx, 0))
+
+		devices, err := tl.deviceSvc.GetByAddress(auth.SetClaims(ctx, auth.Claims{UserID: "1"}), foreign)
+		require.NoError(t, err)
+		require.Empty(t, devices)
+	}
+
+	t.Log("Processes the transaction on the next run when a handler failed")
+	{
+		height, err := tl.source.Commit(send)
+		require.NoError(t, err)
+
+		fails = 1
+
+		require.Error(t, tl.CatchUp(ctx, 0))
+		require.Len(t, handled, 2)
+
+		require.NoError(t, tl.CatchUp(ctx, 0))
+		require.Len(t, handled, 3)
+		require.Equal(t, height, handled[2].Height)
+	}
+
+	t.Log("Skips the transaction when handlers failed in all attempts")
+	{
+		height, err := tl.source.Commit(send)
+		require.NoError(t, err)
+
+		fails = 100
+
+		attempts := 1
+		for ; tl.CatchUp(ctx, 0) != nil; attempts++ {
+			require.Less(t, attempts, 10)
+		}
+
+		fails = 0
+
+		require.Len(t, handled, 3)
+
+		_, err = tl.source.Commit(send)
+		require.NoError(t, err)
+
+		require.NoError(t, tl.CatchUp(ctx, 0))
+		require.Len(t, handled, 4)
+		require.Greater(t, handled[3].Height, height)
+	}
+}
+
+func TestListener_Run(t *testing.T) {
+	tl, teardown := startupT(t)
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	require.NoError(t, tl.CatchUp(ctx, 0))
+
+	go tl.Run(ctx)
+
+	defer func() {
+		cancel()
+		<-tl.Done()
+	}()
+
+	senderCtx := auth.SetClaims(ctx, auth.Claims{UserID: "1"})
+	receiverCtx := auth.SetClaims(ctx, auth.Claims{UserID: "2"})
+
+	t.Log("Emits event of the minted NFT")
+	{
+		_, err := tl.source.Commit(&obadatypes.MsgMintNFT{Creator: tl.sender, Id: testDID, Usn: testUSN})
+		require.NoError(t, err)
+
+		e := tl.waitEvent(t, events.NftMinted)
+		require.Equal(t, testDID, e.Data)
+	}
+
+	t.Log("Moves transferred device from the sender to the receiver profile")
+	{
+		require.NoError(t, tl.importDevice(senderCtx, tl.sender))
+		tl.waitEvent(t, events.DeviceSaved)
+
+		height, err := tl.source.Commit(&obadatypes.MsgTransferNFT{Id: testDID, Sender: tl.sender, Receiver: tl.receiver})
+		require.NoError(t, err)
+
+		e := tl.waitEvent(t, events.NftTransfered)
+		require.Equal(t, testDID, e.Data)
+
+		e = tl.waitEvent(t, events.DeviceSaved)
+		require.Equal(t, "2", e.Data.(device.DeviceSaved).ProfileID)
+
+		sent, err := tl.deviceSvc.GetByDID(senderCtx, testDID)
+		require.NoError(t, err)
+		require.Equal(t, svcs.DeviceStatusTransferred, sent.Status)
+		require.Len(t, sent.History, 1)
+		require.Equal(t, tl.receiver, sent.History[0].Counterparty)
+		require.Equal(t, height, sent.History[0].Height)
+		require.NotEmpty(t, sent.History[0].TxHash)
+
+		devices, err := tl.deviceSvc.GetByAddress(senderCtx, tl.sender)
+		require.NoError(t, err)
+		require.Empty(t, devices)
+
+		received, err := tl.deviceSvc.GetByDID(receiverCtx, testDID)
+		require.NoError(t, err)
+		require.Equal(t, tl.receiver, received.Address)
+		require.Empty(t, received.Status)
+	}
+
+	t.Log("Catches up transactions committed while the source was disconnected")
+	{
+		tl.source.Disconnect()
+
+		_, err := tl.source.Commit(&obadatypes.MsgUpdateUriHash{Editor: tl.receiver, Id: testDID})
+		require.NoError(t, err)
+
+		e := tl.waitEvent(t, events.NftMetadataUpdated)
+		require.Equal(t, testDID, e.Data)
+
+		e = tl.waitEvent(t, events.DeviceSaved)
+		require.Equal(t, "2", e.Data.(device.DeviceSaved).ProfileID)
+	}
+}
+
+func TestRegistry_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	r := listener.NewRegistry()
+
+	var msgs []sdk.Msg
+
+	r.Register(&banktypes.MsgSend{}, func(_ context.Context, _ listener.Tx, msg sdk.Msg) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+
+	from := sdk.AccAddress("from________________")
+	to := sdk.AccAddress("to__________________")
+
+	send := banktypes.NewMsgSend(from, to, sdk.NewCoins())
+
+	t.Log("Passes message to the handler of its type")
+	{
+		require.NoError(t, r.Handle(ctx, listener.Tx{}, send))
+		require.Len(t, msgs, 1)
+	}
+
+	t.Log("Unwraps messages executed through authz")
+	{
+		exec := authz.NewMsgExec(to, []sdk.Msg{send})
+
+		require.NoError(t, r.Handle(ctx, listener.Tx{}, &exec))
+		require.Len(t, msgs, 2)
+		require.Equal(t, send, msgs[1])
+	}
+
+	t.Log("Ignores messages without handlers")
+	{
+		require.NoError(t, r.Handle(ctx, listener.Tx{}, &authz.MsgRevoke{}))
+		require.Len(t, msgs, 2)
+	}
+}
+
+func TestMemorySource_Commit(t *testing.T) {
+	source := listener.NewMemorySource()
+
+	_, err := source.Subscribe(context.Background())
+	require.NoError(t, err)
+
+	send := banktypes.NewMsgSend(sdk.AccAddress("from________________"), sdk.AccAddress("to__________________"), sdk.NewCoins())
+
+	t.Log("Does not lock the source while the subscriber is full")
+	{
+		committed := make(chan struct{})
+
+		go func() {
+			defer close(committed)
+
+			// the subscriber is never read, so the last commit blocks on the full buffer
+			for i := 0; i <= 100; i++ {
+				_, err := source.Commit(send)
+				assert.NoError(t, err)
+			}
+		}()
+
+		require.Eventually(t, func() bool {
+			height, err := source.LatestHeight(context.Background())
+			return err == nil && height == 101
+		}, eventTimeout, 10*time.Millisecond)
+
+		source.Disconnect()
+
+		select {
+		case <-committed:
+		case <-time.After(eventTimeout):
+			require.FailNow(t, "commit is blocked after the subscriber was closed")
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package listener
+
+import (
+	"context"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+)
+
+// memorySubscriptionSize how many transactions are buffered for the subscriber
+const memorySubscriptionSize = 100
+
+// MemorySource is an in-memory chain that commits transactions injected by tests
+type MemorySource struct {
+	mu     sync.Mutex
+	enc    obadanode.TxEncoding
+	height int64
+	txs    []TxEvent
+	subs   map[*memorySubscription]struct{}
+
+	// commitMu keeps transactions of concurrent commits in order without holding mu while sending
+	commitMu sync.Mutex
+}
+
+// memorySubscription is the subscriber channel, it is closed once done is closed and pending sends are finished
+type memorySubscription struct {
+	ch      chan TxEvent
+	done    chan struct{}
+	sending sync.WaitGroup
+}
+
+// NewMemorySource creates an empty in-memory chain
+func NewMemorySource() *MemorySource {
+	return &MemorySource{
+		enc:  obadanode.NewTxEncoding(),
+		subs: make(map[*memorySubscription]struct{}),
+	}
+}
+
+// Commit commits a new block with a single transaction of the given messages and returns the block height
+func (s *MemorySource) Commit(msgs ...sdk.Msg) (int64, error) {
+	txBuilder := s.enc.TxConfig.NewTxBuilder()
+
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		return 0, err
+	}
+
+	txBytes, err := s.enc.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return 0, err
+	}
+
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+
+	s.mu.Lock()
+
+	s.height++
+
+	tx := TxEvent{
+		Height: s.height,
+		Tx:     txBytes,
+	}
+
+	s.txs = append(s.txs, tx)
+
+	subs := make([]*memorySubscription, 0, len(s.subs))
+	for sub := range s.subs {
+		sub.sending.Add(1)
+		subs = append(subs, sub)
+	}
+
+	s.mu.Unlock()
+
+	// a full subscriber blocks the commit, but not the subscriber closing nor other source methods
+	for _, sub := range subs {
+		select {
+		case sub.ch <- tx:
+		case <-sub.done:
+		}
+
+		sub.sending.Done()
+	}
+
+	return tx.Height, nil
+}
+
+// Disconnect closes all subscriptions as if the node connection was lost
+func (s *MemorySource) Disconnect() {
+	s.mu.Lock()
+
+	subs := make([]*memorySubscription, 0, len(s.subs))
+	for sub := range s.subs {
+		delete(s.subs, sub)
+		close(sub.done)
+		subs = append(subs, sub)
+	}
+
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+}
+
+// LatestHeight implements the LatestHeight method of the EventSource interface
+func (s *MemorySource) LatestHeight(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.height, nil
+}
+
+// Txs implements the Txs method of the EventSource interface
+func (s *MemorySource) Txs(_ context.Context, fromHeight, toHeight int64, fn func(TxEvent) error) error {
+	s.mu.Lock()
+	txs := append([]TxEvent(nil), s.txs...)
+	s.mu.Unlock()
+
+	for _, tx := range txs {
+		if tx.Height < fromHeight || tx.Height > toHeight {
+			continue
+		}
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribe implements the Subscribe method of the EventSource interface
+func (s *MemorySource) Subscribe(ctx context.Context) (<-chan TxEvent, error) {
+	sub := &memorySubscription{
+		ch:   make(chan TxEvent, memorySubscriptionSize),
+		done: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-sub.done:
+			// closed by Disconnect
+			return
+		}
+
+		s.mu.Lock()
+		_, ok := s.subs[sub]
+		if ok {
+			delete(s.subs, sub)
+			close(sub.done)
+		}
+		s.mu.Unlock()
+
+		if ok {
+			sub.close()
+		}
+	}()
+
+	return sub.ch, nil
+}
+
+// close closes the subscriber channel after commits that are sending to it give up
+func (sub *memorySubscription) close() {
+	sub.sending.Wait()
+	close(sub.ch)
+}
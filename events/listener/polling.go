@@ -0,0 +1,78 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"go.uber.org/zap"
+)
+
+// PollingSource polls the node RPC for new blocks, for deployments where the websocket endpoint is not available
+type PollingSource struct {
+	nodeSource
+
+	interval time.Duration
+	logger   *zap.SugaredLogger
+}
+
+// NewPollingSource creates the polling event source
+func NewPollingSource(interval time.Duration, nodeClient obadanode.Client, logger *zap.SugaredLogger) *PollingSource {
+	return &PollingSource{
+		nodeSource: nodeSource{nodeClient: nodeClient},
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Subscribe implements the Subscribe method of the EventSource interface
+func (s *PollingSource) Subscribe(ctx context.Context) (<-chan TxEvent, error) {
+	lastHeight, err := s.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make(chan TxEvent)
+
+	go func() {
+		defer close(txs)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			latest, err := s.LatestHeight(ctx)
+			if err != nil {
+				s.logger.Errorw("chain listener", "error", err)
+				return
+			}
+
+			if latest <= lastHeight {
+				continue
+			}
+
+			err = s.Txs(ctx, lastHeight+1, latest, func(tx TxEvent) error {
+				select {
+				case txs <- tx:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			if err != nil {
+				s.logger.Errorw("chain listener", "error", err)
+				return
+			}
+
+			lastHeight = latest
+		}
+	}()
+
+	return txs, nil
+}
@@ -0,0 +1,64 @@
+package listener
+
+import (
+	"context"
+	"errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+// Tx committed transaction that contains the handled message
+type Tx struct {
+	Hash   string
+	Height int64
+}
+
+// MsgHandler handles a message of the committed transaction
+type MsgHandler func(ctx context.Context, tx Tx, msg sdk.Msg) error
+
+// Registry keeps message handlers by the message type URL
+type Registry struct {
+	handlers map[string][]MsgHandler
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: make(map[string][]MsgHandler),
+	}
+}
+
+// Register adds the handler of messages with the same type as msg
+func (r *Registry) Register(msg sdk.Msg, h MsgHandler) {
+	typeURL := sdk.MsgTypeURL(msg)
+
+	r.handlers[typeURL] = append(r.handlers[typeURL], h)
+}
+
+// Handle passes the message to registered handlers, messages executed
+// on behalf of another account through x/authz are unwrapped
+func (r *Registry) Handle(ctx context.Context, tx Tx, msg sdk.Msg) error {
+	if exec, ok := msg.(*authz.MsgExec); ok {
+		msgs, err := exec.GetMessages()
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+
+		for _, m := range msgs {
+			errs = append(errs, r.Handle(ctx, tx, m))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	var errs []error
+
+	for _, h := range r.handlers[sdk.MsgTypeURL(msg)] {
+		errs = append(errs, h(ctx, tx, msg))
+	}
+
+	return errors.Join(errs...)
+}
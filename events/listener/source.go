@@ -0,0 +1,63 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/obada-foundation/client-helper/system/obadanode"
+)
+
+// TxEvent is a committed transaction
+type TxEvent struct {
+	Height int64
+	Index  uint32
+	Code   uint32
+	Tx     []byte
+}
+
+// EventSource delivers committed transactions of the chain
+type EventSource interface {
+	// LatestHeight returns the height of the latest committed block
+	LatestHeight(ctx context.Context) (int64, error)
+
+	// Txs passes committed transactions of the blocks in the given height range to fn, oldest first
+	Txs(ctx context.Context, fromHeight, toHeight int64, fn func(TxEvent) error) error
+
+	// Subscribe streams transactions committed after the subscription until the context is done.
+	// The channel is closed when the source is disconnected from the node.
+	Subscribe(ctx context.Context) (<-chan TxEvent, error)
+}
+
+// nodeSource reads committed transactions from the node RPC
+type nodeSource struct {
+	nodeClient obadanode.Client
+}
+
+// LatestHeight implements the LatestHeight method of the EventSource interface
+func (s nodeSource) LatestHeight(ctx context.Context) (int64, error) {
+	return s.nodeClient.LatestHeight(ctx)
+}
+
+// Txs implements the Txs method of the EventSource interface
+func (s nodeSource) Txs(ctx context.Context, fromHeight, toHeight int64, fn func(TxEvent) error) error {
+	for page := 1; ; page++ {
+		res, err := s.nodeClient.BlockTxs(ctx, fromHeight, toHeight, page, pageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, txr := range res.Txs {
+			if err := fn(TxEvent{
+				Height: txr.Height,
+				Index:  txr.Index,
+				Code:   txr.TxResult.Code,
+				Tx:     txr.Tx,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(res.Txs) == 0 || page*pageSize >= res.TotalCount {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	tmjson "github.com/cometbft/cometbft/libs/json"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"go.uber.org/zap"
+)
+
+// query subscribes to all committed transactions
+const query = "tm.event = 'Tx'"
+
+// WebsocketSource streams committed transactions through the CometBFT websocket
+type WebsocketSource struct {
+	nodeSource
+
+	rpcURL string
+	logger *zap.SugaredLogger
+}
+
+// NewWebsocketSource creates the websocket event source
+func NewWebsocketSource(rpcURL string, nodeClient obadanode.Client, logger *zap.SugaredLogger) *WebsocketSource {
+	return &WebsocketSource{
+		nodeSource: nodeSource{nodeClient: nodeClient},
+		rpcURL:     rpcURL,
+		logger:     logger,
+	}
+}
+
+// Subscribe implements the Subscribe method of the EventSource interface
+func (s *WebsocketSource) Subscribe(ctx context.Context) (<-chan TxEvent, error) {
+	// the websocket client does not restore subscriptions after the reconnect,
+	// so the stream is closed and the listener catches up missed transactions
+	reconnected := make(chan struct{}, 1)
+
+	client, err := jsonrpcclient.NewWS(s.rpcURL, "/websocket", jsonrpcclient.OnReconnect(func() {
+		select {
+		case reconnected <- struct{}{}:
+		default:
+		}
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	if er := client.Start(); er != nil {
+		return nil, er
+	}
+
+	if er := client.Subscribe(ctx, query); er != nil {
+		_ = client.Stop()
+		return nil, er
+	}
+
+	txs := make(chan TxEvent)
+
+	go func() {
+		defer close(txs)
+
+		defer func() {
+			if client.IsRunning() {
+				unsubCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+				_ = client.UnsubscribeAll(unsubCtx)
+				cancel()
+
+				_ = client.Stop()
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-reconnected:
+				return
+
+			case resp, ok := <-client.ResponsesCh:
+				if !ok {
+					return
+				}
+
+				if resp.Error != nil {
+					s.logger.Errorw("chain listener", "error", resp.Error)
+					continue
+				}
+
+				var result ctypes.ResultEvent
+
+				if err := tmjson.Unmarshal(resp.Result, &result); err != nil {
+					s.logger.Errorw("unmarshal tx", "error", err)
+					continue
+				}
+
+				// subscription confirmation has no data
+				dataTx, ok := result.Data.(tmtypes.EventDataTx)
+				if !ok {
+					continue
+				}
+
+				select {
+				case txs <- TxEvent{
+					Height: dataTx.Height,
+					Index:  dataTx.Index,
+					Code:   dataTx.Result.Code,
+					Tx:     dataTx.Tx,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return txs, nil
+}
@@ -0,0 +1,511 @@
+package sinks
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/events/envelope"
+)
+
+// DefaultTopic is the Kafka topic that receives events
+const DefaultTopic = "client-helper.events"
+
+// SASLPlain is the SASL mechanism of the user and password authentication
+const SASLPlain = "PLAIN"
+
+// maxResponseSize limits the size of the broker response, the sink reads metadata of the single topic
+// and produce acknowledgements only
+const maxResponseSize = 8 << 20
+
+// ErrSinkClosed the sink was closed on the shutdown
+var ErrSinkClosed = errors.New("sink is closed")
+
+// KafkaConfig Kafka sink options
+type KafkaConfig struct {
+	// Brokers bootstrap brokers, host:port
+	Brokers []string
+
+	Topic    string
+	ClientID string
+	Timeout  time.Duration
+
+	TLS  TLSConfig
+	SASL SASLConfig
+}
+
+// SASLConfig SASL authentication of broker connections, the PLAIN mechanism sends the password as is,
+// so it should be used over TLS
+type SASLConfig struct {
+	// Mechanism only PLAIN is supported, it is the default when the user is set
+	Mechanism string
+
+	User     string
+	Password string
+}
+
+// Kafka produces event envelopes to the topic of any Kafka protocol compatible broker.
+// Events are keyed by the profile ID, so events of the profile keep their order within the partition.
+// The produce request waits for all in-sync replicas. Events of different partition leaders are produced concurrently,
+// requests of the broker connection are sent one at a time.
+type Kafka struct {
+	brokers  []string
+	topic    string
+	clientID string
+	timeout  time.Duration
+	tls      *tls.Config
+	sasl     SASLConfig
+
+	// mu guards the state below, it is not held during network calls
+	mu     sync.Mutex
+	conns  map[string]*brokerConn
+	closed bool
+
+	// leaders are addresses of the partition leaders, refreshed after the failed produce
+	leaders []string
+}
+
+// brokerConn is the connection to the broker, it is established with the first request
+type brokerConn struct {
+	mu            sync.Mutex
+	conn          net.Conn
+	correlationID int32
+}
+
+// NewKafka creates the Kafka sink, the connection is established with the first event
+func NewKafka(cfg KafkaConfig) (*Kafka, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka brokers are not configured")
+	}
+
+	tlsConfig, err := cfg.TLS.config()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.SASL.User != "" {
+		if cfg.SASL.Mechanism == "" {
+			cfg.SASL.Mechanism = SASLPlain
+		}
+
+		if !strings.EqualFold(cfg.SASL.Mechanism, SASLPlain) {
+			return nil, fmt.Errorf("kafka SASL mechanism %q is not supported", cfg.SASL.Mechanism)
+		}
+	}
+
+	s := &Kafka{
+		brokers:  cfg.Brokers,
+		topic:    cfg.Topic,
+		clientID: cfg.ClientID,
+		timeout:  cfg.Timeout,
+		tls:      tlsConfig,
+		sasl:     cfg.SASL,
+		conns:    make(map[string]*brokerConn),
+	}
+
+	if s.topic == "" {
+		s.topic = DefaultTopic
+	}
+
+	if s.clientID == "" {
+		s.clientID = "client-helper"
+	}
+
+	if s.timeout <= 0 {
+		s.timeout = defaultTimeout
+	}
+
+	return s, nil
+}
+
+// Publish implements the Publish method of the events.EventSink interface
+func (s *Kafka) Publish(ctx context.Context, e bus.Event) error {
+	evt, err := envelope.New(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	leaders := s.leaders
+	s.mu.Unlock()
+
+	if len(leaders) == 0 {
+		if leaders, err = s.refreshMetadata(ctx); err != nil {
+			return fmt.Errorf("cannot fetch kafka metadata: %w", err)
+		}
+
+		s.mu.Lock()
+		s.leaders = leaders
+		s.mu.Unlock()
+	}
+
+	key := []byte(evt.ProfileID)
+
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	partition := int32(h.Sum32() % uint32(len(leaders)))
+
+	batch := recordBatch(key, value, [][2]string{{"id", evt.ID}, {"type", evt.Type}}, time.Now())
+
+	if err := s.produce(ctx, leaders[partition], partition, batch); err != nil {
+		// leadership may have moved, the metadata is fetched again for the next event
+		s.mu.Lock()
+		s.leaders = nil
+		s.mu.Unlock()
+
+		return fmt.Errorf("cannot produce event to kafka: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes connections to the brokers, requests in progress are finished or time out first
+func (s *Kafka) Close() error {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[string]*brokerConn)
+	s.closed = true
+	s.mu.Unlock()
+
+	for _, bc := range conns {
+		bc.mu.Lock()
+
+		if bc.conn != nil {
+			_ = bc.conn.Close()
+			bc.conn = nil
+		}
+
+		bc.mu.Unlock()
+	}
+
+	return nil
+}
+
+// deadline returns the deadline of the single broker call
+func (s *Kafka) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(s.timeout)
+
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+
+	return deadline
+}
+
+func (s *Kafka) produce(ctx context.Context, leader string, partition int32, batch []byte) error {
+	deadline := s.deadline(ctx)
+
+	var req kafkaEncoder
+
+	req.nullString() // transactional id
+	req.int16(-1)    // acks from all in-sync replicas
+	req.int32(int32(time.Until(deadline).Milliseconds()))
+	req.int32(1)
+	req.string(s.topic)
+	req.int32(1)
+	req.int32(partition)
+	req.bytes(batch)
+
+	resp, err := s.roundTrip(ctx, deadline, leader, apiProduce, produceVersion, req.b)
+	if err != nil {
+		return err
+	}
+
+	d := kafkaDecoder{b: resp}
+
+	for topics := d.int32(); topics > 0 && d.err == nil; topics-- {
+		_ = d.string()
+
+		for partitions := d.int32(); partitions > 0 && d.err == nil; partitions-- {
+			_ = d.int32()     // partition
+			code := d.int16() // error code
+			_ = d.int64()     // base offset
+			_ = d.int64()     // log append time
+
+			if d.err == nil && code != 0 {
+				return fmt.Errorf("produce failed with error code %d", code)
+			}
+		}
+	}
+
+	return d.err
+}
+
+// refreshMetadata finds leaders of the topic partitions through the first available bootstrap broker
+func (s *Kafka) refreshMetadata(ctx context.Context) ([]string, error) {
+	var req kafkaEncoder
+
+	req.int32(1)
+	req.string(s.topic)
+
+	var errs []error
+
+	for _, broker := range s.brokers {
+		resp, err := s.roundTrip(ctx, s.deadline(ctx), broker, apiMetadata, metadataVersion, req.b)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		return s.parseMetadata(resp)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (s *Kafka) parseMetadata(resp []byte) ([]string, error) {
+	d := kafkaDecoder{b: resp}
+
+	brokers := make(map[int32]string)
+
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		nodeID := d.int32()
+		host := d.string()
+		port := d.int32()
+		_ = d.string() // rack
+
+		brokers[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+
+	_ = d.int32() // controller id
+
+	var leaders []string
+
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		code := d.int16()
+		name := d.string()
+		_ = d.int8() // is internal
+
+		partitions := d.int32()
+		leaders = make([]string, partitions)
+
+		for i := int32(0); i < partitions && d.err == nil; i++ {
+			_ = d.int16() // partition error code
+			index := d.int32()
+			leader := d.int32()
+			_ = d.int32Array() // replicas
+			_ = d.int32Array() // in-sync replicas
+
+			if index >= 0 && index < partitions {
+				leaders[index] = brokers[leader]
+			}
+		}
+
+		if d.err == nil && name == s.topic && code != 0 {
+			// e.g. the leader is not elected yet for the auto created topic
+			return nil, fmt.Errorf("topic %s metadata error code %d", s.topic, code)
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	for i, leader := range leaders {
+		if leader == "" {
+			return nil, fmt.Errorf("partition %d of topic %s has no leader", i, s.topic)
+		}
+	}
+
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("topic %s does not exist", s.topic)
+	}
+
+	return leaders, nil
+}
+
+// roundTrip sends the request to the broker and returns the response body, the request is interrupted
+// when the context is canceled
+func (s *Kafka) roundTrip(ctx context.Context, deadline time.Time, addr string, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrSinkClosed
+	}
+
+	bc, ok := s.conns[addr]
+	if !ok {
+		bc = &brokerConn{}
+		s.conns[addr] = bc
+	}
+
+	s.mu.Unlock()
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if bc.conn == nil {
+		// the sink may be closed while the previous request of the connection was in progress
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+
+		if closed {
+			return nil, ErrSinkClosed
+		}
+
+		if err := s.dial(ctx, deadline, bc, addr); err != nil {
+			return nil, err
+		}
+	}
+
+	conn := bc.conn
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	resp, err := s.exchange(bc, deadline, apiKey, apiVersion, body)
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		_ = conn.Close()
+		bc.conn = nil
+
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// exchange writes the request to the connection and reads the response, the connection is used by one call at a time
+func (s *Kafka) exchange(bc *brokerConn, deadline time.Time, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	if err := bc.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	bc.correlationID++
+
+	var req kafkaEncoder
+
+	req.int32(0) // size is set below
+	req.int16(apiKey)
+	req.int16(apiVersion)
+	req.int32(bc.correlationID)
+	req.string(s.clientID)
+	req.b = append(req.b, body...)
+
+	binary.BigEndian.PutUint32(req.b, uint32(len(req.b)-4))
+
+	if _, err := bc.conn.Write(req.b); err != nil {
+		return nil, err
+	}
+
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(bc.conn, size); err != nil {
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(size)
+	if n > maxResponseSize {
+		return nil, fmt.Errorf("kafka response of %d bytes exceeds the limit of %d bytes", n, maxResponseSize)
+	}
+
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(bc.conn, resp); err != nil {
+		return nil, err
+	}
+
+	d := kafkaDecoder{b: resp}
+
+	if correlationID := d.int32(); d.err != nil || correlationID != bc.correlationID {
+		return nil, fmt.Errorf("unexpected kafka response correlation id %d", correlationID)
+	}
+
+	return d.b, nil
+}
+
+// dial connects and authenticates the new broker connection
+func (s *Kafka) dial(ctx context.Context, deadline time.Time, bc *brokerConn, addr string) error {
+	dialer := &net.Dialer{Deadline: deadline}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if s.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tls}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	bc.conn = conn
+
+	if s.sasl.User != "" {
+		if err := s.authenticate(bc, deadline); err != nil {
+			_ = conn.Close()
+			bc.conn = nil
+
+			return fmt.Errorf("kafka SASL authentication failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// authenticate authenticates the new connection by the SASL PLAIN mechanism
+func (s *Kafka) authenticate(bc *brokerConn, deadline time.Time) error {
+	var handshake kafkaEncoder
+
+	handshake.string(SASLPlain)
+
+	resp, err := s.exchange(bc, deadline, apiSaslHandshake, saslHandshakeVersion, handshake.b)
+	if err != nil {
+		return err
+	}
+
+	d := kafkaDecoder{b: resp}
+
+	if code := d.int16(); d.err == nil && code != 0 {
+		return fmt.Errorf("broker does not accept the PLAIN mechanism, error code %d", code)
+	}
+
+	var auth kafkaEncoder
+
+	auth.bytes([]byte("\x00" + s.sasl.User + "\x00" + s.sasl.Password))
+
+	resp, err = s.exchange(bc, deadline, apiSaslAuthenticate, saslAuthenticateVersion, auth.b)
+	if err != nil {
+		return err
+	}
+
+	d = kafkaDecoder{b: resp}
+
+	code := d.int16()
+	message := d.string()
+
+	if d.err != nil {
+		return d.err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("error code %d: %s", code, message)
+	}
+
+	return nil
+}
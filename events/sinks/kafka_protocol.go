@@ -0,0 +1,185 @@
+package sinks
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"time"
+)
+
+// Kafka protocol API keys and versions used by the sink
+const (
+	apiProduce          int16 = 0
+	apiMetadata         int16 = 3
+	apiSaslHandshake    int16 = 17
+	apiSaslAuthenticate int16 = 36
+
+	produceVersion          int16 = 3
+	metadataVersion         int16 = 1
+	saslHandshakeVersion    int16 = 1
+	saslAuthenticateVersion int16 = 0
+)
+
+var errShortResponse = errors.New("kafka response is truncated")
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaEncoder builds Kafka protocol messages
+type kafkaEncoder struct {
+	b []byte
+}
+
+func (e *kafkaEncoder) int8(v int8) {
+	e.b = append(e.b, byte(v))
+}
+
+func (e *kafkaEncoder) int16(v int16) {
+	e.b = binary.BigEndian.AppendUint16(e.b, uint16(v))
+}
+
+func (e *kafkaEncoder) int32(v int32) {
+	e.b = binary.BigEndian.AppendUint32(e.b, uint32(v))
+}
+
+func (e *kafkaEncoder) int64(v int64) {
+	e.b = binary.BigEndian.AppendUint64(e.b, uint64(v))
+}
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.b = append(e.b, v...)
+}
+
+func (e *kafkaEncoder) nullString() {
+	e.int16(-1)
+}
+
+func (e *kafkaEncoder) bytes(v []byte) {
+	e.int32(int32(len(v)))
+	e.b = append(e.b, v...)
+}
+
+func (e *kafkaEncoder) varint(v int64) {
+	e.b = binary.AppendVarint(e.b, v)
+}
+
+func (e *kafkaEncoder) varintBytes(v []byte) {
+	e.varint(int64(len(v)))
+	e.b = append(e.b, v...)
+}
+
+// kafkaDecoder reads Kafka protocol messages, the first error stops decoding
+type kafkaDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	if n < 0 || len(d.b) < n {
+		d.err = errShortResponse
+		return nil
+	}
+
+	v := d.b[:n]
+	d.b = d.b[n:]
+
+	return v
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if v := d.next(1); v != nil {
+		return int8(v[0])
+	}
+
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if v := d.next(2); v != nil {
+		return int16(binary.BigEndian.Uint16(v))
+	}
+
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if v := d.next(4); v != nil {
+		return int32(binary.BigEndian.Uint32(v))
+	}
+
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if v := d.next(8); v != nil {
+		return int64(binary.BigEndian.Uint64(v))
+	}
+
+	return 0
+}
+
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+
+	return string(d.next(int(n)))
+}
+
+func (d *kafkaDecoder) int32Array() []int32 {
+	n := d.int32()
+
+	var v []int32
+
+	for i := int32(0); i < n && d.err == nil; i++ {
+		v = append(v, d.int32())
+	}
+
+	return v
+}
+
+// recordBatch encodes the message as a single record batch of the magic v2 format
+func recordBatch(key, value []byte, headers [][2]string, ts time.Time) []byte {
+	var rec kafkaEncoder
+
+	rec.int8(0)   // attributes
+	rec.varint(0) // timestamp delta
+	rec.varint(0) // offset delta
+	rec.varintBytes(key)
+	rec.varintBytes(value)
+	rec.varint(int64(len(headers)))
+
+	for _, h := range headers {
+		rec.varintBytes([]byte(h[0]))
+		rec.varintBytes([]byte(h[1]))
+	}
+
+	// fields covered by the checksum
+	var body kafkaEncoder
+
+	body.int16(0) // attributes
+	body.int32(0) // last offset delta
+	body.int64(ts.UnixMilli())
+	body.int64(ts.UnixMilli())
+	body.int64(-1) // producer id
+	body.int16(-1) // producer epoch
+	body.int32(-1) // base sequence
+	body.int32(1)  // records count
+	body.varintBytes(rec.b)
+
+	var batch kafkaEncoder
+
+	batch.int64(0)                              // base offset
+	batch.int32(int32(4 + 1 + 4 + len(body.b))) // batch length
+	batch.int32(-1)                             // partition leader epoch
+	batch.int8(2)                               // magic
+	batch.int32(int32(crc32.Checksum(body.b, castagnoli)))
+	batch.b = append(batch.b, body.b...)
+
+	return batch.b
+}
@@ -0,0 +1,398 @@
+package sinks_test
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/obada-foundation/client-helper/events/sinks"
+	"github.com/stretchr/testify/require"
+)
+
+type kafkaRecord struct {
+	topic string
+	key   []byte
+	value []byte
+}
+
+// kafkaBroker is the in-process broker that leads the single partition of every topic
+// and implements Metadata v1 and Produce v3 requests used by the sink. With the user set the broker
+// requires SASL PLAIN authentication of every connection.
+type kafkaBroker struct {
+	ln        net.Listener
+	errorCode int16
+	user      string
+	password  string
+
+	mu      sync.Mutex
+	records []kafkaRecord
+}
+
+func newKafkaBroker(t *testing.T, user, password string) *kafkaBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &kafkaBroker{ln: ln, user: user, password: password}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go b.serve(t, conn)
+		}
+	}()
+
+	t.Cleanup(func() { _ = ln.Close() })
+
+	return b
+}
+
+func (b *kafkaBroker) produced() []kafkaRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.records
+}
+
+func (b *kafkaBroker) serve(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+
+	authenticated := b.user == ""
+
+	for {
+		size := make([]byte, 4)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return
+		}
+
+		req := &reader{b: make([]byte, binary.BigEndian.Uint32(size))}
+		if _, err := io.ReadFull(conn, req.b); err != nil {
+			return
+		}
+
+		apiKey := req.int16()
+		_ = req.int16() // api version
+		correlationID := req.int32()
+		_ = req.string() // client id
+
+		resp := binary.BigEndian.AppendUint32(nil, uint32(correlationID))
+
+		switch {
+		case apiKey == 17:
+			require.Equal(t, "PLAIN", req.string())
+
+			var w writer
+
+			w.int16(0)
+			w.int32(1)
+			w.string("PLAIN")
+
+			resp = append(resp, w.b...)
+		case apiKey == 36:
+			authenticated = string(req.next(int(req.int32()))) == "\x00"+b.user+"\x00"+b.password
+
+			var w writer
+
+			if authenticated {
+				w.int16(0)
+				w.int16(-1)
+			} else {
+				w.int16(58) // SASL authentication failed
+				w.string("invalid credentials")
+			}
+
+			w.int32(0) // auth bytes
+
+			resp = append(resp, w.b...)
+		case !authenticated:
+			return
+		case apiKey == 3:
+			resp = append(resp, b.metadata(req)...)
+		case apiKey == 0:
+			resp = append(resp, b.produce(t, req)...)
+		default:
+			return
+		}
+
+		if _, err := conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(resp))), resp...)); err != nil {
+			return
+		}
+	}
+}
+
+func (b *kafkaBroker) metadata(req *reader) []byte {
+	_ = req.int32()
+	topic := req.string()
+
+	host, port, _ := net.SplitHostPort(b.ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	var w writer
+
+	w.int32(1) // brokers
+	w.int32(1) // node id
+	w.string(host)
+	w.int32(int32(portNum))
+	w.int16(-1) // rack
+	w.int32(1)  // controller id
+	w.int32(1)  // topics
+	w.int16(0)
+	w.string(topic)
+	w.int8(0)
+	w.int32(1) // partitions
+	w.int16(0)
+	w.int32(0) // partition index
+	w.int32(1) // leader
+	w.int32(1) // replicas
+	w.int32(1)
+	w.int32(1) // in-sync replicas
+	w.int32(1)
+
+	return w.b
+}
+
+func (b *kafkaBroker) produce(t *testing.T, req *reader) []byte {
+	_ = req.string() // transactional id
+	require.Equal(t, int16(-1), req.int16())
+	_ = req.int32() // timeout
+	_ = req.int32() // topics
+	topic := req.string()
+	_ = req.int32() // partitions
+	partition := req.int32()
+	batch := req.next(int(req.int32()))
+
+	// record batch header: base offset, length, partition leader epoch, magic, crc
+	hdr := &reader{b: batch}
+	_ = hdr.int64()
+	_ = hdr.int32()
+	_ = hdr.int32()
+	require.Equal(t, int8(2), hdr.int8())
+	require.Equal(t, uint32(hdr.int32()), crc32.Checksum(hdr.b, crc32.MakeTable(crc32.Castagnoli)))
+
+	// attributes, last offset delta, timestamps, producer id and epoch, base sequence
+	_ = hdr.next(2 + 4 + 8 + 8 + 8 + 2 + 4)
+	require.Equal(t, int32(1), hdr.int32())
+
+	rec := &reader{b: hdr.varintBytes()}
+	_ = rec.int8()
+	_ = rec.varint()
+	_ = rec.varint()
+
+	b.mu.Lock()
+	b.records = append(b.records, kafkaRecord{topic: topic, key: rec.varintBytes(), value: rec.varintBytes()})
+	offset, code := len(b.records), b.errorCode
+	b.mu.Unlock()
+
+	var w writer
+
+	w.int32(1)
+	w.string(topic)
+	w.int32(1)
+	w.int32(partition)
+	w.int16(code)
+	w.int64(int64(offset))
+	w.int64(-1)
+	w.int32(0) // throttle time
+
+	return w.b
+}
+
+type reader struct {
+	b []byte
+}
+
+func (r *reader) next(n int) []byte {
+	v := r.b[:n]
+	r.b = r.b[n:]
+
+	return v
+}
+
+func (r *reader) int8() int8   { return int8(r.next(1)[0]) }
+func (r *reader) int16() int16 { return int16(binary.BigEndian.Uint16(r.next(2))) }
+func (r *reader) int32() int32 { return int32(binary.BigEndian.Uint32(r.next(4))) }
+func (r *reader) int64() int64 { return int64(binary.BigEndian.Uint64(r.next(8))) }
+
+func (r *reader) string() string {
+	n := r.int16()
+	if n < 0 {
+		return ""
+	}
+
+	return string(r.next(int(n)))
+}
+
+func (r *reader) varint() int64 {
+	v, n := binary.Varint(r.b)
+	r.b = r.b[n:]
+
+	return v
+}
+
+func (r *reader) varintBytes() []byte {
+	return r.next(int(r.varint()))
+}
+
+type writer struct {
+	b []byte
+}
+
+func (w *writer) int8(v int8)   { w.b = append(w.b, byte(v)) }
+func (w *writer) int16(v int16) { w.b = binary.BigEndian.AppendUint16(w.b, uint16(v)) }
+func (w *writer) int32(v int32) { w.b = binary.BigEndian.AppendUint32(w.b, uint32(v)) }
+func (w *writer) int64(v int64) { w.b = binary.BigEndian.AppendUint64(w.b, uint64(v)) }
+
+func (w *writer) string(v string) {
+	w.int16(int16(len(v)))
+	w.b = append(w.b, v...)
+}
+
+// newRawBroker accepts connections and handles them by the function instead of the Kafka protocol
+func newRawBroker(t *testing.T, handle func(conn net.Conn)) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	t.Cleanup(func() { _ = ln.Close() })
+
+	return ln.Addr().String()
+}
+
+func TestKafka(t *testing.T) {
+	ctx, e := testEvent()
+
+	t.Log("Requires brokers")
+	{
+		_, err := sinks.NewKafka(sinks.KafkaConfig{})
+		require.Error(t, err)
+	}
+
+	broker := newKafkaBroker(t, "", "")
+
+	sink, err := sinks.NewKafka(sinks.KafkaConfig{Brokers: []string{broker.ln.Addr().String()}})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	t.Log("Produces events keyed by the profile")
+	{
+		require.NoError(t, sink.Publish(ctx, e))
+		require.NoError(t, sink.Publish(ctx, e))
+
+		records := broker.produced()
+		require.Len(t, records, 2)
+		require.Equal(t, sinks.DefaultTopic, records[0].topic)
+		require.Equal(t, "1", string(records[0].key))
+		requireEnvelope(t, records[0].value)
+	}
+
+	t.Log("Fails when the broker rejects the event")
+	{
+		broker.mu.Lock()
+		broker.errorCode = 6 // not leader for partition
+		broker.mu.Unlock()
+
+		require.Error(t, sink.Publish(ctx, e))
+	}
+
+	t.Log("Authenticates connections by SASL PLAIN")
+	{
+		broker := newKafkaBroker(t, "obada", "secret")
+
+		_, err := sinks.NewKafka(sinks.KafkaConfig{
+			Brokers: []string{broker.ln.Addr().String()},
+			SASL:    sinks.SASLConfig{Mechanism: "SCRAM-SHA-512", User: "obada", Password: "secret"},
+		})
+		require.Error(t, err)
+
+		wrong, err := sinks.NewKafka(sinks.KafkaConfig{
+			Brokers: []string{broker.ln.Addr().String()},
+			SASL:    sinks.SASLConfig{User: "obada", Password: "wrong"},
+		})
+		require.NoError(t, err)
+		defer wrong.Close()
+
+		require.Error(t, wrong.Publish(ctx, e))
+
+		sink, err := sinks.NewKafka(sinks.KafkaConfig{
+			Brokers: []string{broker.ln.Addr().String()},
+			SASL:    sinks.SASLConfig{User: "obada", Password: "secret"},
+		})
+		require.NoError(t, err)
+		defer sink.Close()
+
+		require.NoError(t, sink.Publish(ctx, e))
+		require.Len(t, broker.produced(), 1)
+	}
+
+	t.Log("Rejects responses that exceed the size limit")
+	{
+		addr := newRawBroker(t, func(conn net.Conn) {
+			size := make([]byte, 4)
+			if _, err := io.ReadFull(conn, size); err != nil {
+				return
+			}
+
+			if _, err := io.CopyN(io.Discard, conn, int64(binary.BigEndian.Uint32(size))); err != nil {
+				return
+			}
+
+			_, _ = conn.Write(binary.BigEndian.AppendUint32(nil, 1<<31))
+		})
+
+		sink, err := sinks.NewKafka(sinks.KafkaConfig{Brokers: []string{addr}})
+		require.NoError(t, err)
+		defer sink.Close()
+
+		err = sink.Publish(ctx, e)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds the limit")
+	}
+
+	t.Log("Times out when the broker does not respond")
+	{
+		addr := newRawBroker(t, func(conn net.Conn) {
+			_, _ = io.Copy(io.Discard, conn)
+		})
+
+		sink, err := sinks.NewKafka(sinks.KafkaConfig{Brokers: []string{addr}, Timeout: 50 * time.Millisecond})
+		require.NoError(t, err)
+		defer sink.Close()
+
+		err = sink.Publish(ctx, e)
+		require.Error(t, err)
+
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		require.True(t, netErr.Timeout())
+	}
+
+	t.Log("Does not publish after the sink is closed")
+	{
+		sink, err := sinks.NewKafka(sinks.KafkaConfig{Brokers: []string{broker.ln.Addr().String()}})
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		require.ErrorIs(t, sink.Publish(ctx, e), sinks.ErrSinkClosed)
+	}
+}
@@ -0,0 +1,242 @@
+package sinks_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/events/sinks"
+	"github.com/stretchr/testify/require"
+)
+
+type natsMsg struct {
+	subject string
+	headers string
+	payload []byte
+}
+
+// natsServer is the in-process NATS server that implements the subset of the protocol used by the client,
+// published messages are acknowledged as stored by the stream. With the TLS configuration the server requires TLS.
+type natsServer struct {
+	ln      net.Listener
+	tls     *tls.Config
+	connect chan string
+
+	mu   sync.Mutex
+	msgs []natsMsg
+}
+
+func newNATSServer(t *testing.T, tlsConfig *tls.Config) *natsServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &natsServer{ln: ln, tls: tlsConfig, connect: make(chan string, 1)}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go srv.serve(conn)
+		}
+	}()
+
+	t.Cleanup(func() { _ = ln.Close() })
+
+	return srv
+}
+
+func (srv *natsServer) url() string {
+	return "nats://token@" + srv.ln.Addr().String()
+}
+
+func (srv *natsServer) messages() []natsMsg {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return srv.msgs
+}
+
+func (srv *natsServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	info := `INFO {"server_id":"test","proto":1,"headers":true,"max_payload":1048576,"tls_required":%t}` + "\r\n"
+
+	if _, err := fmt.Fprintf(conn, info, srv.tls != nil); err != nil {
+		return
+	}
+
+	if srv.tls != nil {
+		conn = tls.Server(conn, srv.tls)
+	}
+
+	r := bufio.NewReader(conn)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var reply string
+
+		switch fields[0] {
+		case "CONNECT":
+			srv.connect <- strings.TrimSpace(strings.TrimPrefix(line, "CONNECT"))
+		case "PING":
+			reply = "PONG\r\n"
+		case "HPUB":
+			// HPUB <subject> [reply-to] <hdr size> <size>
+			hdrSize, _ := strconv.Atoi(fields[len(fields)-2])
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+
+			buf := make([]byte, size+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+
+			srv.mu.Lock()
+			srv.msgs = append(srv.msgs, natsMsg{subject: fields[1], headers: string(buf[:hdrSize]), payload: buf[hdrSize:size]})
+			seq := len(srv.msgs)
+			srv.mu.Unlock()
+
+			if len(fields) == 5 {
+				ack := fmt.Sprintf(`{"stream":"EVENTS","seq":%d}`, seq)
+				reply = fmt.Sprintf("MSG %s 1 %d\r\n%s\r\n", fields[2], len(ack), ack)
+			}
+		}
+
+		if reply != "" {
+			if _, err := conn.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// newCertificate creates the self-signed certificate of 127.0.0.1, returns the certificate and the CA file
+func newCertificate(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestNATS(t *testing.T) {
+	ctx, e := testEvent()
+
+	t.Log("Validates the server url")
+	{
+		_, err := sinks.NewNATS(sinks.NATSConfig{URL: "http://localhost:4222"})
+		require.Error(t, err)
+	}
+
+	t.Log("Publishes events to JetStream")
+	{
+		srv := newNATSServer(t, nil)
+
+		sink, err := sinks.NewNATS(sinks.NATSConfig{URL: srv.url(), JetStream: true})
+		require.NoError(t, err)
+		defer sink.Close()
+
+		require.NoError(t, sink.Publish(ctx, e))
+		require.Contains(t, <-srv.connect, `"auth_token":"token"`)
+
+		require.NoError(t, sink.Publish(ctx, e))
+
+		msgs := srv.messages()
+		require.Len(t, msgs, 2)
+		require.Equal(t, sinks.DefaultSubjectPrefix+"."+events.NftMinted, msgs[0].subject)
+		require.Contains(t, msgs[0].headers, "Nats-Msg-Id: "+e.ID)
+		requireEnvelope(t, msgs[0].payload)
+	}
+
+	t.Log("Publishes events to core NATS")
+	{
+		srv := newNATSServer(t, nil)
+
+		sink, err := sinks.NewNATS(sinks.NATSConfig{URL: srv.url(), SubjectPrefix: "obada"})
+		require.NoError(t, err)
+		defer sink.Close()
+
+		require.NoError(t, sink.Publish(ctx, e))
+
+		msgs := srv.messages()
+		require.Len(t, msgs, 1)
+		require.Equal(t, "obada."+events.NftMinted, msgs[0].subject)
+		requireEnvelope(t, msgs[0].payload)
+	}
+
+	t.Log("Publishes events over TLS verified by the CA")
+	{
+		cert, caFile := newCertificate(t)
+
+		srv := newNATSServer(t, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+
+		_, err := sinks.NewNATS(sinks.NATSConfig{URL: srv.url(), TLS: sinks.TLSConfig{CA: "missing.pem"}})
+		require.Error(t, err)
+
+		sink, err := sinks.NewNATS(sinks.NATSConfig{URL: srv.url(), TLS: sinks.TLSConfig{CA: caFile}})
+		require.NoError(t, err)
+		defer sink.Close()
+
+		require.NoError(t, sink.Publish(ctx, e))
+
+		msgs := srv.messages()
+		require.Len(t, msgs, 1)
+		requireEnvelope(t, msgs[0].payload)
+	}
+
+	t.Log("Fails when the server is not available")
+	{
+		srv := newNATSServer(t, nil)
+		require.NoError(t, srv.ln.Close())
+
+		sink, err := sinks.NewNATS(sinks.NATSConfig{URL: srv.url()})
+		require.NoError(t, err)
+
+		require.Error(t, sink.Publish(ctx, e))
+	}
+}
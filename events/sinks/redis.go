@@ -0,0 +1,83 @@
+package sinks
+
+import (
+	"context"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/events/envelope"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultStream is the Redis stream that receives events
+	DefaultStream = "client-helper:events"
+
+	// DefaultStreamMaxLen approximate count of the latest events kept in the stream
+	DefaultStreamMaxLen = 100000
+)
+
+// RedisPubSub publishes events to the Redis channel of the event name
+type RedisPubSub struct {
+	client *redis.Client
+}
+
+// NewRedisPubSub creates the Redis pub/sub sink
+func NewRedisPubSub(client *redis.Client) *RedisPubSub {
+	return &RedisPubSub{client: client}
+}
+
+// Publish implements the Publish method of the events.EventSink interface
+func (s *RedisPubSub) Publish(ctx context.Context, e bus.Event) error {
+	msg, err := legacyMessage(e)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Publish(ctx, e.Topic, msg).Err()
+}
+
+// RedisStream appends event envelopes to the Redis stream, so consumer groups can read events at least once
+type RedisStream struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStream creates the Redis streams sink
+func NewRedisStream(client *redis.Client, stream string, maxLen int64) *RedisStream {
+	if stream == "" {
+		stream = DefaultStream
+	}
+
+	if maxLen <= 0 {
+		maxLen = DefaultStreamMaxLen
+	}
+
+	return &RedisStream{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// Publish implements the Publish method of the events.EventSink interface
+func (s *RedisStream) Publish(ctx context.Context, e bus.Event) error {
+	evt, err := envelope.New(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: []any{
+			"id", evt.ID,
+			"type", evt.Type,
+			"profile_id", evt.ProfileID,
+			"payload", string(evt.Payload),
+			"occurred_at", evt.OccurredAt.Format(time.RFC3339Nano),
+		},
+	}).Err()
+}
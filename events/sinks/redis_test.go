@@ -0,0 +1,89 @@
+package sinks_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/events/sinks"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisPubSub(t *testing.T) {
+	rdb, redisMock := redismock.NewClientMock()
+
+	sink := sinks.NewRedisPubSub(rdb)
+
+	ctx, e := testEvent()
+
+	t.Log("Publishes NFT events as DID")
+	{
+		redisMock.ExpectPublish(events.NftMinted, testDID).SetVal(1)
+
+		require.NoError(t, sink.Publish(ctx, e))
+		require.NoError(t, redisMock.ExpectationsWereMet())
+	}
+
+	t.Log("Publishes device events as JSON")
+	{
+		evt := device.DeviceSaved{Device: svcs.Device{DID: testDID}, ProfileID: "1"}
+
+		msg, err := json.Marshal(evt)
+		require.NoError(t, err)
+
+		redisMock.ExpectPublish(events.DeviceSaved, string(msg)).SetVal(1)
+
+		e.Topic = events.DeviceSaved
+		e.Data = evt
+
+		require.NoError(t, sink.Publish(ctx, e))
+		require.NoError(t, redisMock.ExpectationsWereMet())
+	}
+}
+
+func TestRedisStream(t *testing.T) {
+	rdb, redisMock := redismock.NewClientMock()
+
+	sink := sinks.NewRedisStream(rdb, "events", 10)
+
+	ctx, e := testEvent()
+
+	t.Log("Appends the event envelope to the trimmed stream")
+	{
+		redisMock.CustomMatch(func(_, actual []interface{}) error {
+			// xadd events maxlen ~ 10 * field value ...
+			if actual[1] != "events" || fmt.Sprint(actual[4]) != "10" {
+				return fmt.Errorf("unexpected stream args: %v", actual)
+			}
+
+			values := map[string]interface{}{}
+			for i := 6; i+1 < len(actual); i += 2 {
+				values[fmt.Sprint(actual[i])] = actual[i+1]
+			}
+
+			if values["id"] != e.ID || values["type"] != events.NftMinted || values["profile_id"] != "1" {
+				return fmt.Errorf("unexpected envelope: %v", values)
+			}
+
+			var payload string
+			if err := json.Unmarshal([]byte(fmt.Sprint(values["payload"])), &payload); err != nil || payload != testDID {
+				return fmt.Errorf("payload is not JSON: %v", values["payload"])
+			}
+
+			return nil
+		}).ExpectXAdd(&redis.XAddArgs{
+			Stream: "events",
+			MaxLen: 10,
+			Approx: true,
+			Values: []any{"id", "", "type", "", "profile_id", "", "payload", "", "occurred_at", ""},
+		}).SetVal("1-0")
+
+		require.NoError(t, sink.Publish(ctx, e))
+		require.NoError(t, redisMock.ExpectationsWereMet())
+	}
+}
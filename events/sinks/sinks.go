@@ -0,0 +1,103 @@
+package sinks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/services/device"
+)
+
+// TLSConfig TLS options of the broker connection
+type TLSConfig struct {
+	// Enabled connects with TLS, the server certificate is verified by system roots unless CA is set
+	Enabled bool
+
+	// CA is the PEM file with certificates of the authority that issued the server certificate
+	CA string
+
+	// Cert and Key are PEM files of the client certificate of the mutual TLS
+	Cert string
+	Key  string
+}
+
+// config returns the TLS configuration, nil when TLS is not enabled
+func (c TLSConfig) config() (*tls.Config, error) {
+	if !c.Enabled && c.CA == "" && c.Cert == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CA != "" {
+		pem, err := os.ReadFile(c.CA)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA file: %w", err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA file %s", c.CA)
+		}
+	}
+
+	if c.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// Multi publishes events to every sink
+type Multi []events.EventSink
+
+// Publish implements the Publish method of the events.EventSink interface
+func (m Multi) Publish(ctx context.Context, e bus.Event) error {
+	var errs []error
+
+	for _, s := range m {
+		errs = append(errs, s.Publish(ctx, e))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes sinks that hold connections to the brokers
+func (m Multi) Close() error {
+	var errs []error
+
+	for _, s := range m {
+		if c, ok := s.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// legacyMessage is the message format of the pub/sub subscribers: device events are JSON, other events are strings
+func legacyMessage(e bus.Event) (string, error) {
+	if evt, ok := e.Data.(device.DeviceSaved); ok {
+		b, err := json.Marshal(evt)
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+
+	return fmt.Sprintf("%v", e.Data), nil
+}
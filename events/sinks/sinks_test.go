@@ -0,0 +1,62 @@
+package sinks_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/events/envelope"
+	"github.com/obada-foundation/client-helper/events/sinks"
+	"github.com/stretchr/testify/require"
+)
+
+const testDID = "did:obada:64925be84b586363670c1f7e5ada86a37904e590d1f6570d834436331dd3eb88"
+
+func testEvent() (context.Context, bus.Event) {
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	return ctx, bus.Event{
+		ID:         "JqHC8Qlp00010001",
+		Topic:      events.NftMinted,
+		Data:       testDID,
+		OccurredAt: time.Now(),
+	}
+}
+
+// requireEnvelope checks the published envelope of the test event
+func requireEnvelope(t *testing.T, b []byte) {
+	var evt envelope.Envelope
+
+	require.NoError(t, json.Unmarshal(b, &evt))
+	require.Equal(t, "JqHC8Qlp00010001", evt.ID)
+	require.Equal(t, events.NftMinted, evt.Type)
+	require.Equal(t, "1", evt.ProfileID)
+	require.JSONEq(t, `"`+testDID+`"`, string(evt.Payload))
+}
+
+func TestMulti(t *testing.T) {
+	ctx, e := testEvent()
+
+	broker := newKafkaBroker(t, "", "")
+
+	kafka, err := sinks.NewKafka(sinks.KafkaConfig{Brokers: []string{broker.ln.Addr().String()}})
+	require.NoError(t, err)
+
+	multi := sinks.Multi{kafka}
+
+	t.Log("Publishes events to every sink")
+	{
+		require.NoError(t, multi.Publish(ctx, e))
+		require.Len(t, broker.produced(), 1)
+	}
+
+	t.Log("Closes sinks")
+	{
+		require.NoError(t, multi.Close())
+		require.ErrorIs(t, multi.Publish(ctx, e), sinks.ErrSinkClosed)
+	}
+}
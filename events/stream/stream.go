@@ -0,0 +1,193 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/events/envelope"
+	"go.uber.org/zap"
+)
+
+const (
+	// handlerKey the bus handler key of the hub
+	handlerKey = "stream:clients"
+
+	// matcher selects topics which are pushed to the clients
+	matcher = `^(account\.(created|deleted)|device\.saved|nft\..+)$`
+
+	// DefaultBufferSize how many events are kept for the resume by default
+	DefaultBufferSize = 1000
+
+	// subscriptionSize how many events are buffered for the slow subscriber before it is dropped
+	subscriptionSize = 64
+)
+
+// Event is the client notification
+type Event struct {
+	ID        uint64          `json:"id"`
+	Topic     string          `json:"topic"`
+	ProfileID string          `json:"-"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Config hub dependencies
+type Config struct {
+	Bus    *bus.Bus
+	Logger *zap.SugaredLogger
+
+	// BufferSize how many latest events are kept for the clients that resume the stream
+	BufferSize int
+}
+
+// Hub fans out bus events to the connected clients of the event profile.
+// The latest events are kept in the ring buffer, so reconnected clients receive events they missed.
+type Hub struct {
+	logger *zap.SugaredLogger
+
+	mu     sync.Mutex
+	lastID uint64
+	buffer []Event
+	next   int
+	subs   map[chan Event]string
+	closed bool
+}
+
+// NewHub creates the hub and subscribes it to the bus
+func NewHub(cfg Config) *Hub {
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	h := &Hub{
+		logger: cfg.Logger,
+		// IDs are seeded with the start time, so IDs given before the restart are lower than new ones
+		lastID: uint64(time.Now().UnixNano()),
+		buffer: make([]Event, 0, size),
+		subs:   make(map[chan Event]string),
+	}
+
+	cfg.Bus.RegisterHandler(handlerKey, bus.Handler{
+		Handle:  h.handle,
+		Matcher: matcher,
+	})
+
+	return h
+}
+
+// handle converts the bus event to the client notification, events without a profile are skipped
+func (h *Hub) handle(ctx context.Context, e bus.Event) {
+	evt, err := envelope.New(ctx, e)
+	if err != nil {
+		h.logger.Errorw("cannot marshal stream event", "topic", e.Topic, "error", err)
+		return
+	}
+
+	if evt.ProfileID == "" {
+		return
+	}
+
+	h.Publish(evt.ProfileID, evt.Type, evt.Payload)
+}
+
+// Publish sends the event to the profile subscribers and keeps it for the resume
+func (h *Hub) Publish(profileID, topic string, data json.RawMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+
+	evt := Event{
+		ID:        h.lastID,
+		Topic:     topic,
+		ProfileID: profileID,
+		Data:      data,
+	}
+
+	if len(h.buffer) < cap(h.buffer) {
+		h.buffer = append(h.buffer, evt)
+	} else {
+		h.buffer[h.next] = evt
+		h.next = (h.next + 1) % len(h.buffer)
+	}
+
+	for sub, subProfileID := range h.subs {
+		if subProfileID != profileID {
+			continue
+		}
+
+		select {
+		case sub <- evt:
+		default:
+			// the client does not keep up, it has to reconnect and resume from the last received event
+			delete(h.subs, sub)
+			close(sub)
+
+			h.logger.Warnw("stream subscriber was dropped", "profile_id", profileID)
+		}
+	}
+}
+
+// Subscribe returns the buffered events of the profile published after the lastID and the channel of new events.
+// The channel is closed when the subscriber is dropped or unsubscribed.
+func (h *Hub) Subscribe(profileID string, lastID uint64) ([]Event, <-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var missed []Event
+
+	if lastID > 0 {
+		for i := range h.buffer {
+			evt := h.buffer[(h.next+i)%len(h.buffer)]
+
+			if evt.ProfileID == profileID && evt.ID > lastID {
+				missed = append(missed, evt)
+			}
+		}
+	}
+
+	sub := make(chan Event, subscriptionSize)
+
+	// the server is shutting down, the client resumes the stream on another instance or after the restart
+	if h.closed {
+		close(sub)
+		return missed, sub, func() {}
+	}
+
+	h.subs[sub] = profileID
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subs[sub]; ok {
+			delete(h.subs, sub)
+			close(sub)
+		}
+	}
+
+	return missed, sub, unsubscribe
+}
+
+// Close drops all subscribers, so their streams end and the server shutdown does not wait for them,
+// subscribers that come after Close are dropped right away
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub)
+	}
+}
+
+// String returns the event in the Server-Sent Events format
+func (e Event) String() string {
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Topic, e.Data)
+}
@@ -0,0 +1,86 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	"github.com/obada-foundation/client-helper/events/stream"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testDID = "did:obada:64925be84b586363670c1f7e5ada86a37904e590d1f6570d834436331dd3eb88"
+
+func TestHub(t *testing.T) {
+	var fn bus.Next = func() string { return "afakeid" }
+	b, err := bus.NewBus(fn)
+	require.NoError(t, err)
+
+	b.RegisterTopics(events.AccountCreated, events.DeviceSaved, events.NftMinted)
+
+	logger, deleteLogFile := testutil.MakeLoger()
+	defer deleteLogFile()
+
+	hub := stream.NewHub(stream.Config{
+		Bus:        b,
+		Logger:     logger,
+		BufferSize: 2,
+	})
+
+	ctx := context.Background()
+	profileCtx := auth.SetClaims(ctx, auth.Claims{UserID: "1"})
+
+	t.Log("Pushes events of the subscriber profile only")
+	{
+		missed, evts, unsubscribe := hub.Subscribe("1", 0)
+		defer unsubscribe()
+
+		require.Empty(t, missed)
+
+		require.NoError(t, b.Emit(auth.SetClaims(ctx, auth.Claims{UserID: "2"}), events.NftMinted, testDID))
+		require.NoError(t, b.Emit(ctx, events.NftMinted, testDID))
+		require.NoError(t, b.Emit(profileCtx, events.NftMinted, testDID))
+
+		evt := <-evts
+		require.Equal(t, events.NftMinted, evt.Topic)
+		require.JSONEq(t, `"`+testDID+`"`, string(evt.Data))
+		require.Empty(t, evts)
+
+		require.NoError(t, b.Emit(ctx, events.DeviceSaved, device.DeviceSaved{
+			Device:    svcs.Device{DID: testDID},
+			ProfileID: "1",
+		}))
+
+		evt = <-evts
+		require.Equal(t, events.DeviceSaved, evt.Topic)
+		require.Contains(t, string(evt.Data), testDID)
+	}
+
+	t.Log("Replays buffered events after the last received one")
+	{
+		missed, _, unsubscribe := hub.Subscribe("1", 0)
+		unsubscribe()
+		require.Empty(t, missed)
+
+		require.NoError(t, b.Emit(profileCtx, events.AccountCreated, "obada1address"))
+
+		missed, _, unsubscribe = hub.Subscribe("1", 1)
+		unsubscribe()
+
+		// buffer keeps only two latest events
+		require.Len(t, missed, 2)
+		require.Equal(t, events.DeviceSaved, missed[0].Topic)
+		require.Equal(t, events.AccountCreated, missed[1].Topic)
+
+		resumed, _, unsubscribe := hub.Subscribe("1", missed[0].ID)
+		unsubscribe()
+
+		require.Len(t, resumed, 1)
+		require.Equal(t, missed[1].ID, resumed[0].ID)
+	}
+}
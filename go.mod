@@ -11,6 +11,8 @@ replace (
 
 require (
 	cosmossdk.io/math v1.2.0
+	cosmossdk.io/x/feegrant v0.1.0
+	github.com/99designs/keyring v1.2.1
 	github.com/cometbft/cometbft v0.38.4
 	github.com/cosmos/cosmos-sdk v0.50.3
 	github.com/cosmos/go-bip39 v1.0.0
@@ -29,6 +31,7 @@ require (
 	github.com/jessevdk/go-flags v1.5.0
 	github.com/mustafaturan/bus/v3 v3.0.3
 	github.com/mustafaturan/monoton/v3 v3.0.0
+	github.com/nats-io/nats.go v1.37.0
 	github.com/obada-foundation/common v0.0.0-20230520215448-087575da4fe9
 	github.com/obada-foundation/fullcore v0.0.0-00010101000000-000000000000
 	github.com/obada-foundation/registry v0.1.0
@@ -39,6 +42,7 @@ require (
 	github.com/stretchr/testify v1.8.4
 	github.com/tendermint/tm-db v0.6.7
 	go.uber.org/zap v1.26.0
+	golang.org/x/crypto v0.18.0
 	google.golang.org/grpc v1.60.1
 )
 
@@ -54,7 +58,6 @@ require (
 	cosmossdk.io/x/tx v0.13.0 // indirect
 	filippo.io/edwards25519 v1.0.0 // indirect
 	github.com/99designs/go-keychain v0.0.0-20191008050251-8e49817e8af4 // indirect
-	github.com/99designs/keyring v1.2.1 // indirect
 	github.com/DataDog/datadog-go v3.2.0+incompatible // indirect
 	github.com/DataDog/zstd v1.5.5 // indirect
 	github.com/OneOfOne/xxhash v1.2.8 // indirect
@@ -166,6 +169,8 @@ require (
 	github.com/multiformats/go-multihash v0.2.3 // indirect
 	github.com/multiformats/go-multistream v0.4.1 // indirect
 	github.com/multiformats/go-varint v0.0.7 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
 	github.com/o1egl/paseto v1.0.0 // indirect
 	github.com/oasisprotocol/curve25519-voi v0.0.0-20230904125328-1f23a7beb09a // indirect
 	github.com/oklog/run v1.1.0 // indirect
@@ -209,7 +214,6 @@ require (
 	go.opentelemetry.io/otel/sdk v1.21.0 // indirect
 	go.opentelemetry.io/otel/trace v1.21.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
-	golang.org/x/crypto v0.18.0 // indirect
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
 	golang.org/x/net v0.20.0 // indirect
 	golang.org/x/sync v0.5.0 // indirect
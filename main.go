@@ -17,8 +17,11 @@ import (
 var revision = "unknown"
 
 type opts struct {
-	DBPath    string            `long:"db-path" env:"DB_PATH" description:"Show verbose debug information" default:"/home/client-helper/data"`
-	ServerCmd cmd.ServerCommand `command:"server"`
+	DBPath     string             `long:"db-path" env:"DB_PATH" description:"Show verbose debug information" default:"/home/client-helper/data"`
+	ServerCmd  cmd.ServerCommand  `command:"server"`
+	TxCmd      cmd.TxCommand      `command:"tx"`
+	KeyringCmd cmd.KeyringCommand `command:"keyring"`
+	SignerCmd  cmd.SignerCommand  `command:"signer"`
 }
 
 //nolint:gochecknoinits // this is an entrypoint
@@ -56,19 +59,24 @@ func run(lgr *zap.SugaredLogger, o *opts) error {
 
 	p := flags.NewParser(o, flags.Default)
 	p.CommandHandler = func(command flags.Commander, args []string) error {
-		db, err := db.NewDB("client-helper", db.BadgerDBBackend, o.DBPath)
-		if err != nil {
-			return err
+		commonOpts := cmd.CommonOpts{
+			Revision: revision,
+			Logger:   lgr,
+		}
+
+		if oc, ok := command.(cmd.OfflineCommander); !ok || !oc.Offline() {
+			db, err := db.NewDB("client-helper", db.BadgerDBBackend, o.DBPath)
+			if err != nil {
+				return err
+			}
+
+			commonOpts.DB = db
 		}
 
 		c := command.(cmd.CommonOptionsCommander)
-		c.SetCommon(cmd.CommonOpts{
-			Revision: revision,
-			Logger:   lgr,
-			DB:       db,
-		})
+		c.SetCommon(commonOpts)
 
-		err = c.Execute(args)
+		err := c.Execute(args)
 		if err != nil {
 			lgr.Errorf("failed with %+v", err)
 		}
@@ -4,18 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/cosmos/cosmos-sdk/crypto"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/mustafaturan/bus/v3"
 	"github.com/obada-foundation/client-helper/auth"
 	"github.com/obada-foundation/client-helper/events"
 	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/system/encoder"
 	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
 	"github.com/tendermint/tm-db"
 )
@@ -25,13 +31,41 @@ type Service struct {
 	validator  *validate.Validator
 	db         db.DB
 	nodeClient obadanode.Client
-	keyring    keyring.Keyring
+	keyring    Keys
+	wallets    Wallets
 	eventBus   *bus.Bus
+	signers    signer.Provider
+	gapLimit   uint
 }
 
-// Account contain fields that represent account
+// Keys keeps keys of the accounts, it is the part of the keyring that is used by the service
+type Keys interface {
+	Key(uid string) (*keyring.Record, error)
+	KeyByAddress(address sdk.Address) (*keyring.Record, error)
+	Delete(uid string) error
+	DeleteByAddress(address sdk.Address) error
+	Rename(from, to string) error
+	NewAccount(uid, mnemonic, bip39Passphrase, hdPath string, algo keyring.SignatureAlgo) (*keyring.Record, error)
+	ImportPrivKeyHex(uid, privKey, algoStr string) error
+	SaveMultisig(uid string, pubKey cryptotypes.PubKey) (*keyring.Record, error)
+	ExportPrivKeyArmor(uid, encryptPassphrase string) (string, error)
+}
+
+// Wallets keeps mnemonics of HD wallets outside of client-helper and derives keys of wallet accounts there
+type Wallets interface {
+	SaveMnemonic(ctx context.Context, wallet, mnemonic string) error
+	DeleteMnemonic(ctx context.Context, wallet string) error
+	DeriveKey(ctx context.Context, wallet, uid, hdPath string) (*keyring.Record, error)
+	DeriveAddress(ctx context.Context, wallet, hdPath string) (string, error)
+}
+
+// Account is the metadata record of the account
 type Account struct {
-	Name string `json:"name"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	Tags              []string  `json:"tags"`
+	DefaultForMinting bool      `json:"default_for_minting"`
+	CreatedAt         time.Time `json:"created_at"`
 }
 
 // NewService creates new account service
@@ -42,6 +76,24 @@ func NewService(v *validate.Validator, database db.DB, c obadanode.Client, k key
 		nodeClient: c,
 		keyring:    k,
 		eventBus:   eb,
+		signers:    signer.NewKeyring(k),
+		gapLimit:   DefaultGapLimit,
+	}
+}
+
+// SetRemoteSigner keeps keys and wallet mnemonics of the accounts on the remote signer, so they never live
+// in client-helper. Keys are generated and imported by the signer, they cannot be exported.
+func (as *Service) SetRemoteSigner(r *signer.Remote) {
+	as.keyring = r.Keys()
+	as.wallets = r
+	as.signers = r
+}
+
+// SetGapLimit sets how many unused HD accounts in a row end the discovery, it also limits how many unused
+// accounts can be created ahead of time
+func (as *Service) SetGapLimit(limit uint) {
+	if limit > 0 {
+		as.gapLimit = limit
 	}
 }
 
@@ -88,7 +140,8 @@ func (as Service) incrementImportedAccountIndex(ctx context.Context) error {
 	return nil
 }
 
-// ImportAccount imports an account to the keying
+// ImportAccount imports an account to the keying, the format of the private key is detected,
+// armored and keystore keys are decrypted by the passphrase
 func (as Service) ImportAccount(ctx context.Context, privateKey, passphrase string, acc Account) error {
 	profileID := auth.GetClaims(ctx).UserID
 
@@ -99,9 +152,18 @@ func (as Service) ImportAccount(ctx context.Context, privateKey, passphrase stri
 
 	newIdx := idx + 1
 
+	privKey, err := decodePrivKey(privateKey, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if _, er := as.keyring.KeyByAddress(sdk.AccAddress(privKey.PubKey().Address())); er == nil {
+		return ErrAccountExists
+	}
+
 	importKey := keyringAccountImportedKey(profileID, newIdx)
 
-	if er := as.keyring.ImportPrivKey(importKey, privateKey, passphrase); er != nil {
+	if er := as.keyring.ImportPrivKeyHex(importKey, hex.EncodeToString(privKey.Bytes()), string(hd.Secp256k1Type)); er != nil {
 		return fmt.Errorf("cannot import private key to keyring: %w", er)
 	}
 
@@ -127,6 +189,10 @@ func (as Service) ImportAccount(ctx context.Context, privateKey, passphrase stri
 		return er
 	}
 
+	if er := setAccountMetadata(batch, profileID, accAddress.String(), acc); er != nil {
+		return er
+	}
+
 	// Increment the index
 	idxBytes, err := encoder.DataEncode(newIdx)
 	if err != nil {
@@ -145,6 +211,8 @@ func (as Service) ImportAccount(ctx context.Context, privateKey, passphrase stri
 		return err
 	}
 
+	audit.SetTarget(ctx, accAddress.String())
+
 	return as.eventBus.Emit(ctx, events.AccountCreated, accAddress.String())
 }
 
@@ -152,6 +220,10 @@ func (as Service) ImportAccount(ctx context.Context, privateKey, passphrase stri
 func (as Service) HasAccount(ctx context.Context, address string) bool {
 	profileID := auth.GetUserID(ctx)
 
+	if watchOnly, err := as.isWatchAccount(profileID, address); err == nil && watchOnly {
+		return true
+	}
+
 	accountOwnnerID, err := as.GetProfileByAddress(address)
 	if err != nil {
 		return false
@@ -160,8 +232,9 @@ func (as Service) HasAccount(ctx context.Context, address string) bool {
 	return accountOwnnerID == profileID
 }
 
-// DeleteAccount deletes an imported account
-func (as Service) DeleteAccount(ctx context.Context, address string) error {
+// DeleteAccount deletes an imported or watch-only account. The key of the account that holds NFTs or coins
+// on chain is deleted only when it was exported or the deletion is forced, see DeletionCheck.
+func (as Service) DeleteAccount(ctx context.Context, address string, force bool) error {
 	profileID := auth.GetUserID(ctx)
 
 	accountAddress, err := sdk.AccAddressFromBech32(address)
@@ -169,6 +242,15 @@ func (as Service) DeleteAccount(ctx context.Context, address string) error {
 		return err
 	}
 
+	watchOnly, err := as.isWatchAccount(profileID, address)
+	if err != nil {
+		return err
+	}
+
+	if watchOnly {
+		return as.deleteWatchAccount(ctx, address)
+	}
+
 	key, err := as.keyring.KeyByAddress(accountAddress)
 	if err != nil {
 		return err
@@ -178,7 +260,33 @@ func (as Service) DeleteAccount(ctx context.Context, address string) error {
 		return ErrHDAccountDelete
 	}
 
-	if err := as.db.Delete(accountImportedKey(profileID, address)); err != nil {
+	if !force {
+		check, err := as.DeletionCheck(ctx, address)
+		if err != nil {
+			return err
+		}
+
+		if !check.Deletable {
+			return ErrAccountHasAssets
+		}
+	}
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Delete(accountImportedKey(profileID, address)); err != nil {
+		return err
+	}
+
+	if err := batch.Delete(accountExportedKey(profileID, address)); err != nil {
+		return err
+	}
+
+	if err := as.deleteAccountMetadata(batch, profileID, address); err != nil {
+		return err
+	}
+
+	if err := batch.WriteSync(); err != nil {
 		return err
 	}
 
@@ -189,83 +297,137 @@ func (as Service) DeleteAccount(ctx context.Context, address string) error {
 	return as.eventBus.Emit(ctx, events.AccountDeleted, address)
 }
 
-// ExportAccount expots an account
-func (as Service) ExportAccount(ctx context.Context, address, passphrase string) (string, error) {
-	profileID := auth.GetClaims(ctx).UserID
+// ExportAccount exports the private key of an account in the format, armored key by default.
+// The passphrase encrypts armored and keystore keys, hex keys are not encrypted.
+func (as Service) ExportAccount(ctx context.Context, address, passphrase string, format KeyFormat) (string, error) {
+	if as.wallets != nil {
+		return "", ErrRemoteKeys
+	}
 
-	accountAddress, err := sdk.AccAddressFromBech32(address)
+	keyInfo, err := as.exportKey(ctx, address)
 	if err != nil {
 		return "", err
 	}
 
-	keyInfo, err := as.keyring.KeyByAddress(accountAddress)
+	if err := as.unlock(ctx, address); err != nil {
+		return "", err
+	}
+
+	exported, err := as.exportPrivKey(keyInfo.Name, address, passphrase, format)
 	if err != nil {
 		return "", err
 	}
 
-	if !strings.Contains(keyInfo.Name, keyringAccountPrefix(profileID)) {
-		return "", ErrAccountNotExists
+	// the exported key keeps assets of the account reachable, so it can be deleted without forcing
+	exportedAt, err := encoder.DataEncode(time.Now().UTC())
+	if err != nil {
+		return "", err
 	}
 
-	return as.keyring.ExportPrivKeyArmor(keyInfo.Name, passphrase)
-}
+	if err := as.db.SetSync(accountExportedKey(auth.GetUserID(ctx), address), exportedAt); err != nil {
+		return "", err
+	}
 
-// UpdateAccountName updates the account name
-func (as Service) UpdateAccountName(ctx context.Context, address, newAccountName string) error {
-	profileID := auth.GetUserID(ctx)
+	return exported, nil
+}
 
-	keys := [][]byte{
-		accountHDKey(profileID, ""),
-		accountImportedKey(profileID, ""),
-	}
+func (as Service) exportPrivKey(keyName, address, passphrase string, format KeyFormat) (string, error) {
+	switch format {
+	case "", KeyFormatArmor:
+		return as.keyring.ExportPrivKeyArmor(keyName, passphrase)
+	case KeyFormatHex, KeyFormatKeystore:
+		// the armor is only kept in memory, so the export passphrase protects nothing and stays empty
+		armor, err := as.keyring.ExportPrivKeyArmor(keyName, "")
+		if err != nil {
+			return "", err
+		}
 
-	for _, key := range keys {
-		prefixDB := db.NewPrefixDB(as.db, key)
-		itr, err := prefixDB.Iterator(nil, nil)
+		privKey, _, err := crypto.UnarmorDecryptPrivKey(armor, "")
 		if err != nil {
-			return err
+			return "", err
 		}
 
-		for ; itr.Valid(); itr.Next() {
-			key := itr.Key()
+		if format == KeyFormatHex {
+			return hex.EncodeToString(privKey.Bytes()), nil
+		}
 
-			if strings.Contains(string(key), address) {
-				account := Account{}
+		return encryptKeystore(privKey, address, passphrase)
+	default:
+		return "", ErrUnknownKeyFormat
+	}
+}
 
-				b := bytes.NewBuffer(itr.Value())
-				dec := gob.NewDecoder(b)
+// ExportPubKey exports the public key and address of an account, the signing passphrase is not required
+func (as Service) ExportPubKey(ctx context.Context, address string) (svcs.PubKeyBundle, error) {
+	keyInfo, err := as.exportKey(ctx, address)
+	if err != nil {
+		return svcs.PubKeyBundle{}, err
+	}
 
-				if err := dec.Decode(&account); err != nil {
-					return err
-				}
+	pubKey, err := keyInfo.GetPubKey()
+	if err != nil {
+		return svcs.PubKeyBundle{}, err
+	}
 
-				account.Name = newAccountName
+	return svcs.PubKeyBundle{
+		Address: address,
+		Type:    pubKey.Type(),
+		PubKey:  fmt.Sprintf("%X", pubKey.Bytes()),
+	}, nil
+}
 
-				accountBytes, err := encoder.DataEncode(account)
-				if err != nil {
-					return err
-				}
+func (as Service) exportKey(ctx context.Context, address string) (*keyring.Record, error) {
+	profileID := auth.GetClaims(ctx).UserID
 
-				return prefixDB.Set(key, accountBytes)
-			}
-		}
+	accountAddress, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return nil, err
+	}
+
+	watchOnly, err := as.isWatchAccount(profileID, address)
+	if err != nil {
+		return nil, err
+	}
 
-		_ = itr.Close()
+	if watchOnly {
+		return nil, ErrWatchOnlyAccount
+	}
+
+	keyInfo, err := as.keyring.KeyByAddress(accountAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	return ErrAccountNotExists
+	if !strings.Contains(keyInfo.Name, keyringAccountPrefix(profileID)) {
+		return nil, ErrAccountNotExists
+	}
 
+	return keyInfo, nil
 }
 
 // NewAccount creates a new OBADA account from HD wallet
 func (as Service) NewAccount(ctx context.Context, acc Account) (svcs.Account, error) {
-	var account svcs.Account
+	accounts, err := as.NewAccounts(ctx, acc, 1)
+	if err != nil {
+		return svcs.Account{}, err
+	}
+
+	return accounts[0], nil
+}
 
+// NewAccounts creates the next count OBADA accounts from HD wallet, so addresses can be shared ahead of time,
+// e.g. for receiving NFTs. Unused accounts at the end of the wallet, including the new ones, cannot exceed
+// the gap limit, otherwise the discovery would not find accounts used after them.
+func (as Service) NewAccounts(ctx context.Context, acc Account, count uint) ([]svcs.Account, error) {
 	profileID := auth.GetClaims(ctx).UserID
 
+	if count == 0 {
+		count = 1
+	}
+
 	wallet, err := as.GetWallet(ctx)
 	if err != nil {
-		return account, err
+		return nil, err
 	}
 
 	hasAccounts := false
@@ -273,7 +435,7 @@ func (as Service) NewAccount(ctx context.Context, acc Account) (svcs.Account, er
 	prefixDB := db.NewPrefixDB(as.db, accountHDKey(profileID, ""))
 	itr, err := prefixDB.Iterator(nil, nil)
 	if err != nil {
-		return account, err
+		return nil, err
 	}
 	defer itr.Close()
 
@@ -282,32 +444,49 @@ func (as Service) NewAccount(ctx context.Context, acc Account) (svcs.Account, er
 		break
 	}
 
-	// When we have existing accounts we first check that last account has a zero transactions
+	index := uint(0)
+	unused := uint(0)
+
 	if hasAccounts {
-		accounts, er := as.getHDWalletAccounts(ctx)
-		if er != nil {
-			return account, er
+		unused, err = as.unusedAccounts(ctx, wallet)
+		if err != nil {
+			return nil, err
 		}
 
-		lastAccount := accounts[wallet.AccountIndex]
+		index = wallet.AccountIndex + 1
+	}
 
-		ok, er := as.nodeClient.HasAccount(ctx, lastAccount.Address)
-		if er != nil {
-			return account, er
-		}
+	if unused+count > as.gapLimit {
+		return nil, ErrGapLimitReached
+	}
 
-		if !ok {
-			return account, ErrAccountHasZeroTx
+	accounts := make([]svcs.Account, 0, count)
+
+	for i := uint(0); i < count; i++ {
+		account, err := as.createHDAccount(ctx, &wallet, index+i, acc)
+		if err != nil {
+			return accounts, err
 		}
 
-		wallet.AccountIndex++
+		accounts = append(accounts, account)
 	}
 
-	obadaAccount := keyringAccountKey(profileID, wallet.AccountIndex)
+	return accounts, nil
+}
+
+// createHDAccount derives the account with given index from the wallet mnemonic and stores it
+func (as Service) createHDAccount(ctx context.Context, wallet *svcs.Wallet, index uint, acc Account) (svcs.Account, error) {
+	var account svcs.Account
 
-	hdPath := hd.CreateHDPath(118, uint32(wallet.AccountIndex), 0).String()
+	profileID := auth.GetClaims(ctx).UserID
 
-	keyringAccount, err := as.keyring.NewAccount(obadaAccount, wallet.Mnemonic, "", hdPath, hd.Secp256k1)
+	if err := as.checkNoRotation(profileID); err != nil {
+		return account, err
+	}
+
+	obadaAccount := keyringAccountKey(profileID, index)
+
+	keyringAccount, err := as.deriveKey(ctx, *wallet, obadaAccount, index)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicated address created") {
 			return account, ErrAccountExists
@@ -332,7 +511,15 @@ func (as Service) NewAccount(ctx context.Context, acc Account) (svcs.Account, er
 		return account, er
 	}
 
-	walletBytes, err := encoder.DataEncode(wallet)
+	if er := setAccountMetadata(batch, profileID, addr.String(), acc); er != nil {
+		return account, er
+	}
+
+	if index > wallet.AccountIndex {
+		wallet.AccountIndex = index
+	}
+
+	walletBytes, err := encoder.DataEncode(*wallet)
 	if err != nil {
 		return account, err
 	}
@@ -365,21 +552,37 @@ func (as Service) NewAccount(ctx context.Context, acc Account) (svcs.Account, er
 func (as Service) BalanceByAddress(ctx context.Context, address string) (svcs.Balance, error) {
 	var balance svcs.Balance
 
-	nodeBalance, err := as.nodeClient.BalanceByAddress(ctx, address)
+	coins, err := as.nodeClient.AllBalances(ctx, address)
 	if err != nil {
 		return balance, err
 	}
 
-	decCoin := sdk.NewDecCoinFromCoin(*nodeBalance.Balance)
+	metadatas, err := as.nodeClient.DenomsMetadata(ctx)
+	if err != nil {
+		return balance, err
+	}
+
+	decCoin := sdk.NewDecCoin(obadanode.BaseDenom, coins.AmountOf(obadanode.BaseDenom))
 
 	obdBalance, err := sdk.ConvertDecCoin(decCoin, "obd")
 	if err != nil {
 		return balance, err
 	}
 
+	balances := make([]svcs.CoinBalance, 0, len(coins))
+
+	for _, coin := range coins {
+		balances = append(balances, svcs.CoinBalance{
+			Denom:   coin.Denom,
+			Amount:  coin.Amount.String(),
+			Display: obadanode.DisplayCoin(coin, metadatas),
+		})
+	}
+
 	return svcs.Balance{
-		Address: address,
-		Balance: obdBalance,
+		Address:  address,
+		Balance:  obdBalance,
+		Balances: balances,
 	}, nil
 }
 
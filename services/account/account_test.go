@@ -3,11 +3,14 @@ package account_test
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/google/uuid"
 	"github.com/mustafaturan/bus/v3"
@@ -148,10 +151,10 @@ func TestService(t *testing.T) {
 		assert.Equal(t, defaultPubKey, hdAccounts[0].PublicKey)
 	}
 
-	t.Log("Test that adding new OBADA account creation will fail if there account with no transactions")
+	t.Log("Test that creating new OBADA accounts will fail if unused accounts exceed the gap limit")
 	{
-		_, err = service.NewAccount(ctx, account.Account{})
-		require.ErrorIs(t, err, account.ErrAccountHasZeroTx)
+		_, err = service.NewAccounts(ctx, account.Account{}, account.DefaultGapLimit)
+		require.ErrorIs(t, err, account.ErrGapLimitReached)
 
 		profileAccounts, er := service.GetProfileAccounts(ctx)
 		require.NoError(t, er)
@@ -160,6 +163,29 @@ func TestService(t *testing.T) {
 		assert.Equal(t, 0, len(profileAccounts.ImportedAccounts))
 	}
 
+	t.Log("Test creating unused OBADA accounts ahead of time within the gap limit")
+	{
+		accounts, er := service.NewAccounts(ctx, account.Account{Name: "ahead"}, 2)
+		require.NoError(t, er)
+		require.Len(t, accounts, 2)
+		assert.Equal(t, "ahead", accounts[0].Name)
+
+		walletAccountIndex, er := service.GetWalletAccountIndex(ctx)
+		require.NoError(t, er)
+		assert.Equal(t, uint(2), walletAccountIndex)
+
+		profileAccounts, er := service.GetProfileAccounts(ctx)
+		require.NoError(t, er)
+		assert.Equal(t, 3, len(profileAccounts.HDAccounts))
+	}
+
+	t.Log("Test rescan doesn't add accounts when the wallet has all used ones")
+	{
+		accounts, er := service.Rescan(ctx)
+		require.NoError(t, er)
+		assert.Empty(t, accounts)
+	}
+
 	t.Log("Test it will not import HD wallet if it already exists")
 	{
 		err = service.ImportWallet(ctx, defaultMnemonic, false)
@@ -211,7 +237,7 @@ func TestService_ExportWallet(t *testing.T) {
 
 	t.Log("Test account export")
 	{
-		exportedAccount, err := svc.ExportAccount(ctx, defaultAddress, "")
+		exportedAccount, err := svc.ExportAccount(ctx, defaultAddress, "", account.KeyFormatArmor)
 		require.NoError(t, err)
 
 		assert.True(t, strings.Contains(exportedAccount, "BEGIN TENDERMINT PRIVATE KEY"))
@@ -339,7 +365,7 @@ func TestService_ImportAccount(t *testing.T) {
 
 	t.Log("Test that HD account was not deleted")
 	{
-		err := svc.DeleteAccount(ctx, secondAddress)
+		err := svc.DeleteAccount(ctx, secondAddress, false)
 		require.ErrorIs(t, account.ErrHDAccountDelete, err)
 
 		profileAccounts, err := svc.GetProfileAccounts(ctx)
@@ -366,7 +392,7 @@ func TestService_ImportAccount(t *testing.T) {
 			bus.Handler{Handle: fn, Matcher: events.AccountDeleted},
 		)
 
-		err := svc.DeleteAccount(ctx, defaultAddress)
+		err := svc.DeleteAccount(ctx, defaultAddress, false)
 		require.NoError(t, err)
 
 		profileAccounts, err := svc.GetProfileAccounts(ctx)
@@ -377,6 +403,98 @@ func TestService_ImportAccount(t *testing.T) {
 	}
 }
 
+func TestService_ImportAccountFormats(t *testing.T) {
+	_, svc, ctx, deferFn := createTestService(t)
+	defer deferFn()
+
+	err := svc.ImportAccount(ctx, defaultObadaPrivateKey, "", account.Account{Name: "test"})
+	require.NoError(t, err)
+
+	t.Log("Test the account key is exported in all formats")
+
+	hexKey, err := svc.ExportAccount(ctx, defaultAddress, "", account.KeyFormatHex)
+	require.NoError(t, err)
+	assert.Len(t, hexKey, 64)
+
+	keystore, err := svc.ExportAccount(ctx, defaultAddress, "keystore-pass", account.KeyFormatKeystore)
+	require.NoError(t, err)
+	assert.Contains(t, keystore, `"kdf":"scrypt"`)
+
+	bundle, err := svc.ExportPubKey(ctx, defaultAddress)
+	require.NoError(t, err)
+	assert.Equal(t, defaultAddress, bundle.Address)
+	assert.Equal(t, "secp256k1", bundle.Type)
+	assert.NotEmpty(t, bundle.PubKey)
+
+	_, err = svc.ExportAccount(ctx, defaultAddress, "", account.KeyFormat("pem"))
+	require.ErrorIs(t, err, account.ErrUnknownKeyFormat)
+
+	t.Log("Test that the same key cannot be imported twice")
+	{
+		err := svc.ImportAccount(ctx, hexKey, "", account.Account{})
+		require.ErrorIs(t, err, account.ErrAccountExists)
+	}
+
+	t.Log("Test the hex key is imported")
+	{
+		require.NoError(t, svc.DeleteAccount(ctx, defaultAddress, false))
+
+		err := svc.ImportAccount(ctx, "0x"+hexKey, "", account.Account{})
+		require.NoError(t, err)
+
+		acc, err := svc.GetProfileAccount(ctx, defaultAddress)
+		require.NoError(t, err)
+		assert.Equal(t, bundle.PubKey, acc.PublicKey)
+	}
+
+	t.Log("Test the keystore is imported with the keystore passphrase")
+	{
+		require.NoError(t, svc.DeleteAccount(ctx, defaultAddress, false))
+
+		err := svc.ImportAccount(ctx, keystore, "wrong", account.Account{})
+		require.ErrorIs(t, err, account.ErrInvalidKeyPassphrase)
+
+		err = svc.ImportAccount(ctx, keystore, "keystore-pass", account.Account{})
+		require.NoError(t, err)
+
+		acc, err := svc.GetProfileAccount(ctx, defaultAddress)
+		require.NoError(t, err)
+		assert.Equal(t, bundle.PubKey, acc.PublicKey)
+	}
+
+	t.Log("Test keystores with oversized scrypt parameters are rejected before the key derivation")
+	{
+		require.Contains(t, keystore, `"n":262144,"p":1,"r":8`)
+
+		oversized := strings.Replace(keystore, `"n":262144,"p":1,"r":8`, `"n":1048576,"p":16,"r":32`, 1)
+
+		start := time.Now()
+		err := svc.ImportAccount(ctx, oversized, "keystore-pass", account.Account{})
+		require.ErrorIs(t, err, account.ErrInvalidKeystore)
+		assert.Less(t, time.Since(start), time.Second)
+
+		notPowerOfTwo := strings.Replace(keystore, `"n":262144`, `"n":262143`, 1)
+
+		err = svc.ImportAccount(ctx, notPowerOfTwo, "keystore-pass", account.Account{})
+		require.ErrorIs(t, err, account.ErrInvalidKeystore)
+	}
+
+	t.Log("Test invalid keys are rejected")
+	{
+		err := svc.ImportAccount(ctx, "not a key", "", account.Account{})
+		require.ErrorIs(t, err, account.ErrUnknownKeyFormat)
+
+		err = svc.ImportAccount(ctx, "abcd", "", account.Account{})
+		require.ErrorIs(t, err, account.ErrInvalidPrivateKey)
+
+		err = svc.ImportAccount(ctx, `{"version":1}`, "", account.Account{})
+		require.ErrorIs(t, err, account.ErrInvalidKeystore)
+
+		err = svc.ImportAccount(ctx, defaultObadaPrivateKey, "wrong", account.Account{})
+		require.ErrorIs(t, err, account.ErrInvalidKeyPassphrase)
+	}
+}
+
 func TestService_GetProfileAccount(t *testing.T) {
 
 	_, service, ctx, deferFn := createTestService(t)
@@ -412,12 +530,16 @@ func TestService_GetProfileAccount(t *testing.T) {
 		assert.Equal(t, defaultAddress, acc.Address)
 		assert.Equal(t, defaultPubKey, acc.PublicKey)
 		assert.Equal(t, "1.000000000000000000obd", acc.Balance.String())
+		require.Len(t, acc.Balances, 1)
+		assert.Equal(t, "rohi", acc.Balances[0].Denom)
+		assert.Equal(t, "1000000", acc.Balances[0].Amount)
+		assert.Equal(t, "1.000000000000000000obd", acc.Balances[0].Display.String())
 	}
 
-	privKey, err := service.GetAccountPrivateKey(ctx, defaultAddress)
+	accountSigner, err := service.GetAccountSigner(ctx, defaultAddress)
 	require.NoError(t, err)
 
-	assert.Equal(t, defaultPubKey, fmt.Sprintf("%X", privKey.PubKey().Bytes()))
+	assert.Equal(t, defaultPubKey, fmt.Sprintf("%X", accountSigner.PubKey().Bytes()))
 }
 
 func TestService_GetProfileAccounts(t *testing.T) {
@@ -587,6 +709,82 @@ func TestService_UpdateAccountName(t *testing.T) {
 	}
 }
 
+func TestService_UpdateAccountMetadata(t *testing.T) {
+	_, service, ctx, deferFn := createTestService(t)
+	defer deferFn()
+
+	_, err := service.NewWallet(ctx, secondMnemonic, false)
+	require.NoError(t, err, "Cannot create user profile HD wallet")
+
+	err = service.ImportAccount(ctx, defaultObadaPrivateKey, "", account.Account{Name: "imported"})
+	require.NoError(t, err)
+
+	tags := []string{"warehouse-a", "returns"}
+	description := "Devices of the warehouse A"
+	isDefault := true
+
+	t.Log("Testing account metadata is updated partially")
+	{
+		_, err = service.MintingAccount(ctx)
+		require.ErrorIs(t, err, account.ErrMintingAccountNotSet)
+
+		err = service.UpdateAccountMetadata(ctx, defaultAddress, services.UpdateAccount{
+			Description:       &description,
+			Tags:              tags,
+			DefaultForMinting: &isDefault,
+		})
+		require.NoError(t, err)
+
+		acc, err := service.GetProfileAccount(ctx, defaultAddress)
+		require.NoError(t, err)
+
+		assert.Equal(t, "imported", acc.Name)
+		assert.Equal(t, description, acc.Description)
+		assert.Equal(t, tags, acc.Tags)
+		assert.True(t, acc.DefaultForMinting)
+		assert.NotNil(t, acc.CreatedAt)
+
+		address, err := service.MintingAccount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, defaultAddress, address)
+	}
+
+	t.Log("Testing another minting account resets the previous one")
+	{
+		err = service.UpdateAccountMetadata(ctx, secondAddress, services.UpdateAccount{DefaultForMinting: &isDefault})
+		require.NoError(t, err)
+
+		address, err := service.MintingAccount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, secondAddress, address)
+
+		acc, err := service.GetAccountMetadata(ctx, defaultAddress)
+		require.NoError(t, err)
+		assert.False(t, acc.DefaultForMinting)
+	}
+
+	t.Log("Testing accounts are filtered by tag")
+	{
+		accounts, err := service.GetProfileAccounts(ctx)
+		require.NoError(t, err)
+
+		tagged := accounts.WithTag("warehouse-a")
+		assert.Len(t, tagged.ImportedAccounts, 1)
+		assert.Empty(t, tagged.HDAccounts)
+	}
+
+	t.Log("Testing the deleted minting account is not default anymore")
+	{
+		err = service.UpdateAccountMetadata(ctx, defaultAddress, services.UpdateAccount{DefaultForMinting: &isDefault})
+		require.NoError(t, err)
+
+		require.NoError(t, service.DeleteAccount(ctx, defaultAddress, false))
+
+		_, err = service.MintingAccount(ctx)
+		require.ErrorIs(t, err, account.ErrMintingAccountNotSet)
+	}
+}
+
 func TestService_GetProfileByAddress(t *testing.T) {
 	_, service, ctx, deferFn := createTestService(t)
 	defer deferFn()
@@ -618,3 +816,176 @@ func TestService_GetProfileByAddress(t *testing.T) {
 
 	}
 }
+
+func TestService_SigningPassphrase(t *testing.T) {
+	_, svc, ctx, deferFn := createTestService(t)
+	defer deferFn()
+
+	err := svc.ImportAccount(ctx, defaultObadaPrivateKey, "", account.Account{Name: "test"})
+	require.NoError(t, err)
+
+	signingCtx := func(passphrase string) context.Context {
+		return account.WithSigningPassphrase(ctx, passphrase)
+	}
+
+	t.Log("Test that keys are unlocked without passphrase when it is not set")
+	{
+		_, err := svc.GetAccountSigner(signingCtx(""), defaultAddress)
+		require.NoError(t, err)
+	}
+
+	t.Log("Test that the profile passphrase is required for signing")
+	{
+		err := svc.SetSigningPassphrase(signingCtx(""), "", services.SigningPassphrase{Passphrase: "1234"})
+		require.NoError(t, err)
+
+		_, err = svc.GetAccountSigner(signingCtx(""), defaultAddress)
+		require.ErrorIs(t, err, account.ErrSigningPassphraseRequired)
+
+		_, err = svc.GetAccountSigner(signingCtx("4321"), defaultAddress)
+		require.ErrorIs(t, err, account.ErrInvalidSigningPassphrase)
+
+		_, err = svc.GetAccountSigner(signingCtx("1234"), defaultAddress)
+		require.NoError(t, err)
+
+		_, err = svc.ExportAccount(signingCtx(""), defaultAddress, "", account.KeyFormatArmor)
+		require.ErrorIs(t, err, account.ErrSigningPassphraseRequired)
+
+		_, err = svc.GetAccountSigner(ctx, defaultAddress)
+		require.ErrorIs(t, err, account.ErrSigningPassphraseRequired, "the key is not unlocked without passphrase")
+	}
+
+	t.Log("Test that the account passphrase overrides the profile one")
+	{
+		err := svc.SetSigningPassphrase(signingCtx(""), defaultAddress, services.SigningPassphrase{Passphrase: "account-pin"})
+		require.ErrorIs(t, err, account.ErrSigningPassphraseRequired)
+
+		err = svc.SetSigningPassphrase(signingCtx("1234"), defaultAddress, services.SigningPassphrase{Passphrase: "account-pin"})
+		require.NoError(t, err)
+
+		_, err = svc.GetAccountSigner(signingCtx("1234"), defaultAddress)
+		require.ErrorIs(t, err, account.ErrInvalidSigningPassphrase)
+
+		_, err = svc.GetAccountSigner(signingCtx("account-pin"), defaultAddress)
+		require.NoError(t, err)
+	}
+
+	t.Log("Test that signing is locked after too many invalid passphrases")
+	{
+		for i := 0; i < account.MaxSigningAttempts; i++ {
+			_, err := svc.GetAccountSigner(signingCtx("wrong"), defaultAddress)
+			require.ErrorIs(t, err, account.ErrInvalidSigningPassphrase)
+		}
+
+		_, err := svc.GetAccountSigner(signingCtx("account-pin"), defaultAddress)
+		require.ErrorIs(t, err, account.ErrSigningLocked)
+	}
+}
+
+func TestService_DeleteAccountWithAssets(t *testing.T) {
+	_, svc, ctx, deferFn := createTestService(t)
+	defer deferFn()
+
+	err := svc.ImportAccount(ctx, defaultObadaPrivateKey, "", account.Account{})
+	require.NoError(t, err)
+
+	err = testutil.AddBalance(t, c, defaultAddress, "1000rohi")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		check, err := svc.DeletionCheck(ctx, defaultAddress)
+		return err == nil && len(check.Balances) > 0
+	}, 10*time.Second, 500*time.Millisecond)
+
+	t.Log("Test that the account with coins is not deleted without force")
+	{
+		check, err := svc.DeletionCheck(ctx, defaultAddress)
+		require.NoError(t, err)
+		assert.False(t, check.Exported)
+		assert.False(t, check.Deletable)
+
+		err = svc.DeleteAccount(ctx, defaultAddress, false)
+		require.ErrorIs(t, err, account.ErrAccountHasAssets)
+	}
+
+	t.Log("Test that the account is deletable after its key was exported")
+	{
+		_, err := svc.ExportAccount(ctx, defaultAddress, "", account.KeyFormatHex)
+		require.NoError(t, err)
+
+		check, err := svc.DeletionCheck(ctx, defaultAddress)
+		require.NoError(t, err)
+		assert.True(t, check.Exported)
+		assert.True(t, check.Deletable)
+
+		require.NoError(t, svc.DeleteAccount(ctx, defaultAddress, false))
+	}
+
+	t.Log("Test that the deletion is forced")
+	{
+		err := svc.ImportAccount(ctx, defaultObadaPrivateKey, "", account.Account{})
+		require.NoError(t, err)
+
+		err = svc.DeleteAccount(ctx, defaultAddress, false)
+		require.ErrorIs(t, err, account.ErrAccountHasAssets)
+
+		require.NoError(t, svc.DeleteAccount(ctx, defaultAddress, true))
+	}
+}
+
+func TestService_RemoteSigner(t *testing.T) {
+	svc, kr, signerKr, ctx, deferFn := createRemoteTestService(t)
+	defer deferFn()
+
+	privKey := secp256k1.GenPrivKey()
+	importedAddress := sdk.AccAddress(privKey.PubKey().Address()).String()
+
+	t.Log("Test that keys are created and imported by the signer")
+	{
+		_, err := svc.NewWallet(ctx, defaultMnemonic, false)
+		require.NoError(t, err)
+
+		_, err = svc.NewAccount(ctx, account.Account{})
+		require.NoError(t, err)
+
+		err = svc.ImportAccount(ctx, hex.EncodeToString(privKey.Bytes()), "", account.Account{})
+		require.NoError(t, err)
+
+		records, err := kr.List()
+		require.NoError(t, err)
+		assert.Empty(t, records, "the local keyring stays empty")
+
+		records, err = signerKr.List()
+		require.NoError(t, err)
+		assert.Len(t, records, 3)
+
+		wallet, err := svc.GetWallet(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, wallet.Mnemonic, "the mnemonic is kept by the signer")
+
+		accounts, err := svc.GetProfileAccounts(ctx)
+		require.NoError(t, err)
+		assert.Len(t, accounts.HDAccounts, 2)
+		assert.Len(t, accounts.ImportedAccounts, 1)
+		assert.Equal(t, defaultAddress, accounts.HDAccounts[0].Address)
+	}
+
+	t.Log("Test that accounts sign with keys of the signer")
+	{
+		s, err := svc.GetAccountSigner(ctx, importedAddress)
+		require.NoError(t, err)
+
+		signature, err := s.Sign(ctx, []byte("sign bytes"))
+		require.NoError(t, err)
+		assert.True(t, privKey.PubKey().VerifySignature([]byte("sign bytes"), signature))
+	}
+
+	t.Log("Test that keys and mnemonic are not exported")
+	{
+		_, err := svc.ExportAccount(ctx, defaultAddress, "", account.KeyFormatHex)
+		require.ErrorIs(t, err, account.ErrRemoteKeys)
+
+		_, err = svc.Mnemonic(ctx)
+		require.ErrorIs(t, err, account.ErrRemoteKeys)
+	}
+}
@@ -0,0 +1,51 @@
+package account
+
+import (
+	"context"
+	"strings"
+
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+)
+
+// DeletionCheck reports on-chain assets of the account before it is deleted. Watch-only accounts are always
+// deletable, the profile doesn't control their keys.
+func (as Service) DeletionCheck(ctx context.Context, address string) (svcs.AccountDeletionCheck, error) {
+	profileID := auth.GetUserID(ctx)
+
+	acc, err := as.GetProfileAccount(ctx, address)
+	if err != nil {
+		return svcs.AccountDeletionCheck{}, err
+	}
+
+	check := svcs.AccountDeletionCheck{
+		Address:   address,
+		Balances:  acc.Balances,
+		NFTsCount: acc.NFTsCount,
+	}
+
+	if acc.WatchOnly {
+		check.Deletable = true
+
+		return check, nil
+	}
+
+	key, err := as.keyByAddress(ctx, address)
+	if err != nil {
+		return check, err
+	}
+
+	if !strings.Contains(key.Name, "imported") {
+		return check, ErrHDAccountDelete
+	}
+
+	check.Exported, err = as.db.Has(accountExportedKey(profileID, address))
+	if err != nil {
+		return check, err
+	}
+
+	// the bank module doesn't keep zero balances, so any balance is the asset
+	check.Deletable = check.Exported || (acc.NFTsCount == 0 && len(acc.Balances) == 0)
+
+	return check, nil
+}
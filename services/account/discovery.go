@@ -0,0 +1,146 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+)
+
+// DefaultGapLimit how many unused HD accounts in a row end the discovery, BIP44 recommends 20
+const DefaultGapLimit uint = 20
+
+// Rescan discovers HD accounts that were created elsewhere with the same mnemonic and adds missing ones
+// to the wallet. Accounts are scanned until the gap limit of unused accounts in a row is reached,
+// all accounts up to the last used one are added. Returns the added accounts.
+func (as Service) Rescan(ctx context.Context) ([]svcs.Account, error) {
+	profileID := auth.GetClaims(ctx).UserID
+
+	wallet, err := as.GetWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lastUsed, found, err := as.discoverAccounts(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	lastIndex := wallet.AccountIndex
+	if found && lastUsed > lastIndex {
+		lastIndex = lastUsed
+	}
+
+	accounts := make([]svcs.Account, 0)
+
+	for index := uint(0); index <= lastIndex; index++ {
+		_, err := as.keyring.Key(keyringAccountKey(profileID, index))
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return accounts, err
+		}
+
+		account, err := as.createHDAccount(ctx, &wallet, index, Account{})
+		if err != nil {
+			return accounts, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// discoverAccounts returns the index of the last used account of the wallet
+func (as Service) discoverAccounts(ctx context.Context, wallet svcs.Wallet) (lastUsed uint, found bool, err error) {
+	gap := uint(0)
+
+	for index := uint(0); gap < as.gapLimit; index++ {
+		address, err := as.walletAddress(ctx, wallet, index)
+		if err != nil {
+			return 0, false, err
+		}
+
+		used, err := as.isAccountUsed(ctx, address)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if !used {
+			gap++
+			continue
+		}
+
+		lastUsed, found, gap = index, true, 0
+	}
+
+	return lastUsed, found, nil
+}
+
+// unusedAccounts returns how many accounts at the end of the wallet are not used
+func (as Service) unusedAccounts(ctx context.Context, wallet svcs.Wallet) (uint, error) {
+	unused := uint(0)
+
+	for index := wallet.AccountIndex; unused < as.gapLimit; index-- {
+		address, err := as.walletAddress(ctx, wallet, index)
+		if err != nil {
+			return 0, err
+		}
+
+		used, err := as.isAccountUsed(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+
+		if used {
+			break
+		}
+
+		unused++
+
+		if index == 0 {
+			break
+		}
+	}
+
+	return unused, nil
+}
+
+// isAccountUsed returns true when the account has transactions or owns NFTs, receiving the NFT
+// doesn't create the account on the chain
+func (as Service) isAccountUsed(ctx context.Context, address string) (bool, error) {
+	ok, err := as.nodeClient.HasAccount(ctx, address)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	nfts, err := as.nodeClient.GetNFTByAddress(ctx, address)
+	if err != nil {
+		return false, err
+	}
+
+	return len(nfts) > 0, nil
+}
+
+func hdPath(index uint) string {
+	return hd.CreateHDPath(118, uint32(index), 0).String()
+}
+
+// deriveAddress derives the account address without storing the key in the keyring
+func deriveAddress(mnemonic string, index uint) (string, error) {
+	derived, err := hd.Secp256k1.Derive()(mnemonic, "", hdPath(index))
+	if err != nil {
+		return "", err
+	}
+
+	privKey := hd.Secp256k1.Generate()(derived)
+
+	return sdk.AccAddress(privKey.PubKey().Address()).String(), nil
+}
@@ -23,22 +23,100 @@ var (
 	// ErrAccountExists account already exists
 	ErrAccountExists = errors.New("account already exists")
 
-	// ErrAccountHasZeroTx no tx for account
-	ErrAccountHasZeroTx = errors.New("please use the prior address before creating a new one")
+	// ErrGapLimitReached too many unused HD accounts in a row
+	ErrGapLimitReached = errors.New("too many unused accounts, please use one of them before creating a new one")
+
+	// ErrMultisigAccount multisig account cannot sign alone
+	ErrMultisigAccount = errors.New("multisig account transactions have to be approved by co-signers")
+
+	// ErrNotMultisigAccount account is not multisig
+	ErrNotMultisigAccount = errors.New("account is not multisig")
+
+	// ErrNoMultisigMember none of co-signers is the profile account
+	ErrNoMultisigMember = errors.New("none of multisig co-signers is the profile account")
+
+	// ErrWatchOnlyAccount the profile doesn't control the key of the account
+	ErrWatchOnlyAccount = errors.New("account is watch-only, its key is not managed by client-helper")
+
+	// ErrMintingAccountNotSet the profile has no default minting account
+	ErrMintingAccountNotSet = errors.New("default minting account is not set")
+
+	// ErrUnknownKeyFormat the imported key is neither armored, hex nor keystore JSON
+	ErrUnknownKeyFormat = errors.New("unknown key format, expected armored private key, hex private key or keystore JSON")
+
+	// ErrInvalidPrivateKey the private key cannot be decoded
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+
+	// ErrInvalidKeystore the keystore JSON is malformed or uses unsupported parameters
+	ErrInvalidKeystore = errors.New("invalid keystore")
+
+	// ErrInvalidKeyPassphrase the passphrase of the encrypted private key doesn't match
+	ErrInvalidKeyPassphrase = errors.New("cannot decrypt private key, the passphrase doesn't match")
+
+	// ErrWalletRotationInProgress the wallet cannot be changed until its rotation is completed
+	ErrWalletRotationInProgress = errors.New("profile wallet rotation is in progress")
+
+	// ErrWalletRotationNotExists no wallet rotation
+	ErrWalletRotationNotExists = errors.New("profile wallet rotation doesn't exists")
 
 	// ErrInvalidMnemonic invalid mnemonic
 	ErrInvalidMnemonic = errors.New("invalid mnemonic")
 
+	// ErrAccountHasAssets the account holds assets on chain and its key was not exported
+	ErrAccountHasAssets = errors.New("account holds NFTs or coins, export its key, move the assets or force the deletion")
+
 	// ErrHDAccountDelete cannot delete hd account
 	ErrHDAccountDelete = errors.New("cannot delete hd account")
+
+	// ErrSigningPassphraseRequired the account is protected by the signing passphrase
+	ErrSigningPassphraseRequired = errors.New("signing passphrase is required")
+
+	// ErrInvalidSigningPassphrase the signing passphrase doesn't match
+	ErrInvalidSigningPassphrase = errors.New("invalid signing passphrase")
+
+	// ErrSigningLocked too many invalid signing passphrases
+	ErrSigningLocked = errors.New("too many invalid signing passphrase attempts, please try again later")
+
+	// ErrRemoteKeys keys are kept by the remote signer
+	ErrRemoteKeys = errors.New("keys are kept by the remote signer, private keys and mnemonics cannot be exported")
+
+	// ErrSigningPassphraseNotExists no signing passphrase
+	ErrSigningPassphraseNotExists = errors.New("signing passphrase doesn't exists")
 )
 
 // IsAccountError errors that can send back to the client
 func IsAccountError(err error) bool {
 	return errors.Is(err, ErrProfileExists) ||
-		errors.Is(err, ErrAccountHasZeroTx) ||
+		errors.Is(err, ErrGapLimitReached) ||
 		errors.Is(err, ErrAccountExists) ||
+		errors.Is(err, ErrAccountHasAssets) ||
+		errors.Is(err, ErrMultisigAccount) ||
+		errors.Is(err, ErrNotMultisigAccount) ||
+		errors.Is(err, ErrNoMultisigMember) ||
+		errors.Is(err, ErrMintingAccountNotSet) ||
+		errors.Is(err, ErrWatchOnlyAccount) ||
 		errors.Is(err, ErrWalletExists) ||
+		errors.Is(err, ErrWalletRotationInProgress) ||
+		errors.Is(err, ErrWalletRotationNotExists) ||
 		errors.Is(err, ErrInvalidMnemonic) ||
-		errors.Is(err, ErrWalletNotExists)
+		IsKeyFormatError(err) ||
+		errors.Is(err, ErrWalletNotExists) ||
+		errors.Is(err, ErrSigningPassphraseNotExists) ||
+		errors.Is(err, ErrRemoteKeys) ||
+		IsSigningError(err)
+}
+
+// IsKeyFormatError errors of the key import and export formats
+func IsKeyFormatError(err error) bool {
+	return errors.Is(err, ErrUnknownKeyFormat) ||
+		errors.Is(err, ErrInvalidPrivateKey) ||
+		errors.Is(err, ErrInvalidKeystore) ||
+		errors.Is(err, ErrInvalidKeyPassphrase)
+}
+
+// IsSigningError errors of the signing passphrase check
+func IsSigningError(err error) bool {
+	return errors.Is(err, ErrSigningPassphraseRequired) ||
+		errors.Is(err, ErrInvalidSigningPassphrase) ||
+		errors.Is(err, ErrSigningLocked)
 }
@@ -3,9 +3,11 @@ package account_test
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	dkeyring "github.com/99designs/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
@@ -14,7 +16,9 @@ import (
 	"github.com/obada-foundation/client-helper/events"
 	"github.com/obada-foundation/client-helper/services/account"
 	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
+	chtestutil "github.com/obada-foundation/client-helper/testutil"
 	"github.com/stretchr/testify/require"
 	db "github.com/tendermint/tm-db"
 )
@@ -68,3 +72,64 @@ func createTestService(t *testing.T) (*bus.Bus, *account.Service, context.Contex
 
 	return b, service, ctx, deferFn
 }
+
+// createRemoteTestService creates the service that keeps keys on the remote signer, returns the local keyring
+// of the service and the keyring of the signer
+func createRemoteTestService(t *testing.T) (*account.Service, keyring.Keyring, keyring.Keyring, context.Context, func()) {
+	v, err := validate.NewValidator()
+	require.NoError(t, err, "Cannot initialize validation")
+
+	database, err := db.NewDB("accounts", db.MemDBBackend, "./testdb")
+	require.NoError(t, err, "Cannot initialize database")
+
+	nodeClient, err := obadanode.NewClient(
+		context.Background(),
+		"obada-testnet",
+		fmt.Sprintf("tcp://%s:%d", c.Host, c.Ports["26657"]),
+		fmt.Sprintf("%s:%d", c.Host, c.Ports["9090"]),
+	)
+	require.NoError(t, err, "Cannot initialize OBADA Node client")
+
+	cdc := cosmostestutil.MakeTestEncodingConfig().Codec
+
+	kr := keyring.NewInMemory(cdc)
+	signerKr := keyring.NewInMemory(cdc)
+
+	logger, deleteLogFile := chtestutil.MakeLoger()
+
+	srv := httptest.NewServer(signer.NewServer(signer.ServerConfig{
+		Provider: signer.NewKeyring(signerKr),
+		Keyring:  signerKr,
+		Wallets:  dkeyring.NewArrayKeyring(nil),
+		Logger:   logger,
+	}))
+
+	remote, err := signer.NewRemote(signer.RemoteConfig{URL: srv.URL})
+	require.NoError(t, err)
+
+	var fn bus.Next = func() string { return "afakeid" }
+	b, err := bus.NewBus(fn)
+	require.NoError(t, err, "Cannot initialize event bus")
+
+	b.RegisterTopics(events.AccountDeleted, events.AccountCreated)
+
+	service := account.NewService(v, database, &nodeClient, kr, b)
+	service.SetRemoteSigner(remote)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	ctx = auth.SetClaims(ctx, auth.Claims{
+		UserID: "3",
+	})
+
+	_, err = makeProfile(t, service, ctx)
+	require.NoError(t, err)
+
+	deferFn := func() {
+		cancel()
+		srv.Close()
+		deleteLogFile()
+	}
+
+	return service, kr, signerKr, ctx, deferFn
+}
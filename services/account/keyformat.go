@@ -0,0 +1,315 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+// KeyFormat is the format of the imported or exported private key
+type KeyFormat string
+
+const (
+	// KeyFormatArmor ASCII armored private key encrypted with the passphrase, the format of Cosmos SDK keyring
+	KeyFormatArmor KeyFormat = "armor"
+
+	// KeyFormatHex raw secp256k1 private key encoded in hex, the passphrase is not used
+	KeyFormatHex KeyFormat = "hex"
+
+	// KeyFormatKeystore Web3 Secret Storage (version 3) JSON, the key is encrypted by AES-128-CTR
+	// with the key derived from the passphrase by scrypt
+	KeyFormatKeystore KeyFormat = "keystore"
+
+	// KeyFormatPubKey public key and address of the account, the private key is not exported
+	KeyFormatPubKey KeyFormat = "pubkey"
+)
+
+const (
+	keystoreVersion = 3
+	keystoreKDF     = "scrypt"
+	keystoreCipher  = "aes-128-ctr"
+
+	// scrypt parameters of exported keystores, the same as the standard parameters of Ethereum tooling
+	keystoreScryptN     = 1 << 18
+	keystoreScryptR     = 8
+	keystoreScryptP     = 1
+	keystoreScryptDKLen = 32
+
+	// limits of imported keystores parameters, scrypt needs about 128*N*r*p bytes of memory,
+	// the limit fits the standard parameters of Ethereum tooling
+	keystoreMaxScryptN      = 1 << 20
+	keystoreMaxScryptR      = 32
+	keystoreMaxScryptP      = 16
+	keystoreMaxScryptMemory = 256 << 20
+)
+
+type keystoreJSON struct {
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string               `json:"cipher"`
+	CipherText   string               `json:"ciphertext"`
+	CipherParams keystoreCipherParams `json:"cipherparams"`
+	KDF          string               `json:"kdf"`
+	KDFParams    keystoreScryptParams `json:"kdfparams"`
+	MAC          string               `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+type keystoreScryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+// DetectKeyFormat detects the format of the private key
+func DetectKeyFormat(privateKey string) (KeyFormat, error) {
+	key := strings.TrimSpace(privateKey)
+
+	switch {
+	case strings.HasPrefix(key, "-----BEGIN"):
+		return KeyFormatArmor, nil
+	case strings.HasPrefix(key, "{"):
+		return KeyFormatKeystore, nil
+	case key != "":
+		if _, err := hex.DecodeString(strings.TrimPrefix(key, "0x")); err == nil {
+			return KeyFormatHex, nil
+		}
+	}
+
+	return "", ErrUnknownKeyFormat
+}
+
+// decodePrivKey decodes the secp256k1 private key of any supported format
+func decodePrivKey(privateKey, passphrase string) (cryptotypes.PrivKey, error) {
+	format, err := DetectKeyFormat(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimSpace(privateKey)
+
+	switch format {
+	case KeyFormatArmor:
+		return decodeArmoredPrivKey(key, passphrase)
+	case KeyFormatKeystore:
+		return decryptKeystore(key, passphrase)
+	default:
+		return decodeHexPrivKey(key)
+	}
+}
+
+func decodeArmoredPrivKey(armor, passphrase string) (cryptotypes.PrivKey, error) {
+	privKey, algo, err := crypto.UnarmorDecryptPrivKey(armor, passphrase)
+	if err != nil {
+		if errors.Is(err, sdkerrors.ErrWrongPassword) {
+			return nil, ErrInvalidKeyPassphrase
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPrivateKey, err)
+	}
+
+	if algo != string(hd.Secp256k1Type) {
+		return nil, fmt.Errorf("%w: %q keys are not supported", ErrInvalidPrivateKey, algo)
+	}
+
+	return privKey, nil
+}
+
+func decodeHexPrivKey(key string) (cryptotypes.PrivKey, error) {
+	keyBytes, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPrivateKey, err)
+	}
+
+	return privKeyFromBytes(keyBytes)
+}
+
+func privKeyFromBytes(keyBytes []byte) (cryptotypes.PrivKey, error) {
+	if len(keyBytes) != secp256k1.PrivKeySize {
+		return nil, fmt.Errorf("%w: secp256k1 key must be %d bytes long", ErrInvalidPrivateKey, secp256k1.PrivKeySize)
+	}
+
+	return &secp256k1.PrivKey{Key: keyBytes}, nil
+}
+
+// encryptKeystore encrypts the private key to the keystore JSON
+func encryptKeystore(privKey cryptotypes.PrivKey, address, passphrase string) (string, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, keystoreScryptN, keystoreScryptR, keystoreScryptP, keystoreScryptDKLen)
+	if err != nil {
+		return "", err
+	}
+
+	cipherText, err := aesCTR(derivedKey[:16], iv, privKey.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	keystore := keystoreJSON{
+		Address: address,
+		Crypto: keystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          keystoreKDF,
+			KDFParams: keystoreScryptParams{
+				DKLen: keystoreScryptDKLen,
+				N:     keystoreScryptN,
+				P:     keystoreScryptP,
+				R:     keystoreScryptR,
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(keystoreMAC(derivedKey, cipherText)),
+		},
+		ID:      uuid.NewString(),
+		Version: keystoreVersion,
+	}
+
+	b, err := json.Marshal(keystore)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// decryptKeystore decrypts the private key of the keystore JSON, the MAC is verified before the decryption,
+// so the wrong passphrase is reported instead of the invalid key
+func decryptKeystore(keystore, passphrase string) (cryptotypes.PrivKey, error) {
+	var ks keystoreJSON
+
+	if err := json.Unmarshal([]byte(keystore), &ks); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeystore, err)
+	}
+
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("%w: version %d is not supported", ErrInvalidKeystore, ks.Version)
+	}
+
+	if ks.Crypto.KDF != keystoreKDF {
+		return nil, fmt.Errorf("%w: kdf %q is not supported", ErrInvalidKeystore, ks.Crypto.KDF)
+	}
+
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("%w: cipher %q is not supported", ErrInvalidKeystore, ks.Crypto.Cipher)
+	}
+
+	params := ks.Crypto.KDFParams
+
+	if err := validateScryptParams(params); err != nil {
+		return nil, err
+	}
+
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: salt: %s", ErrInvalidKeystore, err)
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: invalid iv", ErrInvalidKeystore)
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ciphertext: %s", ErrInvalidKeystore, err)
+	}
+
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mac: %s", ErrInvalidKeystore, err)
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeystore, err)
+	}
+
+	if subtle.ConstantTimeCompare(keystoreMAC(derivedKey, cipherText), mac) != 1 {
+		return nil, ErrInvalidKeyPassphrase
+	}
+
+	keyBytes, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	return privKeyFromBytes(keyBytes)
+}
+
+// validateScryptParams checks the scrypt parameters of the imported keystore before the key derivation,
+// so a crafted keystore cannot make scrypt allocate gigabytes of memory
+func validateScryptParams(params keystoreScryptParams) error {
+	if params.DKLen < keystoreScryptDKLen || params.DKLen > 2*keystoreScryptDKLen {
+		return fmt.Errorf("%w: scrypt dklen %d is out of range", ErrInvalidKeystore, params.DKLen)
+	}
+
+	if params.N <= 1 || params.N > keystoreMaxScryptN || params.N&(params.N-1) != 0 {
+		return fmt.Errorf("%w: scrypt n %d must be a power of two up to %d", ErrInvalidKeystore, params.N, keystoreMaxScryptN)
+	}
+
+	if params.R < 1 || params.R > keystoreMaxScryptR || params.P < 1 || params.P > keystoreMaxScryptP {
+		return fmt.Errorf("%w: scrypt r %d or p %d is out of range", ErrInvalidKeystore, params.R, params.P)
+	}
+
+	if memory := 128 * int64(params.N) * int64(params.R) * int64(params.P); memory > keystoreMaxScryptMemory {
+		return fmt.Errorf("%w: scrypt needs %d bytes of memory, the limit is %d", ErrInvalidKeystore, memory, keystoreMaxScryptMemory)
+	}
+
+	return nil
+}
+
+// keystoreMAC is keccak256 of the second half of the derived key and the ciphertext
+func keystoreMAC(derivedKey, cipherText []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+
+	return hash.Sum(nil)
+}
+
+func aesCTR(key, iv, text []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(text))
+	cipher.NewCTR(block, iv).XORKeyStream(out, text)
+
+	return out, nil
+}
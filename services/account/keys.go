@@ -1,6 +1,9 @@
 package account
 
-import "fmt"
+import (
+	"crypto/sha256"
+	"fmt"
+)
 
 const (
 	prefix = "profiles:"
@@ -22,6 +25,12 @@ func keyringAccountKey(profileID string, index uint) string {
 	return fmt.Sprintf("%s_%d", profileID, index)
 }
 
+// signerWalletName names the wallet on the remote signer, the mnemonic hash keeps names of the profile wallet
+// and its rotation apart
+func signerWalletName(profileID, mnemonic string) string {
+	return fmt.Sprintf("%s_wallet_%x", profileID, sha256.Sum256([]byte(mnemonic)))
+}
+
 func keyringAccountImportedKey(profileID string, index uint) string {
 	return fmt.Sprintf("%s_imported_%d", profileID, index)
 }
@@ -30,6 +39,10 @@ func keyringAccountPrefix(profileID string) string {
 	return fmt.Sprintf("%s_", profileID)
 }
 
+func keyringAccountMultisigKey(profileID, accountAddress string) string {
+	return fmt.Sprintf("%s_multisig_%s", profileID, accountAddress)
+}
+
 func accountHDKey(profileID, accountAddress string) []byte {
 	return []byte(fmt.Sprintf("%s%s:hd-accounts:%s", prefix, profileID, accountAddress))
 }
@@ -37,3 +50,43 @@ func accountHDKey(profileID, accountAddress string) []byte {
 func accountImportedKey(profileID, accountAddress string) []byte {
 	return []byte(fmt.Sprintf("%s%s:imported-accounts:%s", prefix, profileID, accountAddress))
 }
+
+func accountMultisigKey(profileID, accountAddress string) []byte {
+	return []byte(fmt.Sprintf("%s%s:multisig-accounts:%s", prefix, profileID, accountAddress))
+}
+
+func accountWatchKey(profileID, accountAddress string) []byte {
+	return []byte(fmt.Sprintf("%s%s:watch-accounts:%s", prefix, profileID, accountAddress))
+}
+
+func accountMetadataKey(profileID, accountAddress string) []byte {
+	return []byte(fmt.Sprintf("%s%s:account-metadata:%s", prefix, profileID, accountAddress))
+}
+
+func accountExportedKey(profileID, accountAddress string) []byte {
+	return []byte(fmt.Sprintf("%s%s:account-exported:%s", prefix, profileID, accountAddress))
+}
+
+func mintingAccountKey(profileID string) []byte {
+	return []byte(fmt.Sprintf("%s%s:minting-account", prefix, profileID))
+}
+
+func signingPassphraseKey(profileID string) []byte {
+	return []byte(fmt.Sprintf("%s%s:signing-passphrase", prefix, profileID))
+}
+
+func accountSigningPassphraseKey(profileID, accountAddress string) []byte {
+	return []byte(fmt.Sprintf("%s%s:signing-passphrase:%s", prefix, profileID, accountAddress))
+}
+
+func signingAttemptsKey(profileID string) []byte {
+	return []byte(fmt.Sprintf("%s%s:signing-attempts", prefix, profileID))
+}
+
+func rotationWalletKey(id string) []byte {
+	return []byte(fmt.Sprintf("%s%s:rotation-wallet", prefix, id))
+}
+
+func keyringAccountRotationKey(profileID string, index uint) string {
+	return fmt.Sprintf("%s_rotation_%d", profileID, index)
+}
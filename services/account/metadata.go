@@ -0,0 +1,231 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/encoder"
+	"github.com/tendermint/tm-db"
+)
+
+// GetAccountMetadata returns the metadata record of the profile account
+func (as Service) GetAccountMetadata(ctx context.Context, address string) (Account, error) {
+	profileID := auth.GetUserID(ctx)
+
+	watchOnly, err := as.isWatchAccount(profileID, address)
+	if err != nil {
+		return Account{}, err
+	}
+
+	if watchOnly {
+		return as.accountMetadata(profileID, address, accountWatchKey(profileID, address))
+	}
+
+	key, err := as.metadataKeyByAddress(ctx, address)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return as.accountMetadata(profileID, address, accountTypeKey(profileID, key, address))
+}
+
+// UpdateAccountMetadata updates the account metadata, only fields that are set in the request are changed.
+// The profile has a single minting account, making another account default resets the previous one.
+func (as Service) UpdateAccountMetadata(ctx context.Context, address string, upd svcs.UpdateAccount) error {
+	profileID := auth.GetUserID(ctx)
+
+	if err := as.validator.Check(upd); err != nil {
+		return err
+	}
+
+	acc, err := as.GetAccountMetadata(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if upd.Name != nil {
+		acc.Name = *upd.Name
+	}
+
+	if upd.Description != nil {
+		acc.Description = *upd.Description
+	}
+
+	if upd.Tags != nil {
+		acc.Tags = upd.Tags
+	}
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	if upd.DefaultForMinting != nil && *upd.DefaultForMinting != acc.DefaultForMinting {
+		if er := as.setMintingAccount(batch, profileID, address, *upd.DefaultForMinting); er != nil {
+			return er
+		}
+
+		acc.DefaultForMinting = *upd.DefaultForMinting
+	}
+
+	accountBytes, err := encoder.DataEncode(acc)
+	if err != nil {
+		return err
+	}
+
+	if err := batch.Set(accountMetadataKey(profileID, address), accountBytes); err != nil {
+		return err
+	}
+
+	return batch.WriteSync()
+}
+
+// UpdateAccountName updates the account name
+func (as Service) UpdateAccountName(ctx context.Context, address, newAccountName string) error {
+	return as.UpdateAccountMetadata(ctx, address, svcs.UpdateAccount{Name: &newAccountName})
+}
+
+// MintingAccount returns the address of the profile default minting account
+func (as Service) MintingAccount(ctx context.Context) (string, error) {
+	b, err := as.db.Get(mintingAccountKey(auth.GetUserID(ctx)))
+	if err != nil {
+		return "", err
+	}
+
+	if b == nil {
+		return "", ErrMintingAccountNotSet
+	}
+
+	return string(b), nil
+}
+
+// setMintingAccount moves the profile minting account pointer, the flag of the previous minting account is reset
+func (as Service) setMintingAccount(batch db.Batch, profileID, address string, isDefault bool) error {
+	current, err := as.db.Get(mintingAccountKey(profileID))
+	if err != nil {
+		return err
+	}
+
+	if !isDefault {
+		if string(current) == address {
+			return batch.Delete(mintingAccountKey(profileID))
+		}
+
+		return nil
+	}
+
+	if current != nil && string(current) != address {
+		if err := as.resetMintingFlag(batch, profileID, string(current)); err != nil {
+			return err
+		}
+	}
+
+	return batch.Set(mintingAccountKey(profileID), []byte(address))
+}
+
+func (as Service) resetMintingFlag(batch db.Batch, profileID, address string) error {
+	b, err := as.db.Get(accountMetadataKey(profileID, address))
+	if err != nil || b == nil {
+		return err
+	}
+
+	var acc Account
+
+	if err := gob.NewDecoder(bytes.NewBuffer(b)).Decode(&acc); err != nil {
+		return err
+	}
+
+	acc.DefaultForMinting = false
+
+	accountBytes, err := encoder.DataEncode(acc)
+	if err != nil {
+		return err
+	}
+
+	return batch.Set(accountMetadataKey(profileID, address), accountBytes)
+}
+
+// setAccountMetadata adds the metadata record of the new account to the batch
+func setAccountMetadata(batch db.Batch, profileID, address string, acc Account) error {
+	// The account becomes default for minting only by the metadata update, that resets the previous one
+	acc.DefaultForMinting = false
+
+	if acc.CreatedAt.IsZero() {
+		acc.CreatedAt = time.Now().UTC()
+	}
+
+	accountBytes, err := encoder.DataEncode(acc)
+	if err != nil {
+		return err
+	}
+
+	return batch.Set(accountMetadataKey(profileID, address), accountBytes)
+}
+
+// deleteAccountMetadata adds removal of the account metadata to the batch, including the minting account pointer
+func (as Service) deleteAccountMetadata(batch db.Batch, profileID, address string) error {
+	if err := as.setMintingAccount(batch, profileID, address, false); err != nil {
+		return err
+	}
+
+	return batch.Delete(accountMetadataKey(profileID, address))
+}
+
+// accountMetadata reads the metadata record of the account. Accounts created before records were stored
+// by address keep their name under the account type key.
+func (as Service) accountMetadata(profileID, address string, typeKey []byte) (Account, error) {
+	var acc Account
+
+	accBytes, err := as.db.Get(accountMetadataKey(profileID, address))
+	if err != nil {
+		return acc, err
+	}
+
+	if accBytes == nil {
+		accBytes, err = as.db.Get(typeKey)
+		if err != nil {
+			return acc, err
+		}
+	}
+
+	if len(accBytes) == 0 {
+		return acc, nil
+	}
+
+	if err := gob.NewDecoder(bytes.NewBuffer(accBytes)).Decode(&acc); err != nil {
+		return acc, err
+	}
+
+	return acc, nil
+}
+
+func (as Service) metadataKeyByAddress(ctx context.Context, address string) (*keyring.Record, error) {
+	key, err := as.keyByAddress(ctx, address)
+	if err != nil {
+		if errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return nil, ErrAccountNotExists
+		}
+
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// accountTypeKey returns the key that lists the account among accounts of its type
+func accountTypeKey(profileID string, key *keyring.Record, address string) []byte {
+	switch {
+	case strings.Contains(key.Name, "imported"):
+		return accountImportedKey(profileID, address)
+	case key.GetType() == keyring.TypeMulti:
+		return accountMultisigKey(profileID, address)
+	default:
+		return accountHDKey(profileID, address)
+	}
+}
@@ -0,0 +1,288 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/encoder"
+	"github.com/obada-foundation/client-helper/system/signer"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/tendermint/tm-db"
+)
+
+// CreateMultisig creates the legacy amino multisig account of profile accounts and external public keys.
+// Co-signers are sorted by address, so the same keys always give the same account.
+func (as Service) CreateMultisig(ctx context.Context, nm svcs.NewMultisig) (svcs.Account, error) {
+	var account svcs.Account
+
+	profileID := auth.GetClaims(ctx).UserID
+
+	if err := as.validator.Check(nm); err != nil {
+		return account, err
+	}
+
+	pubKeys := make([]cryptotypes.PubKey, 0, len(nm.Addresses)+len(nm.PubKeys))
+
+	for _, address := range nm.Addresses {
+		key, err := as.keyByAddress(ctx, address)
+		if err != nil {
+			return account, fmt.Errorf("co-signer %s: %w", address, err)
+		}
+
+		if key.GetType() == keyring.TypeMulti {
+			return account, validate.FieldErrors{
+				validate.FieldError{
+					Field: "addresses",
+					Error: fmt.Sprintf("%s is multisig account", address),
+				},
+			}
+		}
+
+		pubKey, err := key.GetPubKey()
+		if err != nil {
+			return account, err
+		}
+
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	for _, hexKey := range nm.PubKeys {
+		b, err := hex.DecodeString(hexKey)
+		if err != nil || len(b) != secp256k1.PubKeySize {
+			return account, validate.FieldErrors{
+				validate.FieldError{
+					Field: "pub_keys",
+					Error: fmt.Sprintf("%q is not hex encoded secp256k1 public key", hexKey),
+				},
+			}
+		}
+
+		pubKeys = append(pubKeys, &secp256k1.PubKey{Key: b})
+	}
+
+	if len(pubKeys) < 2 {
+		return account, validate.FieldErrors{
+			validate.FieldError{
+				Field: "addresses",
+				Error: "multisig account requires at least two co-signers",
+			},
+		}
+	}
+
+	if nm.Threshold > uint(len(pubKeys)) {
+		return account, validate.FieldErrors{
+			validate.FieldError{
+				Field: "threshold",
+				Error: "threshold cannot exceed the number of co-signers",
+			},
+		}
+	}
+
+	sort.Slice(pubKeys, func(i, j int) bool {
+		return bytes.Compare(pubKeys[i].Address(), pubKeys[j].Address()) < 0
+	})
+
+	for i := 1; i < len(pubKeys); i++ {
+		if pubKeys[i].Equals(pubKeys[i-1]) {
+			return account, validate.FieldErrors{
+				validate.FieldError{
+					Field: "pub_keys",
+					Error: fmt.Sprintf("co-signer %s is duplicated", sdk.AccAddress(pubKeys[i].Address())),
+				},
+			}
+		}
+	}
+
+	multisigPubKey := kmultisig.NewLegacyAminoPubKey(int(nm.Threshold), pubKeys)
+	address := sdk.AccAddress(multisigPubKey.Address()).String()
+
+	accountBytes, err := encoder.DataEncode(Account{Name: nm.Name})
+	if err != nil {
+		return account, err
+	}
+
+	key, err := as.keyring.SaveMultisig(keyringAccountMultisigKey(profileID, address), multisigPubKey)
+	if err != nil {
+		if errors.Is(err, keyring.ErrKeyAlreadyExists) {
+			return account, ErrAccountExists
+		}
+
+		return account, fmt.Errorf("cannot save multisig key: %w", err)
+	}
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(accountMultisigKey(profileID, address), accountBytes); err != nil {
+		return account, err
+	}
+
+	if err := setAccountMetadata(batch, profileID, address, Account{Name: nm.Name}); err != nil {
+		return account, err
+	}
+
+	if err := batch.WriteSync(); err != nil {
+		if er := as.keyring.Delete(key.Name); er != nil {
+			return account, fmt.Errorf("%s : %w", err.Error(), er)
+		}
+
+		return account, err
+	}
+
+	return as.Keyring2Account(ctx, key)
+}
+
+// GetMultisigPubKey returns the public key of the profile multisig account
+func (as Service) GetMultisigPubKey(ctx context.Context, address string) (*kmultisig.LegacyAminoPubKey, error) {
+	key, err := as.keyByAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := key.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	multisigPubKey, ok := pubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return nil, ErrNotMultisigAccount
+	}
+
+	return multisigPubKey, nil
+}
+
+// GetCosignedMultisigPubKey returns the public key of the multisig account that is the profile account or has
+// a co-signer that is the profile account, co-signers of the multisig account may belong to different profiles
+func (as Service) GetCosignedMultisigPubKey(ctx context.Context, address string) (*kmultisig.LegacyAminoPubKey, error) {
+	multisigPubKey, err := as.GetMultisigPubKey(ctx, address)
+	if !errors.Is(err, ErrAccountNotExists) {
+		return multisigPubKey, err
+	}
+
+	addr, er := sdk.AccAddressFromBech32(address)
+	if er != nil {
+		return nil, er
+	}
+
+	key, er := as.keyring.KeyByAddress(addr)
+	if er != nil {
+		return nil, er
+	}
+
+	pubKey, er := key.GetPubKey()
+	if er != nil {
+		return nil, er
+	}
+
+	multisigPubKey, ok := pubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return nil, err
+	}
+
+	for _, pk := range multisigPubKey.GetPubKeys() {
+		if _, er := as.keyByAddress(ctx, sdk.AccAddress(pk.Address()).String()); er == nil {
+			return multisigPubKey, nil
+		}
+	}
+
+	return nil, err
+}
+
+// IsMultisig returns true when the address is the profile multisig account
+func (as Service) IsMultisig(ctx context.Context, address string) (bool, error) {
+	key, err := as.keyByAddress(ctx, address)
+	if err != nil {
+		return false, err
+	}
+
+	return key.GetType() == keyring.TypeMulti, nil
+}
+
+// GetRegistrySigner returns the signer of DID registry documents of devices owned by the account.
+// The multisig account cannot sign registry documents, the first co-signer that is the profile account
+// signs them on behalf of the multisig account.
+func (as Service) GetRegistrySigner(ctx context.Context, address string) (signer.Signer, error) {
+	multisigPubKey, err := as.GetMultisigPubKey(ctx, address)
+	if err != nil {
+		if errors.Is(err, ErrNotMultisigAccount) {
+			return as.GetAccountSigner(ctx, address)
+		}
+
+		return nil, err
+	}
+
+	for _, pubKey := range multisigPubKey.GetPubKeys() {
+		member := sdk.AccAddress(pubKey.Address()).String()
+
+		if _, err := as.keyByAddress(ctx, member); err != nil {
+			continue
+		}
+
+		return as.GetAccountSigner(ctx, member)
+	}
+
+	return nil, ErrNoMultisigMember
+}
+
+func (as Service) getMultisigAccounts(ctx context.Context) ([]svcs.Account, error) {
+	accounts := make([]svcs.Account, 0)
+
+	profileID := auth.GetClaims(ctx).UserID
+
+	prefixDB := db.NewPrefixDB(as.db, accountMultisigKey(profileID, ""))
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return accounts, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		key, err := as.keyring.Key(keyringAccountMultisigKey(profileID, string(itr.Key())))
+		if err != nil {
+			return accounts, err
+		}
+
+		account, err := as.Keyring2Account(ctx, key)
+		if err != nil {
+			return accounts, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// multisigInfo returns co-signers of the multisig public key, nil for other keys
+func multisigInfo(pubKey cryptotypes.PubKey) *svcs.MultisigInfo {
+	multisigPubKey, ok := pubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return nil
+	}
+
+	info := &svcs.MultisigInfo{
+		Threshold: uint(multisigPubKey.Threshold),
+		Members:   make([]svcs.MultisigMember, 0, len(multisigPubKey.PubKeys)),
+	}
+
+	for _, pk := range multisigPubKey.GetPubKeys() {
+		info.Members = append(info.Members, svcs.MultisigMember{
+			Address: sdk.AccAddress(pk.Address()).String(),
+			PubKey:  fmt.Sprintf("%X", pk.Bytes()),
+		})
+	}
+
+	return info
+}
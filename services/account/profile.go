@@ -4,17 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"strings"
 
-	"github.com/cosmos/cosmos-sdk/crypto"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
-	cryptotype "github.com/cosmos/cosmos-sdk/crypto/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/obada-foundation/client-helper/auth"
 	svcs "github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/system/encoder"
-	"github.com/pkg/errors"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/tendermint/tm-db"
 )
 
@@ -105,24 +105,22 @@ func (as Service) GetProfile(ctx context.Context) (svcs.Profile, error) {
 	return profile, nil
 }
 
-// GetAccountPrivateKey returns the private key of the given account
-func (as Service) GetAccountPrivateKey(ctx context.Context, address string) (cryptotype.PrivKey, error) {
+// GetAccountSigner returns the signer of the given account
+func (as Service) GetAccountSigner(ctx context.Context, address string) (signer.Signer, error) {
 	key, err := as.keyByAddress(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 
-	armoredKey, err := as.keyring.ExportPrivKeyArmor(key.Name, "")
-	if err != nil {
-		return nil, err
+	if key.GetType() == keyring.TypeMulti {
+		return nil, ErrMultisigAccount
 	}
 
-	privKey, _, err := crypto.UnarmorDecryptPrivKey(armoredKey, "")
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decrypt private key")
+	if err := as.unlock(ctx, address); err != nil {
+		return nil, err
 	}
 
-	return privKey, nil
+	return as.signers.Signer(ctx, address)
 }
 
 func (as Service) keyByAddress(ctx context.Context, address string) (*keyring.Record, error) {
@@ -133,6 +131,15 @@ func (as Service) keyByAddress(ctx context.Context, address string) (*keyring.Re
 		return nil, err
 	}
 
+	watchOnly, err := as.isWatchAccount(profileID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if watchOnly {
+		return nil, ErrWatchOnlyAccount
+	}
+
 	keyringAccount, err := as.keyring.KeyByAddress(addr)
 	if err != nil {
 		return nil, err
@@ -168,10 +175,30 @@ func (as Service) GetProfileByAddress(address string) (string, error) {
 	return keyParts[0], nil
 }
 
+// GetPubKeyByAddress returns the public key of the account, the key is resolved by the keyring
+// that keeps account keys, it is the remote signer when keys live there
+func (as Service) GetPubKeyByAddress(address string) (cryptotypes.PubKey, error) {
+	addr, err := types.AccAddressFromBech32(address)
+	if err != nil {
+		return nil, err
+	}
+
+	keyringAccount, err := as.keyring.KeyByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyringAccount.GetPubKey()
+}
+
 // GetProfileAccount returns the account of the given user by context value
 func (as Service) GetProfileAccount(ctx context.Context, address string) (svcs.Account, error) {
 	keyringAccount, err := as.keyByAddress(ctx, address)
 	if err != nil {
+		if errors.Is(err, ErrWatchOnlyAccount) {
+			return as.getWatchAccount(ctx, address)
+		}
+
 		return svcs.Account{}, err
 	}
 
@@ -190,9 +217,21 @@ func (as Service) GetProfileAccounts(ctx context.Context) (svcs.ProfileAccounts,
 		return svcs.ProfileAccounts{}, fmt.Errorf("cannot get imported accounts: %w", err)
 	}
 
+	multisigAccounts, err := as.getMultisigAccounts(ctx)
+	if err != nil {
+		return svcs.ProfileAccounts{}, fmt.Errorf("cannot get multisig accounts: %w", err)
+	}
+
+	watchAccounts, err := as.getWatchAccounts(ctx)
+	if err != nil {
+		return svcs.ProfileAccounts{}, fmt.Errorf("cannot get watch accounts: %w", err)
+	}
+
 	return svcs.ProfileAccounts{
 		HDAccounts:       hdAccounts,
 		ImportedAccounts: importedAccounts,
+		MultisigAccounts: multisigAccounts,
+		WatchAccounts:    watchAccounts,
 	}, nil
 }
 
@@ -274,27 +313,9 @@ func (as Service) Keyring2Account(ctx context.Context, key *keyring.Record) (svc
 		return svcs.Account{}, err
 	}
 
-	var accBytes []byte
-
-	if strings.Contains(key.Name, "imported") {
-		accBytes, err = as.db.Get(accountImportedKey(profileID, addr.String()))
-		if err != nil {
-			return svcs.Account{}, err
-		}
-	} else {
-		accBytes, err = as.db.Get(accountHDKey(profileID, addr.String()))
-		if err != nil {
-			return svcs.Account{}, err
-		}
-	}
-
-	acc := Account{}
-
-	b := bytes.NewBuffer(accBytes)
-	dec := gob.NewDecoder(b)
-
-	if er := dec.Decode(&acc); er != nil {
-		return svcs.Account{}, er
+	acc, err := as.accountMetadata(profileID, addr.String(), accountTypeKey(profileID, key, addr.String()))
+	if err != nil {
+		return svcs.Account{}, err
 	}
 
 	balance, err := as.BalanceByAddress(ctx, addr.String())
@@ -312,11 +333,33 @@ func (as Service) Keyring2Account(ctx context.Context, key *keyring.Record) (svc
 		return svcs.Account{}, err
 	}
 
-	return svcs.Account{
-		Name:      acc.Name,
-		PublicKey: fmt.Sprintf("%X", pubKey.Bytes()),
-		Address:   addr.String(),
-		Balance:   balance.Balance,
-		NFTsCount: uint(len(nfts)),
-	}, nil
+	account := makeAccount(acc, addr.String(), balance, uint(len(nfts)))
+	account.PublicKey = fmt.Sprintf("%X", pubKey.Bytes())
+	account.Multisig = multisigInfo(pubKey)
+
+	return account, nil
+}
+
+// makeAccount converts the account metadata record to the client helper account
+func makeAccount(acc Account, address string, balance svcs.Balance, nftsCount uint) svcs.Account {
+	account := svcs.Account{
+		Name:              acc.Name,
+		Description:       acc.Description,
+		Tags:              acc.Tags,
+		DefaultForMinting: acc.DefaultForMinting,
+		Address:           address,
+		Balance:           balance.Balance,
+		Balances:          balance.Balances,
+		NFTsCount:         nftsCount,
+	}
+
+	if !acc.CreatedAt.IsZero() {
+		account.CreatedAt = &acc.CreatedAt
+	}
+
+	if account.Tags == nil {
+		account.Tags = make([]string, 0)
+	}
+
+	return account
 }
@@ -0,0 +1,283 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/go-bip39"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/encoder"
+)
+
+// AddRotationWallet derives accounts of the new mnemonic with the same indexes as HD accounts of the profile wallet.
+// Keys of the new accounts stay in the keyring under rotation names until CompleteWalletRotation replaces
+// the wallet, so they can receive assets meanwhile.
+func (as Service) AddRotationWallet(ctx context.Context, mnemonic string) ([]svcs.RotatedAccount, error) {
+	profileID := auth.GetUserID(ctx)
+
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+
+	if err := as.checkNoRotation(profileID); err != nil {
+		return nil, err
+	}
+
+	wallet, err := as.GetWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation, err := as.newWallet(ctx, profileID, mnemonic, wallet.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	walletBytes, err := encoder.DataEncode(rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.db.SetSync(rotationWalletKey(profileID), walletBytes); err != nil {
+		return nil, err
+	}
+
+	return as.RotationAccounts(ctx)
+}
+
+// RotationAccounts returns pairs of HD accounts of the profile wallet and accounts of the rotation wallet,
+// keys of the rotation accounts that are missing in the keyring are added
+func (as Service) RotationAccounts(ctx context.Context) ([]svcs.RotatedAccount, error) {
+	profileID := auth.GetUserID(ctx)
+
+	rotation, err := as.rotationWallet(profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]svcs.RotatedAccount, 0, rotation.AccountIndex+1)
+
+	for index := uint(0); index <= rotation.AccountIndex; index++ {
+		from, err := as.keyAddress(keyringAccountKey(profileID, index))
+		if err != nil {
+			return accounts, err
+		}
+
+		to, err := as.rotationAccount(ctx, rotation, index)
+		if err != nil {
+			return accounts, err
+		}
+
+		accounts = append(accounts, svcs.RotatedAccount{
+			MovedAssets: svcs.MovedAssets{From: from, To: to, NFTs: make([]string, 0), Coins: sdk.NewCoins()},
+			Index:       index,
+			HandOvers:   make([]string, 0),
+		})
+	}
+
+	return accounts, nil
+}
+
+// CompleteWalletRotation retires the profile wallet and makes the rotation wallet the profile one.
+// Metadata of old accounts is carried over to new accounts with the same index. Every step can be repeated,
+// so the rotation that was interrupted is completed by calling it again.
+func (as Service) CompleteWalletRotation(ctx context.Context) error {
+	profileID := auth.GetUserID(ctx)
+
+	rotation, err := as.rotationWallet(profileID)
+	if err != nil {
+		return err
+	}
+
+	hasWallet, err := as.db.Has(walletKey(profileID))
+	if err != nil {
+		return err
+	}
+
+	if hasWallet {
+		if err := as.carryOverMetadata(profileID, rotation); err != nil {
+			return fmt.Errorf("cannot carry over accounts metadata: %w", err)
+		}
+
+		if err := as.deleteWallet(ctx); err != nil {
+			return fmt.Errorf("cannot retire the wallet: %w", err)
+		}
+	}
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	for index := uint(0); index <= rotation.AccountIndex; index++ {
+		name := keyringAccountKey(profileID, index)
+
+		if _, err := as.keyring.Key(keyringAccountRotationKey(profileID, index)); err == nil {
+			if err := as.keyring.Rename(keyringAccountRotationKey(profileID, index), name); err != nil {
+				return err
+			}
+		}
+
+		address, err := as.keyAddress(name)
+		if err != nil {
+			return err
+		}
+
+		accountBytes, err := encoder.DataEncode(Account{})
+		if err != nil {
+			return err
+		}
+
+		if err := batch.Set(accountHDKey(profileID, address), accountBytes); err != nil {
+			return err
+		}
+	}
+
+	walletBytes, err := encoder.DataEncode(rotation)
+	if err != nil {
+		return err
+	}
+
+	if err := batch.Set(walletKey(profileID), walletBytes); err != nil {
+		return err
+	}
+
+	if err := batch.Delete(rotationWalletKey(profileID)); err != nil {
+		return err
+	}
+
+	return batch.WriteSync()
+}
+
+// rotationAccount adds the key of the rotation wallet account to the keyring, the registry learns its public key
+// from the account created event before NFTs are handed over to it
+func (as Service) rotationAccount(ctx context.Context, rotation svcs.Wallet, index uint) (string, error) {
+	name := keyringAccountRotationKey(auth.GetUserID(ctx), index)
+
+	address, err := as.keyAddress(name)
+	if err == nil {
+		return address, nil
+	}
+
+	if !errors.Is(err, sdkerrors.ErrKeyNotFound) {
+		return "", err
+	}
+
+	key, err := as.deriveKey(ctx, rotation, name, index)
+	if err != nil {
+		return "", fmt.Errorf("cannot create keyring account: %w", err)
+	}
+
+	addr, err := key.GetAddress()
+	if err != nil {
+		return "", err
+	}
+
+	if err := as.eventBus.Emit(ctx, events.AccountCreated, addr.String()); err != nil {
+		return "", err
+	}
+
+	return addr.String(), nil
+}
+
+// carryOverMetadata copies metadata of the wallet accounts to accounts of the rotation wallet, the minting account
+// pointer is moved as well, so the wallet deletion doesn't reset it
+func (as Service) carryOverMetadata(profileID string, rotation svcs.Wallet) error {
+	minting, err := as.db.Get(mintingAccountKey(profileID))
+	if err != nil {
+		return err
+	}
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	for index := uint(0); index <= rotation.AccountIndex; index++ {
+		from, err := as.keyAddress(keyringAccountKey(profileID, index))
+		if err != nil {
+			if errors.Is(err, sdkerrors.ErrKeyNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		to, err := as.keyAddress(keyringAccountRotationKey(profileID, index))
+		if err != nil {
+			return err
+		}
+
+		acc, err := as.accountMetadata(profileID, from, accountHDKey(profileID, from))
+		if err != nil {
+			return err
+		}
+
+		if string(minting) == from {
+			if err := batch.Set(mintingAccountKey(profileID), []byte(to)); err != nil {
+				return err
+			}
+		}
+
+		accountBytes, err := encoder.DataEncode(acc)
+		if err != nil {
+			return err
+		}
+
+		if err := batch.Set(accountMetadataKey(profileID, to), accountBytes); err != nil {
+			return err
+		}
+	}
+
+	return batch.WriteSync()
+}
+
+func (as Service) rotationWallet(profileID string) (svcs.Wallet, error) {
+	var wallet svcs.Wallet
+
+	walletBytes, err := as.db.Get(rotationWalletKey(profileID))
+	if err != nil {
+		return wallet, err
+	}
+
+	if walletBytes == nil {
+		return wallet, ErrWalletRotationNotExists
+	}
+
+	if err := gob.NewDecoder(bytes.NewBuffer(walletBytes)).Decode(&wallet); err != nil {
+		return wallet, err
+	}
+
+	return wallet, nil
+}
+
+// checkNoRotation fails when the wallet rotation is in progress, the wallet cannot get new accounts meanwhile
+func (as Service) checkNoRotation(profileID string) error {
+	ok, err := as.db.Has(rotationWalletKey(profileID))
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		return ErrWalletRotationInProgress
+	}
+
+	return nil
+}
+
+func (as Service) keyAddress(name string) (string, error) {
+	key, err := as.keyring.Key(name)
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := key.GetAddress()
+	if err != nil {
+		return "", err
+	}
+
+	return addr.String(), nil
+}
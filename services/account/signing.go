@@ -0,0 +1,222 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"sync"
+	"time"
+
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/encoder"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MaxSigningAttempts invalid signing passphrases in a row before the profile signing is locked
+	MaxSigningAttempts = 5
+
+	// SigningLockout how long the profile signing stays locked
+	SigningLockout = 15 * time.Minute
+)
+
+// signingMu serializes the signing passphrase checks, so concurrent guesses cannot bypass the attempts counter
+var signingMu sync.Mutex
+
+// signingAttempts invalid signing passphrase attempts of the profile
+type signingAttempts struct {
+	Failures    int
+	LockedUntil time.Time
+}
+
+type signingCtxKey int
+
+const signingKey signingCtxKey = 1
+
+// WithSigningPassphrase sets the signing passphrase of the operation, the signing passphrase of the account
+// is verified against the given one before the key is unlocked
+func WithSigningPassphrase(ctx context.Context, passphrase string) context.Context {
+	return context.WithValue(ctx, signingKey, passphrase)
+}
+
+func signingPassphrase(ctx context.Context) (string, bool) {
+	passphrase, ok := ctx.Value(signingKey).(string)
+	return passphrase, ok
+}
+
+// SetSigningPassphrase sets the signing passphrase of the account, or of the whole profile when the address is empty.
+// The current signing passphrase has to be provided when it is already set.
+func (as Service) SetSigningPassphrase(ctx context.Context, address string, sp svcs.SigningPassphrase) error {
+	if err := as.validator.Check(sp); err != nil {
+		return err
+	}
+
+	key, err := as.signingPassphraseKey(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	current, _ := signingPassphrase(ctx)
+
+	if err := as.verifySigningPassphrase(ctx, address, current); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(sp.Passphrase), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return as.db.SetSync(key, hash)
+}
+
+// RemoveSigningPassphrase removes the signing passphrase of the account, or of the whole profile when the address is empty
+func (as Service) RemoveSigningPassphrase(ctx context.Context, address string) error {
+	key, err := as.signingPassphraseKey(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	hash, err := as.db.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if hash == nil {
+		return ErrSigningPassphraseNotExists
+	}
+
+	current, _ := signingPassphrase(ctx)
+
+	if err := as.verifySigningPassphrase(ctx, address, current); err != nil {
+		return err
+	}
+
+	return as.db.DeleteSync(key)
+}
+
+// Mnemonic returns the mnemonic of the profile wallet, the profile signing passphrase is required when it is set
+func (as Service) Mnemonic(ctx context.Context) (string, error) {
+	if as.wallets != nil {
+		return "", ErrRemoteKeys
+	}
+
+	if err := as.unlock(ctx, ""); err != nil {
+		return "", err
+	}
+
+	wallet, err := as.GetWallet(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return wallet.Mnemonic, nil
+}
+
+func (as Service) signingPassphraseKey(ctx context.Context, address string) ([]byte, error) {
+	profileID := auth.GetClaims(ctx).UserID
+
+	if address == "" {
+		return signingPassphraseKey(profileID), nil
+	}
+
+	if _, err := as.keyByAddress(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return accountSigningPassphraseKey(profileID, address), nil
+}
+
+// unlock verifies the signing passphrase of the context, the key protected by the signing passphrase
+// is never unlocked without it
+func (as Service) unlock(ctx context.Context, address string) error {
+	passphrase, _ := signingPassphrase(ctx)
+
+	return as.verifySigningPassphrase(ctx, address, passphrase)
+}
+
+// verifySigningPassphrase checks the passphrase against the account signing passphrase, or the profile one
+// when the account has no own passphrase. Nothing is required when neither is set.
+func (as Service) verifySigningPassphrase(ctx context.Context, address, passphrase string) error {
+	profileID := auth.GetClaims(ctx).UserID
+
+	hash, err := as.signingPassphraseHash(profileID, address)
+	if err != nil {
+		return err
+	}
+
+	if hash == nil {
+		return nil
+	}
+
+	signingMu.Lock()
+	defer signingMu.Unlock()
+
+	attempts, err := as.signingAttempts(profileID)
+	if err != nil {
+		return err
+	}
+
+	if time.Now().Before(attempts.LockedUntil) {
+		return ErrSigningLocked
+	}
+
+	if passphrase == "" {
+		return ErrSigningPassphraseRequired
+	}
+
+	if bcrypt.CompareHashAndPassword(hash, []byte(passphrase)) == nil {
+		if attempts.Failures == 0 {
+			return nil
+		}
+
+		return as.db.DeleteSync(signingAttemptsKey(profileID))
+	}
+
+	attempts.Failures++
+
+	if attempts.Failures >= MaxSigningAttempts {
+		attempts = signingAttempts{LockedUntil: time.Now().Add(SigningLockout)}
+	}
+
+	b, err := encoder.DataEncode(attempts)
+	if err != nil {
+		return err
+	}
+
+	if err := as.db.SetSync(signingAttemptsKey(profileID), b); err != nil {
+		return err
+	}
+
+	return ErrInvalidSigningPassphrase
+}
+
+func (as Service) signingPassphraseHash(profileID, address string) ([]byte, error) {
+	if address != "" {
+		hash, err := as.db.Get(accountSigningPassphraseKey(profileID, address))
+		if err != nil || hash != nil {
+			return hash, err
+		}
+	}
+
+	return as.db.Get(signingPassphraseKey(profileID))
+}
+
+func (as Service) signingAttempts(profileID string) (signingAttempts, error) {
+	var attempts signingAttempts
+
+	attemptsBytes, err := as.db.Get(signingAttemptsKey(profileID))
+	if err != nil || attemptsBytes == nil {
+		return attempts, err
+	}
+
+	b := bytes.NewBuffer(attemptsBytes)
+	dec := gob.NewDecoder(b)
+
+	if er := dec.Decode(&attempts); er != nil {
+		return attempts, er
+	}
+
+	return attempts, nil
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/go-bip39"
 	"github.com/obada-foundation/client-helper/auth"
@@ -15,12 +17,17 @@ import (
 	"github.com/obada-foundation/client-helper/system/encoder"
 )
 
-// ImportWallet imports HD wallet and fetch existing accounts from the blockchain
+// ImportWallet imports HD wallet and discovers existing accounts on the blockchain
 func (as Service) ImportWallet(ctx context.Context, mnemonic string, force bool) error {
 	if !bip39.IsMnemonicValid(mnemonic) {
 		return ErrInvalidMnemonic
 	}
 
+	// the wallet of the rotation must not be deleted by the failed import
+	if err := as.checkNoRotation(auth.GetUserID(ctx)); err != nil {
+		return err
+	}
+
 	if _, err := as.NewWallet(ctx, mnemonic, force); err != nil {
 		if er := as.deleteWallet(ctx); er != nil {
 			return fmt.Errorf("%s : %w", er.Error(), err)
@@ -29,23 +36,15 @@ func (as Service) ImportWallet(ctx context.Context, mnemonic string, force bool)
 		return err
 	}
 
-	// Create accounts until not receive ErrAccountHasZeroTx
-	for {
-		if _, err := as.NewAccount(ctx, Account{}); err != nil {
-			if !errors.Is(err, ErrAccountHasZeroTx) {
-				if er := as.deleteWallet(ctx); er != nil {
-					return fmt.Errorf("%s : %w", er.Error(), err)
-				}
-
-				return err
-			}
-
-			break
+	if _, err := as.Rescan(ctx); err != nil {
+		if er := as.deleteWallet(ctx); er != nil {
+			return fmt.Errorf("%s : %w", er.Error(), err)
 		}
+
+		return err
 	}
 
 	return nil
-
 }
 
 // NewWallet created new HD wallet attached to the user account
@@ -56,6 +55,10 @@ func (as Service) NewWallet(ctx context.Context, mnemonic string, force bool) (*
 		return nil, ErrInvalidMnemonic
 	}
 
+	if err := as.checkNoRotation(profileID); err != nil {
+		return nil, err
+	}
+
 	wk := walletKey(profileID)
 
 	hasWallet, err := as.db.Has(wk)
@@ -73,9 +76,9 @@ func (as Service) NewWallet(ctx context.Context, mnemonic string, force bool) (*
 		}
 	}
 
-	wallet := svcs.Wallet{
-		Mnemonic:     mnemonic,
-		AccountIndex: 0,
+	wallet, err := as.newWallet(ctx, profileID, mnemonic, 0)
+	if err != nil {
+		return nil, err
 	}
 
 	walletBytes, err := encoder.DataEncode(wallet)
@@ -162,6 +165,10 @@ func (as Service) deleteWallet(ctx context.Context) error {
 				return err
 			}
 
+			if err := as.deleteAccountMetadata(batch, profileID, addr.String()); err != nil {
+				return err
+			}
+
 			if err := as.eventBus.Emit(ctx, events.AccountDeleted, addr.String()); err != nil {
 				return err
 			}
@@ -179,7 +186,15 @@ func (as Service) deleteWallet(ctx context.Context) error {
 		return er
 	}
 
-	return batch.WriteSync()
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+
+	if as.wallets != nil {
+		return as.wallets.DeleteMnemonic(ctx, wallet.Signer)
+	}
+
+	return nil
 }
 
 // GetWalletAccountIndex returns the wallet account index
@@ -191,3 +206,36 @@ func (as Service) GetWalletAccountIndex(ctx context.Context) (uint, error) {
 
 	return wallet.AccountIndex, nil
 }
+
+// newWallet returns the wallet of the mnemonic, the mnemonic is handed over to the remote signer when keys are kept there
+func (as Service) newWallet(ctx context.Context, profileID, mnemonic string, index uint) (svcs.Wallet, error) {
+	if as.wallets == nil {
+		return svcs.Wallet{Mnemonic: mnemonic, AccountIndex: index}, nil
+	}
+
+	name := signerWalletName(profileID, mnemonic)
+
+	if err := as.wallets.SaveMnemonic(ctx, name, mnemonic); err != nil {
+		return svcs.Wallet{}, fmt.Errorf("cannot save wallet on the signer: %w", err)
+	}
+
+	return svcs.Wallet{Signer: name, AccountIndex: index}, nil
+}
+
+// deriveKey adds the key of the wallet account with the index to the keyring
+func (as Service) deriveKey(ctx context.Context, wallet svcs.Wallet, uid string, index uint) (*keyring.Record, error) {
+	if as.wallets != nil {
+		return as.wallets.DeriveKey(ctx, wallet.Signer, uid, hdPath(index))
+	}
+
+	return as.keyring.NewAccount(uid, wallet.Mnemonic, "", hdPath(index), hd.Secp256k1)
+}
+
+// walletAddress derives the address of the wallet account with the index without storing the key
+func (as Service) walletAddress(ctx context.Context, wallet svcs.Wallet, index uint) (string, error) {
+	if as.wallets != nil {
+		return as.wallets.DeriveAddress(ctx, wallet.Signer, hdPath(index))
+	}
+
+	return deriveAddress(wallet.Mnemonic, index)
+}
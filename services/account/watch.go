@@ -0,0 +1,160 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/encoder"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/tendermint/tm-db"
+)
+
+// AddWatchAccount adds the watch-only account, the profile tracks its balance and NFTs without controlling its key
+func (as Service) AddWatchAccount(ctx context.Context, nw svcs.NewWatchAccount) error {
+	profileID := auth.GetUserID(ctx)
+
+	if err := as.validator.Check(nw); err != nil {
+		return err
+	}
+
+	addr, err := sdk.AccAddressFromBech32(nw.Address)
+	if err != nil {
+		return validate.FieldErrors{
+			validate.FieldError{
+				Field: "address",
+				Error: fmt.Sprintf("%q is not OBADA address", nw.Address),
+			},
+		}
+	}
+
+	address := addr.String()
+
+	watchOnly, err := as.isWatchAccount(profileID, address)
+	if err != nil {
+		return err
+	}
+
+	if watchOnly {
+		return ErrAccountExists
+	}
+
+	key, err := as.keyring.KeyByAddress(addr)
+	if err != nil && !errors.Is(err, sdkerrors.ErrKeyNotFound) {
+		return err
+	}
+
+	if key != nil && strings.Contains(key.Name, keyringAccountPrefix(profileID)) {
+		return ErrAccountExists
+	}
+
+	acc := Account{Name: nw.Name}
+
+	accountBytes, err := encoder.DataEncode(acc)
+	if err != nil {
+		return err
+	}
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(accountWatchKey(profileID, address), accountBytes); err != nil {
+		return err
+	}
+
+	if err := setAccountMetadata(batch, profileID, address, acc); err != nil {
+		return err
+	}
+
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+
+	audit.SetTarget(ctx, address)
+
+	return as.eventBus.Emit(ctx, events.AccountCreated, address)
+}
+
+// IsWatchAccount returns true when the address is the watch-only account of the profile
+func (as Service) IsWatchAccount(ctx context.Context, address string) (bool, error) {
+	return as.isWatchAccount(auth.GetUserID(ctx), address)
+}
+
+func (as Service) isWatchAccount(profileID, address string) (bool, error) {
+	return as.db.Has(accountWatchKey(profileID, address))
+}
+
+func (as Service) getWatchAccount(ctx context.Context, address string) (svcs.Account, error) {
+	profileID := auth.GetUserID(ctx)
+
+	acc, err := as.accountMetadata(profileID, address, accountWatchKey(profileID, address))
+	if err != nil {
+		return svcs.Account{}, err
+	}
+
+	balance, err := as.BalanceByAddress(ctx, address)
+	if err != nil {
+		return svcs.Account{}, err
+	}
+
+	nfts, err := as.nodeClient.GetNFTByAddress(ctx, address)
+	if err != nil {
+		return svcs.Account{}, err
+	}
+
+	account := makeAccount(acc, address, balance, uint(len(nfts)))
+	account.WatchOnly = true
+
+	return account, nil
+}
+
+func (as Service) getWatchAccounts(ctx context.Context) ([]svcs.Account, error) {
+	accounts := make([]svcs.Account, 0)
+
+	prefixDB := db.NewPrefixDB(as.db, accountWatchKey(auth.GetUserID(ctx), ""))
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return accounts, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		account, err := as.getWatchAccount(ctx, string(itr.Key()))
+		if err != nil {
+			return accounts, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// deleteWatchAccount removes the watch-only account, its devices are removed by the account deleted event
+func (as Service) deleteWatchAccount(ctx context.Context, address string) error {
+	profileID := auth.GetUserID(ctx)
+
+	batch := as.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Delete(accountWatchKey(profileID, address)); err != nil {
+		return err
+	}
+
+	if err := as.deleteAccountMetadata(batch, profileID, address); err != nil {
+		return err
+	}
+
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+
+	return as.eventBus.Emit(ctx, events.AccountDeleted, address)
+}
@@ -0,0 +1,189 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/web"
+	db "github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+// Audited actions
+const (
+	ActionWalletCreate       = "wallet.create"
+	ActionWalletImport       = "wallet.import"
+	ActionWalletMnemonicShow = "wallet.mnemonic_reveal"
+	ActionWalletRescan       = "wallet.rescan"
+	ActionWalletRotate       = "wallet.rotate"
+	ActionWalletRotateResume = "wallet.rotate_resume"
+	ActionAccountCreate      = "account.create"
+	ActionAccountImport      = "account.import"
+	ActionAccountExport      = "account.export"
+	ActionAccountDelete      = "account.delete"
+	ActionAccountWatch       = "account.watch"
+	ActionObitSave           = "obit.save"
+	ActionNFTMint            = "nft.mint"
+	ActionNFTMetadataUpdate  = "nft.metadata_update"
+	ActionNFTTransfer        = "nft.transfer"
+	ActionCoinsSend          = "coins.send"
+
+	ActionSigningPassphraseSet    = "signing_passphrase.set"
+	ActionSigningPassphraseRemove = "signing_passphrase.remove"
+
+	ActionMultisigCreate      = "account.multisig_create"
+	ActionMultisigTxSign      = "multisig_tx.sign"
+	ActionMultisigTxBroadcast = "multisig_tx.broadcast"
+	ActionMultisigTxCancel    = "multisig_tx.cancel"
+	ActionMultisigTxHandOver  = "multisig_tx.hand_over"
+
+	ActionAuthzGrant      = "authz.grant"
+	ActionAuthzRevoke     = "authz.revoke"
+	ActionAllowanceGrant  = "allowance.grant"
+	ActionAllowanceRevoke = "allowance.revoke"
+	ActionTxBroadcast     = "tx.broadcast"
+)
+
+const (
+	// DefaultLimit how many entries are returned when the limit is not set
+	DefaultLimit = 100
+
+	// MaxLimit maximum number of entries returned by the query
+	MaxLimit = 1000
+)
+
+// Config is the audit service configuration
+type Config struct {
+	DB     db.DB
+	Logger *zap.SugaredLogger
+}
+
+// Service keeps the append-only audit log of the profile operations
+type Service struct {
+	db     db.DB
+	logger *zap.SugaredLogger
+}
+
+// NewService creates a new audit service
+func NewService(cfg Config) *Service {
+	return &Service{
+		db:     cfg.DB,
+		logger: cfg.Logger,
+	}
+}
+
+// Record appends the trail of the context to the log, opErr is the result of the operation.
+// The trail is recorded once, so the handler may record it before the response is sent.
+func (s *Service) Record(ctx context.Context, opErr error) error {
+	t := getTrail(ctx)
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.recorded {
+		return nil
+	}
+
+	claims := auth.GetClaims(ctx)
+
+	entry := svcs.AuditEntry{
+		ID:        uuid.New().String(),
+		Subject:   claims.Subject,
+		ProfileID: claims.UserID,
+		Action:    t.action,
+		Target:    t.target,
+		TraceID:   web.GetTraceID(ctx),
+		Result:    svcs.AuditResultSuccess,
+		TxHash:    t.txHash,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if opErr != nil {
+		entry.Result = svcs.AuditResultFailure
+		entry.Error = opErr.Error()
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.SetSync(makeEntryKey(entry.ProfileID, entry.CreatedAt, entry.ID), b); err != nil {
+		s.logger.Errorw("cannot record audit entry", "trace_id", entry.TraceID, "action", entry.Action, "error", err)
+		return fmt.Errorf("cannot record audit entry: %w", err)
+	}
+
+	t.recorded = true
+
+	return nil
+}
+
+// Search returns audit entries of the profile that match the filter, the newest entries come first
+func (s *Service) Search(ctx context.Context, f svcs.AuditFilter) ([]svcs.AuditEntry, error) {
+	entries := make([]svcs.AuditEntry, 0)
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	prefixDB := db.NewPrefixDB(s.db, makeProfileKey(auth.GetUserID(ctx)))
+
+	itr, err := prefixDB.ReverseIterator(nil, nil)
+	if err != nil {
+		return entries, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid() && len(entries) < limit; itr.Next() {
+		var entry svcs.AuditEntry
+
+		if err := json.Unmarshal(itr.Value(), &entry); err != nil {
+			return entries, err
+		}
+
+		if !f.From.IsZero() && entry.CreatedAt.Before(f.From) {
+			break
+		}
+
+		if !matches(entry, f) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func matches(entry svcs.AuditEntry, f svcs.AuditFilter) bool {
+	if f.Action != "" && entry.Action != f.Action {
+		return false
+	}
+
+	if f.Target != "" && entry.Target != f.Target {
+		return false
+	}
+
+	if f.Result != "" && entry.Result != f.Result {
+		return false
+	}
+
+	if !f.To.IsZero() && entry.CreatedAt.After(f.To) {
+		return false
+	}
+
+	return true
+}
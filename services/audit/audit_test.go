@@ -0,0 +1,110 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/testutil"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+)
+
+const testAddress = "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg"
+
+func TestService(t *testing.T) {
+	logger, deleteLogFile := testutil.MakeLoger()
+	defer deleteLogFile()
+
+	svc := audit.NewService(audit.Config{
+		DB:     db.NewMemDB(),
+		Logger: logger,
+	})
+
+	claims := auth.Claims{UserID: "1"}
+	claims.Subject = "user@obada.io"
+
+	ctx := auth.SetClaims(context.Background(), claims)
+
+	t.Log("Ignores contexts without the trail")
+	{
+		require.NoError(t, svc.Record(ctx, nil))
+
+		entries, err := svc.Search(ctx, svcs.AuditFilter{})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	}
+
+	t.Log("Records the trail once")
+	{
+		exportCtx := audit.NewContext(ctx, audit.ActionAccountExport, "")
+		audit.SetTarget(exportCtx, testAddress)
+
+		require.NoError(t, svc.Record(exportCtx, nil))
+		require.NoError(t, svc.Record(exportCtx, errors.New("response failed")))
+
+		entries, err := svc.Search(ctx, svcs.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "user@obada.io", entries[0].Subject)
+		require.Equal(t, "1", entries[0].ProfileID)
+		require.Equal(t, audit.ActionAccountExport, entries[0].Action)
+		require.Equal(t, testAddress, entries[0].Target)
+		require.Equal(t, svcs.AuditResultSuccess, entries[0].Result)
+	}
+
+	t.Log("Records failures and tx hashes")
+	{
+		sendCtx := audit.NewContext(ctx, audit.ActionCoinsSend, testAddress)
+		audit.SetTxHash(sendCtx, "6A7B0F")
+
+		require.NoError(t, svc.Record(sendCtx, nil))
+
+		mintCtx := audit.NewContext(ctx, audit.ActionNFTMint, "did:obada:1")
+		require.NoError(t, svc.Record(mintCtx, errors.New("insufficient funds")))
+
+		entries, err := svc.Search(ctx, svcs.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		// the newest entry comes first
+		require.Equal(t, audit.ActionNFTMint, entries[0].Action)
+		require.Equal(t, svcs.AuditResultFailure, entries[0].Result)
+		require.Equal(t, "insufficient funds", entries[0].Error)
+		require.Equal(t, "6A7B0F", entries[1].TxHash)
+	}
+
+	t.Log("Filters entries")
+	{
+		entries, err := svc.Search(ctx, svcs.AuditFilter{Target: testAddress})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entries, err = svc.Search(ctx, svcs.AuditFilter{Result: svcs.AuditResultFailure})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		entries, err = svc.Search(ctx, svcs.AuditFilter{Action: audit.ActionAccountExport, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		entries, err = svc.Search(ctx, svcs.AuditFilter{From: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		entries, err = svc.Search(ctx, svcs.AuditFilter{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+	}
+
+	t.Log("Returns entries of the profile only")
+	{
+		entries, err := svc.Search(auth.SetClaims(ctx, auth.Claims{UserID: "2"}), svcs.AuditFilter{})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	}
+}
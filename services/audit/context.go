@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+type ctxKey int
+
+const key ctxKey = 1
+
+// Trail collects details of the audited operation while the request is handled
+type Trail struct {
+	mu       sync.Mutex
+	action   string
+	target   string
+	txHash   string
+	recorded bool
+}
+
+// NewContext starts the trail of the action
+func NewContext(ctx context.Context, action, target string) context.Context {
+	return context.WithValue(ctx, key, &Trail{action: action, target: target})
+}
+
+func getTrail(ctx context.Context) *Trail {
+	t, ok := ctx.Value(key).(*Trail)
+	if !ok {
+		return nil
+	}
+
+	return t
+}
+
+// SetTarget sets the target of the audited operation when it is not known from the route
+func SetTarget(ctx context.Context, target string) {
+	if t := getTrail(ctx); t != nil {
+		t.mu.Lock()
+		t.target = target
+		t.mu.Unlock()
+	}
+}
+
+// SetTxHash sets the hash of the transaction broadcasted by the audited operation
+func SetTxHash(ctx context.Context, hash string) {
+	if t := getTrail(ctx); t != nil {
+		t.mu.Lock()
+		t.txHash = hash
+		t.mu.Unlock()
+	}
+}
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+)
+
+const prefix = "audit:"
+
+func makeProfileKey(profileID string) []byte {
+	return []byte(fmt.Sprintf(prefix+"%s:", profileID))
+}
+
+// makeEntryKey keys of the profile are sorted by the creation time of the entry
+func makeEntryKey(profileID string, createdAt time.Time, id string) []byte {
+	return []byte(fmt.Sprintf(prefix+"%s:%020d:%s", profileID, createdAt.UnixNano(), id))
+}
@@ -0,0 +1,204 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
+	"github.com/obada-foundation/fullcore/x/obit/types"
+)
+
+// authzMsgTypes messages which execution can be delegated to another account
+var authzMsgTypes = map[string]string{
+	TxMsgMint:          sdk.MsgTypeURL(&types.MsgMintNFT{}),
+	TxMsgUpdateURIHash: sdk.MsgTypeURL(&types.MsgUpdateUriHash{}),
+}
+
+func authzMsgTypeURL(msgType string) (string, error) {
+	typeURL, ok := authzMsgTypes[msgType]
+	if !ok {
+		return "", ErrUnknownTxMsgType
+	}
+
+	return typeURL, nil
+}
+
+func authzMsgType(typeURL string) string {
+	for msgType, url := range authzMsgTypes {
+		if url == typeURL {
+			return msgType
+		}
+	}
+
+	return ""
+}
+
+// GrantAuthz allows grantee to execute messages of given type on behalf of the granter.
+func (bs Service) GrantAuthz(ctx context.Context, grantee, msgType string, expiration *time.Time, s signer.Signer) error {
+	granterAddress := signer.Address(s)
+
+	granteeAddress, err := sdk.AccAddressFromBech32(grantee)
+	if err != nil {
+		return err
+	}
+
+	typeURL, err := authzMsgTypeURL(msgType)
+	if err != nil {
+		return err
+	}
+
+	msg, err := authz.NewMsgGrant(granterAddress, granteeAddress, authz.NewGenericAuthorization(typeURL), expiration)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.sendAuthzTx(ctx, msg, s); err != nil {
+		return err
+	}
+
+	bs.logger.Info("Authz grant was created", granterAddress.String(), grantee, msgType)
+
+	return nil
+}
+
+// RevokeAuthz revokes grantee rights to execute messages of given type on behalf of the granter.
+func (bs Service) RevokeAuthz(ctx context.Context, grantee, msgType string, s signer.Signer) error {
+	granterAddress := signer.Address(s)
+
+	granteeAddress, err := sdk.AccAddressFromBech32(grantee)
+	if err != nil {
+		return err
+	}
+
+	typeURL, err := authzMsgTypeURL(msgType)
+	if err != nil {
+		return err
+	}
+
+	msg := authz.NewMsgRevoke(granterAddress, granteeAddress, typeURL)
+
+	if err := bs.sendAuthzTx(ctx, &msg, s); err != nil {
+		return err
+	}
+
+	bs.logger.Info("Authz grant was revoked", granterAddress.String(), grantee, msgType)
+
+	return nil
+}
+
+func (bs Service) sendAuthzTx(ctx context.Context, msg sdk.Msg, s signer.Signer) error {
+	accAddress := signer.Address(s).String()
+
+	ok, err := bs.nodeClient.HasAccount(ctx, accAddress)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrInsufficientFunds
+	}
+
+	txConf := obadanode.TxCustomConfig{
+		Msg:       msg,
+		GasLimit:  uint64(MinGasLimit),
+		FeeAmount: sdkmath.NewInt(MinGasLimit),
+		Signer:    s,
+	}
+
+	resp, err := bs.nodeClient.SendTx(ctx, txConf)
+	if err != nil {
+		if errors.Is(err, obadanode.ErrInsufficientFunds) {
+			return ErrInsufficientFunds
+		}
+
+		return err
+	}
+
+	audit.SetTxHash(ctx, resp.Hash.String())
+
+	return nil
+}
+
+// AuthzGrants returns grants given and received by the account.
+func (bs Service) AuthzGrants(ctx context.Context, address string) (services.AuthzGrants, error) {
+	var grants services.AuthzGrants
+
+	granted, err := bs.nodeClient.GranterGrants(ctx, address)
+	if err != nil {
+		return grants, err
+	}
+
+	received, err := bs.nodeClient.GranteeGrants(ctx, address)
+	if err != nil {
+		return grants, err
+	}
+
+	grants.Granted = toAuthzGrants(granted)
+	grants.Received = toAuthzGrants(received)
+
+	return grants, nil
+}
+
+func toAuthzGrants(grants []*authz.GrantAuthorization) []services.AuthzGrant {
+	result := make([]services.AuthzGrant, 0, len(grants))
+
+	for _, g := range grants {
+		a, ok := g.Authorization.GetCachedValue().(authz.Authorization)
+		if !ok {
+			continue
+		}
+
+		result = append(result, services.AuthzGrant{
+			Granter:    g.Granter,
+			Grantee:    g.Grantee,
+			MsgType:    authzMsgType(a.MsgTypeURL()),
+			MsgTypeURL: a.MsgTypeURL(),
+			Expiration: g.Expiration,
+		})
+	}
+
+	return result
+}
+
+// execMsg wraps the message into MsgExec when the owner delegated the execution to the signer,
+// the message is returned as is when the owner signs it.
+func (bs Service) execMsg(ctx context.Context, signer, owner string, msg sdk.Msg) (sdk.Msg, error) {
+	if owner == "" || owner == signer {
+		return msg, nil
+	}
+
+	granteeAddress, err := sdk.AccAddressFromBech32(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := bs.nodeClient.GranteeGrants(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	typeURL := sdk.MsgTypeURL(msg)
+
+	for _, g := range toAuthzGrants(grants) {
+		if g.Granter != owner || g.MsgTypeURL != typeURL {
+			continue
+		}
+
+		if g.Expiration != nil && g.Expiration.Before(time.Now()) {
+			continue
+		}
+
+		exec := authz.NewMsgExec(granteeAddress, []sdk.Msg{msg})
+
+		return &exec, nil
+	}
+
+	return nil, ErrAuthzNotFound
+}
@@ -0,0 +1,113 @@
+package blockchain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/system/signer"
+	"github.com/obada-foundation/client-helper/testutil"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func makeGrant(t *testing.T, granter, grantee string, msg sdk.Msg, expiration *time.Time) *authz.GrantAuthorization {
+	a, err := codectypes.NewAnyWithValue(authz.NewGenericAuthorization(sdk.MsgTypeURL(msg)))
+	require.NoError(t, err)
+
+	return &authz.GrantAuthorization{
+		Granter:       granter,
+		Grantee:       grantee,
+		Authorization: a,
+		Expiration:    expiration,
+	}
+}
+
+func TestAuthz(t *testing.T) {
+	logger, lgDefer := testutil.MakeLoger()
+	defer lgDefer()
+
+	nodeClient := mocks.NewClient(t)
+	service := blockchain.NewService(nodeClient, logger, "")
+	ctx := context.Background()
+
+	signerKey := signer.NewPrivKeySigner(secp256k1.GenPrivKey())
+	signerAddress := sdk.AccAddress(signerKey.PubKey().Address()).String()
+	ownerAddress := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+
+	device := services.Device{DID: "did:obada:12345", Address: ownerAddress}
+
+	nodeClient.On("HasAccount", mock.Anything, signerAddress).Return(true, nil)
+
+	t.Log("Test mint on behalf of the owner without grant")
+	{
+		expired := time.Now().Add(-time.Hour)
+
+		nodeClient.On("GranteeGrants", mock.Anything, signerAddress).Return([]*authz.GrantAuthorization{
+			makeGrant(t, ownerAddress, signerAddress, &obadatypes.MsgUpdateUriHash{}, nil),
+			makeGrant(t, ownerAddress, signerAddress, &obadatypes.MsgMintNFT{}, &expired),
+		}, nil).Once()
+
+		err := service.MintNFT(ctx, device, signerKey)
+		require.ErrorIs(t, err, blockchain.ErrAuthzNotFound)
+	}
+
+	t.Log("Test mint on behalf of the owner is wrapped into MsgExec")
+	{
+		nodeClient.On("GranteeGrants", mock.Anything, signerAddress).Return([]*authz.GrantAuthorization{
+			makeGrant(t, ownerAddress, signerAddress, &obadatypes.MsgMintNFT{}, nil),
+		}, nil).Once()
+
+		nodeClient.On("SendTx", mock.Anything, mock.MatchedBy(func(cnf obadanode.TxCustomConfig) bool {
+			exec, ok := cnf.Msg.(*authz.MsgExec)
+			if !ok || exec.Grantee != signerAddress {
+				return false
+			}
+
+			msgs, err := exec.GetMessages()
+			if err != nil || len(msgs) != 1 {
+				return false
+			}
+
+			mint, ok := msgs[0].(*obadatypes.MsgMintNFT)
+
+			return ok && mint.Creator == ownerAddress
+		})).Return(&ctypes.ResultBroadcastTx{}, nil).Once()
+
+		err := service.MintNFT(ctx, device, signerKey)
+		require.NoError(t, err)
+	}
+
+	t.Log("Test grants are listed")
+	{
+		nodeClient.On("GranterGrants", mock.Anything, signerAddress).Return([]*authz.GrantAuthorization{}, nil).Once()
+		nodeClient.On("GranteeGrants", mock.Anything, signerAddress).Return([]*authz.GrantAuthorization{
+			makeGrant(t, ownerAddress, signerAddress, &obadatypes.MsgMintNFT{}, nil),
+		}, nil).Once()
+
+		grants, err := service.AuthzGrants(ctx, signerAddress)
+		require.NoError(t, err)
+
+		assert.Empty(t, grants.Granted)
+		require.Len(t, grants.Received, 1)
+		assert.Equal(t, blockchain.TxMsgMint, grants.Received[0].MsgType)
+		assert.Equal(t, ownerAddress, grants.Received[0].Granter)
+	}
+
+	t.Log("Test only NFT messages can be delegated")
+	{
+		err := service.GrantAuthz(ctx, ownerAddress, blockchain.TxMsgSend, nil, signerKey)
+		require.ErrorIs(t, err, blockchain.ErrUnknownTxMsgType)
+	}
+}
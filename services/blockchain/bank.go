@@ -3,18 +3,39 @@ package blockchain
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	sdkmath "cosmossdk.io/math"
-	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
 )
 
+// ParseAmount converts amount in the given denom unit (e.g. "1.5" and "obd") to the coin in the base denom.
+func (bs Service) ParseAmount(ctx context.Context, amount, denom string) (sdk.Coin, error) {
+	metadatas, err := bs.nodeClient.DenomsMetadata(ctx)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	coin, err := obadanode.ParseDisplayCoin(amount, denom, metadatas)
+	if err != nil {
+		if errors.Is(err, obadanode.ErrInvalidAmount) {
+			return coin, fmt.Errorf("%w: %s", ErrInvalidAmount, err)
+		}
+
+		return coin, err
+	}
+
+	return coin, nil
+}
+
 // Send sends coins from one account to another.
-func (bs Service) Send(ctx context.Context, account services.Account, toAddress, amount string, privKey cryptotypes.PrivKey) error {
-	fromAddress, err := sdk.AccAddressFromBech32(account.Address)
+func (bs Service) Send(ctx context.Context, acc services.Account, toAddress string, amount sdk.Coin, s signer.Signer) error {
+	fromAddress, err := sdk.AccAddressFromBech32(acc.Address)
 	if err != nil {
 		return err
 	}
@@ -24,7 +45,7 @@ func (bs Service) Send(ctx context.Context, account services.Account, toAddress,
 		return err
 	}
 
-	ok, err := bs.nodeClient.HasAccount(ctx, account.Address)
+	ok, err := bs.nodeClient.HasAccount(ctx, acc.Address)
 	if err != nil {
 		return err
 	}
@@ -33,18 +54,13 @@ func (bs Service) Send(ctx context.Context, account services.Account, toAddress,
 		return ErrInsufficientFunds
 	}
 
-	coins, err := sdk.ParseCoinsNormalized(amount)
-	if err != nil {
-		return err
-	}
-
-	msg := types.NewMsgSend(fromAddress, recepientAddress, coins)
+	msg := types.NewMsgSend(fromAddress, recepientAddress, sdk.NewCoins(amount))
 
 	txConf := obadanode.TxCustomConfig{
 		Msg:       msg,
 		GasLimit:  uint64(MinGasLimit),
 		FeeAmount: sdkmath.NewInt(MinGasLimit),
-		Priv:      privKey,
+		Signer:    s,
 	}
 
 	resp, err := bs.nodeClient.SendTx(ctx, txConf)
@@ -56,6 +72,12 @@ func (bs Service) Send(ctx context.Context, account services.Account, toAddress,
 		return err
 	}
 
+	audit.SetTxHash(ctx, resp.Hash.String())
+
+	if err := bs.waitForCommit(ctx, resp.Hash); err != nil {
+		return err
+	}
+
 	bs.logger.Info("Coins were transferred", msg, resp)
 
 	return nil
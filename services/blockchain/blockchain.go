@@ -10,6 +10,7 @@ type Service struct {
 	nodeClient  node.Client
 	logger      *zap.SugaredLogger
 	registryURL string
+	sponsor     *Sponsor
 }
 
 // NewService creates a new instance of the service.
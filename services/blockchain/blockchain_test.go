@@ -9,6 +9,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -41,7 +42,7 @@ func TestService(t *testing.T) {
 }
 
 func (ts tests) testSend(t *testing.T) {
-	privKey := secp256k1.GenPrivKey()
+	privKey := signer.NewPrivKeySigner(secp256k1.GenPrivKey())
 
 	accAddress := sdk.AccAddress(privKey.PubKey().Address().Bytes()).String()
 
@@ -50,12 +51,16 @@ func (ts tests) testSend(t *testing.T) {
 		Address: accAddress,
 	}
 
-	err := ts.service.Send(ts.ctx, account, receiverAddress, "1obd", privKey)
+	amount, err := ts.service.ParseAmount(ts.ctx, "1", "obd")
+	require.NoError(t, err)
+	assert.Equal(t, "1000000rohi", amount.String())
+
+	err = ts.service.Send(ts.ctx, account, receiverAddress, amount, privKey)
 	require.ErrorIs(t, err, blockchain.ErrInsufficientFunds)
 }
 
 func (ts tests) testMintNFT(t *testing.T) {
-	privKey := secp256k1.GenPrivKey()
+	privKey := signer.NewPrivKeySigner(secp256k1.GenPrivKey())
 
 	t.Log("Test miniting NFT from account with zero tx")
 	account := services.Device{
@@ -67,7 +72,7 @@ func (ts tests) testMintNFT(t *testing.T) {
 }
 
 func (ts tests) testTransferNFT(t *testing.T) {
-	privKey := secp256k1.GenPrivKey()
+	privKey := signer.NewPrivKeySigner(secp256k1.GenPrivKey())
 
 	t.Log("Test transferring NFT to account with zero tx")
 
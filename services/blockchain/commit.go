@@ -0,0 +1,30 @@
+package blockchain
+
+import (
+	"context"
+)
+
+type commitCtxKey int
+
+const commitKey commitCtxKey = 1
+
+// WithCommit marks the context, so coins and NFT transfers sent with it wait until they are committed to the block.
+// Transactions of the account that are sent one after another need it, the next one is signed with the sequence
+// and the balance changed by the previous one.
+func WithCommit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, commitKey, true)
+}
+
+// waitForCommit waits until the transaction is committed when the context is marked by WithCommit
+func (bs Service) waitForCommit(ctx context.Context, hash []byte) error {
+	if wait, _ := ctx.Value(commitKey).(bool); !wait {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, txCommitTimeout)
+	defer cancel()
+
+	_, err := bs.nodeClient.WaitForTx(ctx, hash)
+
+	return err
+}
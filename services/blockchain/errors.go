@@ -7,9 +7,33 @@ import (
 var (
 	// ErrInsufficientFunds is returned when the account balance has insufficient funds to complete the transaction.
 	ErrInsufficientFunds = errors.New("out of funds")
+
+	// ErrUnknownTxMsgType is returned when transactions are filtered by unsupported message type.
+	ErrUnknownTxMsgType = errors.New("unknown transaction message type")
+
+	// ErrInvalidSignedTx is returned when signed transaction cannot be decoded or doesn't have signatures.
+	ErrInvalidSignedTx = errors.New("invalid signed transaction")
+
+	// ErrInvalidAmount is returned when amount cannot be converted to the base denom coin.
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrSponsorDisabled is returned when fee allowance is managed but sponsor account isn't configured.
+	ErrSponsorDisabled = errors.New("sponsor mode is disabled")
+
+	// ErrAllowanceNotFound is returned when the sponsor didn't grant fee allowance to the account.
+	ErrAllowanceNotFound = errors.New("fee allowance not found")
+
+	// ErrAuthzNotFound is returned when the device owner didn't grant the signer execution of the message.
+	ErrAuthzNotFound = errors.New("authorization not found")
 )
 
 // IsAcceptableError returns true if the error is acceptable to return to the client.
 func IsAcceptableError(err error) bool {
-	return errors.Is(err, ErrInsufficientFunds)
+	return errors.Is(err, ErrInsufficientFunds) ||
+		errors.Is(err, ErrUnknownTxMsgType) ||
+		errors.Is(err, ErrInvalidSignedTx) ||
+		errors.Is(err, ErrInvalidAmount) ||
+		errors.Is(err, ErrSponsorDisabled) ||
+		errors.Is(err, ErrAllowanceNotFound) ||
+		errors.Is(err, ErrAuthzNotFound)
 }
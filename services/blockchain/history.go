@@ -0,0 +1,241 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/fullcore/x/obit/types"
+)
+
+const (
+	// TxMsgSend coins were sent
+	TxMsgSend = "send"
+
+	// TxMsgMint NFT was minted
+	TxMsgMint = "mint_nft"
+
+	// TxMsgBatchMint batch of NFTs was minted
+	TxMsgBatchMint = "batch_mint_nft"
+
+	// TxMsgTransfer NFT was transferred
+	TxMsgTransfer = "transfer_nft"
+
+	// TxMsgBatchTransfer batch of NFTs was transferred
+	TxMsgBatchTransfer = "batch_transfer_nft"
+
+	// TxMsgUpdateURIHash NFT uri hash was updated
+	TxMsgUpdateURIHash = "update_uri_hash"
+
+	// txSearchPerPage is the max page size accepted by CometBFT TxSearch
+	txSearchPerPage = 100
+
+	// maxHistoryTxs limits how many transactions are scanned per query
+	maxHistoryTxs = 1000
+)
+
+// IsTxMsgType returns true if the given string is a known transaction message type
+func IsTxMsgType(msgType string) bool {
+	switch msgType {
+	case TxMsgSend, TxMsgMint, TxMsgBatchMint, TxMsgTransfer, TxMsgBatchTransfer, TxMsgUpdateURIHash:
+		return true
+	}
+
+	return false
+}
+
+// TxHistory returns transactions involving the given address, newest first.
+// When msgType is not empty only messages of that type are returned.
+func (bs Service) TxHistory(ctx context.Context, address, msgType string, page, perPage int) (services.TxHistory, error) {
+	history := services.TxHistory{
+		Txs:     make([]services.Tx, 0),
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	if msgType != "" && !IsTxMsgType(msgType) {
+		return history, ErrUnknownTxMsgType
+	}
+
+	// CometBFT queries do not support OR, so every direction is searched separately
+	queries := []string{
+		fmt.Sprintf("message.sender='%s'", address),
+		fmt.Sprintf("transfer.recipient='%s'", address),
+		fmt.Sprintf("cosmos.nft.v1beta1.EventSend.receiver='\"%s\"'", address),
+	}
+
+	seen := make(map[string]struct{})
+	results := make([]*ctypes.ResultTx, 0)
+
+	for _, q := range queries {
+		txs, err := bs.searchTxs(ctx, q)
+		if err != nil {
+			return history, err
+		}
+
+		for _, tx := range txs {
+			hash := tx.Hash.String()
+			if _, ok := seen[hash]; ok {
+				continue
+			}
+
+			seen[hash] = struct{}{}
+			results = append(results, tx)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Height == results[j].Height {
+			return results[i].Index > results[j].Index
+		}
+
+		return results[i].Height > results[j].Height
+	})
+
+	txs := make([]services.Tx, 0, len(results))
+
+	for _, res := range results {
+		tx, err := bs.decodeTx(res, address, msgType)
+		if err != nil {
+			return history, err
+		}
+
+		if len(tx.Messages) == 0 {
+			continue
+		}
+
+		txs = append(txs, tx)
+	}
+
+	history.Total = len(txs)
+
+	from := (page - 1) * perPage
+	if from >= len(txs) {
+		return history, nil
+	}
+
+	to := from + perPage
+	if to > len(txs) {
+		to = len(txs)
+	}
+
+	history.Txs = txs[from:to]
+
+	return history, nil
+}
+
+func (bs Service) searchTxs(ctx context.Context, query string) ([]*ctypes.ResultTx, error) {
+	txs := make([]*ctypes.ResultTx, 0)
+
+	for page := 1; len(txs) < maxHistoryTxs; page++ {
+		resp, err := bs.nodeClient.TxSearch(ctx, query, page, txSearchPerPage)
+		if err != nil {
+			return nil, fmt.Errorf("cannot search txs by %q: %w", query, err)
+		}
+
+		txs = append(txs, resp.Txs...)
+
+		if len(resp.Txs) < txSearchPerPage || len(txs) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return txs, nil
+}
+
+func (bs Service) decodeTx(res *ctypes.ResultTx, address, msgType string) (services.Tx, error) {
+	tx := services.Tx{
+		Hash:     strings.ToUpper(hex.EncodeToString(res.Hash)),
+		Height:   res.Height,
+		Code:     res.TxResult.Code,
+		Messages: make([]services.TxMsg, 0),
+	}
+
+	decoded, err := bs.nodeClient.DecodeTx(res.Tx)
+	if err != nil {
+		return tx, fmt.Errorf("cannot decode tx %s: %w", tx.Hash, err)
+	}
+
+	if txWithMemo, ok := decoded.Tx.(sdk.TxWithMemo); ok {
+		tx.Memo = txWithMemo.GetMemo()
+	}
+
+	for _, msg := range decoded.GetMsgs() {
+		txMsg, ok := DecodeTxMsg(msg)
+		if !ok {
+			continue
+		}
+
+		if msgType != "" && txMsg.Type != msgType {
+			continue
+		}
+
+		if txMsg.Sender != address && txMsg.Receiver != address {
+			continue
+		}
+
+		tx.Messages = append(tx.Messages, txMsg)
+	}
+
+	return tx, nil
+}
+
+// DecodeTxMsg converts a known blockchain message to its JSON friendly representation
+func DecodeTxMsg(msg sdk.Msg) (services.TxMsg, bool) {
+	switch m := msg.(type) {
+	case *banktypes.MsgSend:
+		return services.TxMsg{
+			Type:     TxMsgSend,
+			Sender:   m.FromAddress,
+			Receiver: m.ToAddress,
+			Amount:   m.Amount,
+		}, true
+	case *types.MsgMintNFT:
+		return services.TxMsg{
+			Type:    TxMsgMint,
+			Sender:  m.Creator,
+			DIDs:    []string{m.Id},
+			URIHash: m.UriHash,
+		}, true
+	case *types.MsgBatchMintNFT:
+		dids := make([]string, 0, len(m.Nft))
+		for _, nft := range m.Nft {
+			dids = append(dids, nft.Id)
+		}
+
+		return services.TxMsg{
+			Type:   TxMsgBatchMint,
+			Sender: m.Creator,
+			DIDs:   dids,
+		}, true
+	case *types.MsgTransferNFT:
+		return services.TxMsg{
+			Type:     TxMsgTransfer,
+			Sender:   m.Sender,
+			Receiver: m.Receiver,
+			DIDs:     []string{m.Id},
+		}, true
+	case *types.MsgBatchTransferNFT:
+		return services.TxMsg{
+			Type:     TxMsgBatchTransfer,
+			Sender:   m.Sender,
+			Receiver: m.Receiver,
+			DIDs:     m.Ids,
+		}, true
+	case *types.MsgUpdateUriHash:
+		return services.TxMsg{
+			Type:    TxMsgUpdateURIHash,
+			Sender:  m.Editor,
+			DIDs:    []string{m.Id},
+			URIHash: m.UriHash,
+		}, true
+	}
+
+	return services.TxMsg{}, false
+}
@@ -0,0 +1,100 @@
+package blockchain_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/testutil"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const historyAddress = "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg"
+
+func makeTx(t *testing.T, msgs ...sdk.Msg) obadanode.Tx {
+	txBuilder := cosmostestutil.MakeTestEncodingConfig().TxConfig.NewTxBuilder()
+	require.NoError(t, txBuilder.SetMsgs(msgs...))
+
+	return obadanode.Tx{Tx: txBuilder.GetTx()}
+}
+
+func TestTxHistory(t *testing.T) {
+	logger, lgDefer := testutil.MakeLoger()
+	defer lgDefer()
+
+	nodeClient := mocks.NewClient(t)
+	service := blockchain.NewService(nodeClient, logger, "")
+	ctx := context.Background()
+
+	sent := &ctypes.ResultTx{Hash: []byte{0x01}, Height: 10, Tx: []byte("sent")}
+	received := &ctypes.ResultTx{Hash: []byte{0x02}, Height: 12, Tx: []byte("received")}
+	minted := &ctypes.ResultTx{Hash: []byte{0x03}, Height: 11, Tx: []byte("minted")}
+
+	nodeClient.On("TxSearch", mock.Anything, fmt.Sprintf("message.sender='%s'", historyAddress), 1, 100).
+		Return(&ctypes.ResultTxSearch{Txs: []*ctypes.ResultTx{minted, sent}, TotalCount: 2}, nil)
+	nodeClient.On("TxSearch", mock.Anything, fmt.Sprintf("transfer.recipient='%s'", historyAddress), 1, 100).
+		Return(&ctypes.ResultTxSearch{Txs: []*ctypes.ResultTx{received}, TotalCount: 1}, nil)
+	nodeClient.On("TxSearch", mock.Anything, fmt.Sprintf("cosmos.nft.v1beta1.EventSend.receiver='\"%s\"'", historyAddress), 1, 100).
+		Return(&ctypes.ResultTxSearch{Txs: []*ctypes.ResultTx{}, TotalCount: 0}, nil)
+
+	nodeClient.On("DecodeTx", []byte("sent")).Return(makeTx(t, &banktypes.MsgSend{
+		FromAddress: historyAddress,
+		ToAddress:   receiverAddress,
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("rohi", 10)),
+	}), nil)
+	nodeClient.On("DecodeTx", []byte("received")).Return(makeTx(t, &banktypes.MsgSend{
+		FromAddress: receiverAddress,
+		ToAddress:   historyAddress,
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("rohi", 5)),
+	}), nil)
+	nodeClient.On("DecodeTx", []byte("minted")).Return(makeTx(t, &obadatypes.MsgMintNFT{
+		Creator: historyAddress,
+		Id:      "did:obada:12345",
+		UriHash: "hash",
+	}), nil)
+
+	t.Log("Test history is sorted by height and paginated")
+	{
+		history, err := service.TxHistory(ctx, historyAddress, "", 1, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, history.Total)
+		require.Len(t, history.Txs, 2)
+		assert.Equal(t, int64(12), history.Txs[0].Height)
+		assert.Equal(t, "02", history.Txs[0].Hash)
+		assert.Equal(t, blockchain.TxMsgSend, history.Txs[0].Messages[0].Type)
+		assert.Equal(t, historyAddress, history.Txs[0].Messages[0].Receiver)
+		assert.Equal(t, int64(11), history.Txs[1].Height)
+
+		history, err = service.TxHistory(ctx, historyAddress, "", 2, 2)
+		require.NoError(t, err)
+		require.Len(t, history.Txs, 1)
+		assert.Equal(t, int64(10), history.Txs[0].Height)
+	}
+
+	t.Log("Test history filtered by message type")
+	{
+		history, err := service.TxHistory(ctx, historyAddress, blockchain.TxMsgMint, 1, 20)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, history.Total)
+		require.Len(t, history.Txs, 1)
+		assert.Equal(t, []string{"did:obada:12345"}, history.Txs[0].Messages[0].DIDs)
+	}
+
+	t.Log("Test history with unknown message type")
+	{
+		_, err := service.TxHistory(ctx, historyAddress, "burn", 1, 20)
+		require.ErrorIs(t, err, blockchain.ErrUnknownTxMsgType)
+	}
+}
@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+
+	sdkmath "cosmossdk.io/math"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+)
+
+// BuildMultisigTx builds a transaction of the given type from the multisig account, the transaction
+// is broadcasted once co-signers of the threshold signed it.
+func (bs Service) BuildMultisigTx(ctx context.Context, req UnsignedTxRequest, pubKey cryptotypes.PubKey) (obadanode.MultisigTx, error) {
+	var mtx obadanode.MultisigTx
+
+	req.Signer = sdk.AccAddress(pubKey.Address()).String()
+
+	feeGranter, err := bs.feeGranter(ctx, req.Signer)
+	if err != nil {
+		return mtx, err
+	}
+
+	ok, err := bs.nodeClient.HasAccount(ctx, req.Signer)
+	if err != nil {
+		return mtx, err
+	}
+
+	if !ok {
+		return mtx, ErrInsufficientFunds
+	}
+
+	msg, err := bs.buildOfflineMsg(ctx, req)
+	if err != nil {
+		return mtx, err
+	}
+
+	txConf := obadanode.TxCustomConfig{
+		Msg:        msg,
+		GasLimit:   uint64(MinGasLimit),
+		FeeAmount:  sdkmath.NewInt(MinGasLimit),
+		FeeGranter: feeGranter,
+	}
+
+	return bs.nodeClient.BuildMultisigTx(ctx, pubKey, txConf)
+}
+
+// BroadcastMultisigTx broadcasts the multisig account transaction with signatures of co-signers,
+// keyed by their addresses, and returns the tx hash.
+func (bs Service) BroadcastMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, mtx obadanode.MultisigTx,
+	signatures map[string][]byte,
+) (string, error) {
+	resp, err := bs.nodeClient.BroadcastMultisigTx(ctx, pubKey, mtx, signatures)
+	if err != nil {
+		if errors.Is(err, obadanode.ErrInsufficientFunds) {
+			return "", ErrInsufficientFunds
+		}
+
+		return "", err
+	}
+
+	audit.SetTxHash(ctx, resp.Hash.String())
+	bs.logger.Info("Multisig transaction was broadcasted", resp)
+
+	return resp.Hash.String(), nil
+}
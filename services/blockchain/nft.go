@@ -7,12 +7,12 @@ import (
 	"strings"
 
 	sdkmath "cosmossdk.io/math"
-	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/golang/protobuf/jsonpb" //nolint:staticcheck // wait for refactoring
 	"github.com/golang/protobuf/proto"  //nolint:staticcheck // wait for refactoring
 	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
 	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/fullcore/x/obit/types"
 )
 
@@ -92,6 +92,15 @@ func (bs Service) buildMintMsg(d services.Device, address string) *types.MsgMint
 	}
 }
 
+// ownerAddress returns the device owner, the signer owns devices without address
+func ownerAddress(d services.Device, signer string) string {
+	if d.Address == "" {
+		return signer
+	}
+
+	return d.Address
+}
+
 func (bs Service) buildBatchMintMsg(devices []services.Device, address string) *types.MsgBatchMintNFT {
 
 	nfts := make([]types.MsgBatchNFT, 0, len(devices))
@@ -114,8 +123,13 @@ func (bs Service) buildBatchMintMsg(devices []services.Device, address string) *
 }
 
 // EditNFTMetadata edits NFT metadata.
-func (bs Service) EditNFTMetadata(ctx context.Context, d services.Device, privKey cryptotypes.PrivKey) error {
-	accAddress := sdk.AccAddress(privKey.PubKey().Address().Bytes()).String()
+func (bs Service) EditNFTMetadata(ctx context.Context, d services.Device, s signer.Signer) error {
+	accAddress := signer.Address(s).String()
+
+	feeGranter, err := bs.feeGranter(ctx, accAddress)
+	if err != nil {
+		return err
+	}
 
 	nft, err := bs.GetNFT(ctx, d.DID)
 	if err != nil {
@@ -128,17 +142,23 @@ func (bs Service) EditNFTMetadata(ctx context.Context, d services.Device, privKe
 		return er
 	}
 
-	msg := &types.MsgUpdateUriHash{
+	owner := ownerAddress(d, accAddress)
+
+	msg, err := bs.execMsg(ctx, accAddress, owner, &types.MsgUpdateUriHash{
 		Id:      nft.Id,
-		Editor:  accAddress,
+		Editor:  owner,
 		UriHash: d.Checksum,
+	})
+	if err != nil {
+		return err
 	}
 
 	txConf := obadanode.TxCustomConfig{
-		Msg:       msg,
-		GasLimit:  uint64(MinGasLimit),
-		FeeAmount: sdkmath.NewInt(MinGasLimit),
-		Priv:      privKey,
+		Msg:        msg,
+		GasLimit:   uint64(MinGasLimit),
+		FeeAmount:  sdkmath.NewInt(MinGasLimit),
+		Signer:     s,
+		FeeGranter: feeGranter,
 	}
 
 	resp, err := bs.nodeClient.SendTx(ctx, txConf)
@@ -149,14 +169,20 @@ func (bs Service) EditNFTMetadata(ctx context.Context, d services.Device, privKe
 
 		return err
 	}
+	audit.SetTxHash(ctx, resp.Hash.String())
 	bs.logger.Info("NFT metadata was updated", resp)
 
 	return err
 }
 
 // MintNFT creates new NFT.
-func (bs Service) MintNFT(ctx context.Context, d services.Device, privKey cryptotypes.PrivKey) error {
-	accAddress := sdk.AccAddress(privKey.PubKey().Address().Bytes()).String()
+func (bs Service) MintNFT(ctx context.Context, d services.Device, s signer.Signer) error {
+	accAddress := signer.Address(s).String()
+
+	feeGranter, err := bs.feeGranter(ctx, accAddress)
+	if err != nil {
+		return err
+	}
 
 	ok, err := bs.nodeClient.HasAccount(ctx, accAddress)
 	if err != nil {
@@ -167,13 +193,19 @@ func (bs Service) MintNFT(ctx context.Context, d services.Device, privKey crypto
 		return ErrInsufficientFunds
 	}
 
-	msg := bs.buildMintMsg(d, accAddress)
+	owner := ownerAddress(d, accAddress)
+
+	msg, err := bs.execMsg(ctx, accAddress, owner, bs.buildMintMsg(d, owner))
+	if err != nil {
+		return err
+	}
 
 	txConf := obadanode.TxCustomConfig{
-		Msg:       msg,
-		GasLimit:  uint64(MinGasLimit),
-		FeeAmount: sdkmath.NewInt(MinGasLimit),
-		Priv:      privKey,
+		Msg:        msg,
+		GasLimit:   uint64(MinGasLimit),
+		FeeAmount:  sdkmath.NewInt(MinGasLimit),
+		Signer:     s,
+		FeeGranter: feeGranter,
 	}
 
 	resp, err := bs.nodeClient.SendTx(ctx, txConf)
@@ -184,14 +216,20 @@ func (bs Service) MintNFT(ctx context.Context, d services.Device, privKey crypto
 
 		return err
 	}
+	audit.SetTxHash(ctx, resp.Hash.String())
 	bs.logger.Info("NFT was minted", resp)
 
 	return nil
 }
 
 // BatchMintNFT mints many NFTs fron the batch.
-func (bs Service) BatchMintNFT(ctx context.Context, ds []services.Device, privKey cryptotypes.PrivKey) error {
-	accAddress := sdk.AccAddress(privKey.PubKey().Address().Bytes()).String()
+func (bs Service) BatchMintNFT(ctx context.Context, ds []services.Device, s signer.Signer) error {
+	accAddress := signer.Address(s).String()
+
+	feeGranter, err := bs.feeGranter(ctx, accAddress)
+	if err != nil {
+		return err
+	}
 
 	ok, err := bs.nodeClient.HasAccount(ctx, accAddress)
 	if err != nil {
@@ -207,10 +245,11 @@ func (bs Service) BatchMintNFT(ctx context.Context, ds []services.Device, privKe
 	multiplier := len(msg.GetNft())
 
 	txConf := obadanode.TxCustomConfig{
-		Msg:       msg,
-		GasLimit:  uint64(MinGasLimit * multiplier),
-		FeeAmount: sdkmath.NewInt(MinGasLimit * int64(multiplier)),
-		Priv:      privKey,
+		Msg:        msg,
+		GasLimit:   uint64(MinGasLimit * multiplier),
+		FeeAmount:  sdkmath.NewInt(MinGasLimit * int64(multiplier)),
+		Signer:     s,
+		FeeGranter: feeGranter,
 	}
 
 	resp, err := bs.nodeClient.SendTx(ctx, txConf)
@@ -221,14 +260,15 @@ func (bs Service) BatchMintNFT(ctx context.Context, ds []services.Device, privKe
 
 		return err
 	}
+	audit.SetTxHash(ctx, resp.Hash.String())
 	bs.logger.Info("NFT batch was minted", resp)
 
 	return nil
 }
 
 // TransferNFT transfers NFT to another address.
-func (bs Service) TransferNFT(ctx context.Context, did, receiverAddr string, privKey cryptotypes.PrivKey) error {
-	accAddress := sdk.AccAddress(privKey.PubKey().Address().Bytes()).String()
+func (bs Service) TransferNFT(ctx context.Context, did, receiverAddr string, s signer.Signer) error {
+	accAddress := signer.Address(s).String()
 
 	ok, err := bs.nodeClient.HasAccount(ctx, accAddress)
 	if err != nil {
@@ -249,7 +289,7 @@ func (bs Service) TransferNFT(ctx context.Context, did, receiverAddr string, pri
 		Msg:       msg,
 		GasLimit:  uint64(MinGasLimit),
 		FeeAmount: sdkmath.NewInt(MinGasLimit),
-		Priv:      privKey,
+		Signer:    s,
 	}
 
 	resp, err := bs.nodeClient.SendTx(ctx, txConf)
@@ -261,6 +301,12 @@ func (bs Service) TransferNFT(ctx context.Context, did, receiverAddr string, pri
 		return err
 	}
 
+	audit.SetTxHash(ctx, resp.Hash.String())
+
+	if err := bs.waitForCommit(ctx, resp.Hash); err != nil {
+		return err
+	}
+
 	bs.logger.Info("NFT transfer request was sent", msg, resp)
 
 	return nil
@@ -0,0 +1,120 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/fullcore/x/obit/types"
+)
+
+// UnsignedTxRequest describes a transaction that should be built for the offline signing.
+type UnsignedTxRequest struct {
+	Signer   string
+	Type     string
+	Receiver string
+	Amount   string
+	Denom    string
+	Device   services.Device
+}
+
+// BuildUnsignedTx builds a transaction of the given type that will be signed outside of client-helper.
+func (bs Service) BuildUnsignedTx(ctx context.Context, req UnsignedTxRequest) (obadanode.UnsignedTx, error) {
+	var utx obadanode.UnsignedTx
+
+	if _, err := sdk.AccAddressFromBech32(req.Signer); err != nil {
+		return utx, err
+	}
+
+	ok, err := bs.nodeClient.HasAccount(ctx, req.Signer)
+	if err != nil {
+		return utx, err
+	}
+
+	if !ok {
+		return utx, ErrInsufficientFunds
+	}
+
+	msg, err := bs.buildOfflineMsg(ctx, req)
+	if err != nil {
+		return utx, err
+	}
+
+	txConf := obadanode.TxCustomConfig{
+		Msg:       msg,
+		GasLimit:  uint64(MinGasLimit),
+		FeeAmount: sdkmath.NewInt(MinGasLimit),
+	}
+
+	return bs.nodeClient.UnsignedTx(ctx, req.Signer, txConf)
+}
+
+func (bs Service) buildOfflineMsg(ctx context.Context, req UnsignedTxRequest) (sdk.Msg, error) {
+	switch req.Type {
+	case TxMsgSend:
+		fromAddress, err := sdk.AccAddressFromBech32(req.Signer)
+		if err != nil {
+			return nil, err
+		}
+
+		toAddress, err := sdk.AccAddressFromBech32(req.Receiver)
+		if err != nil {
+			return nil, err
+		}
+
+		coin, err := bs.ParseAmount(ctx, req.Amount, req.Denom)
+		if err != nil {
+			return nil, err
+		}
+
+		return banktypes.NewMsgSend(fromAddress, toAddress, sdk.NewCoins(coin)), nil
+	case TxMsgMint:
+		return bs.buildMintMsg(req.Device, req.Signer), nil
+	case TxMsgTransfer:
+		if _, err := sdk.AccAddressFromBech32(req.Receiver); err != nil {
+			return nil, err
+		}
+
+		return &types.MsgTransferNFT{
+			Id:       req.Device.DID,
+			Sender:   req.Signer,
+			Receiver: req.Receiver,
+		}, nil
+	case TxMsgUpdateURIHash:
+		return &types.MsgUpdateUriHash{
+			Id:      req.Device.DID,
+			Editor:  req.Signer,
+			UriHash: req.Device.Checksum,
+		}, nil
+	}
+
+	return nil, ErrUnknownTxMsgType
+}
+
+// BroadcastTx broadcasts a transaction that was signed outside of client-helper and returns the tx hash.
+func (bs Service) BroadcastTx(ctx context.Context, txData []byte, isJSON bool) (string, error) {
+	resp, err := bs.nodeClient.BroadcastTx(ctx, txData, isJSON)
+	if err != nil {
+		if errors.Is(err, obadanode.ErrInsufficientFunds) {
+			return "", ErrInsufficientFunds
+		}
+
+		if errors.Is(err, obadanode.ErrTxNotSigned) || errors.Is(err, obadanode.ErrTxDecode) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidSignedTx, err)
+		}
+
+		return "", err
+	}
+
+	bs.logger.Info("Signed transaction was broadcasted", resp)
+
+	audit.SetTxHash(ctx, resp.Hash.String())
+
+	return resp.Hash.String(), nil
+}
@@ -0,0 +1,90 @@
+package blockchain_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/testutil"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+)
+
+func TestOfflineTx(t *testing.T) {
+	logger, lgDefer := testutil.MakeLoger()
+	defer lgDefer()
+
+	nodeClient := mocks.NewClient(t)
+	service := blockchain.NewService(nodeClient, logger, "")
+	ctx := context.Background()
+
+	signDoc := obadanode.SignDoc{ChainID: "obada-testnet", AccountNumber: 7, Sequence: 3}
+
+	nodeClient.On("HasAccount", mock.Anything, historyAddress).Return(true, nil)
+
+	t.Log("Test unsigned transfer transaction is built for the signer")
+	{
+		nodeClient.On("UnsignedTx", mock.Anything, historyAddress, mock.MatchedBy(func(cnf obadanode.TxCustomConfig) bool {
+			msg, ok := cnf.Msg.(*obadatypes.MsgTransferNFT)
+			return ok && cnf.Signer == nil && msg.Sender == historyAddress && msg.Receiver == receiverAddress
+		})).Return(obadanode.UnsignedTx{SignDoc: signDoc}, nil).Once()
+
+		utx, err := service.BuildUnsignedTx(ctx, blockchain.UnsignedTxRequest{
+			Signer:   historyAddress,
+			Type:     blockchain.TxMsgTransfer,
+			Receiver: receiverAddress,
+			Device:   services.Device{DID: "did:obada:12345"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, signDoc, utx.SignDoc)
+	}
+
+	t.Log("Test unsupported transaction type is rejected")
+	{
+		_, err := service.BuildUnsignedTx(ctx, blockchain.UnsignedTxRequest{
+			Signer: historyAddress,
+			Type:   blockchain.TxMsgBatchMint,
+		})
+		require.ErrorIs(t, err, blockchain.ErrUnknownTxMsgType)
+	}
+
+	t.Log("Test signed transaction is broadcasted")
+	{
+		nodeClient.On("BroadcastTx", mock.Anything, []byte("signed"), false).
+			Return(&ctypes.ResultBroadcastTx{Hash: []byte{0xAB}}, nil).Once()
+
+		auditSvc := audit.NewService(audit.Config{DB: db.NewMemDB(), Logger: logger})
+		auditCtx := audit.NewContext(auth.SetClaims(ctx, auth.Claims{UserID: "1"}), audit.ActionTxBroadcast, "")
+
+		hash, err := service.BroadcastTx(auditCtx, []byte("signed"), false)
+		require.NoError(t, err)
+		assert.Equal(t, "AB", hash)
+
+		require.NoError(t, auditSvc.Record(auditCtx, nil))
+
+		entries, err := auditSvc.Search(auditCtx, services.AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "AB", entries[0].TxHash)
+	}
+
+	t.Log("Test unsigned transaction cannot be broadcasted")
+	{
+		nodeClient.On("BroadcastTx", mock.Anything, []byte("{}"), true).
+			Return(nil, fmt.Errorf("wrapped: %w", obadanode.ErrTxNotSigned)).Once()
+
+		_, err := service.BroadcastTx(ctx, []byte("{}"), true)
+		require.ErrorIs(t, err, blockchain.ErrInvalidSignedTx)
+		assert.True(t, blockchain.IsAcceptableError(err))
+	}
+}
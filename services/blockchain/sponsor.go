@@ -0,0 +1,244 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"cosmossdk.io/x/feegrant"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/audit"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+)
+
+// txCommitTimeout how long to wait for the transaction to be committed
+const txCommitTimeout = 30 * time.Second
+
+// Sponsor is a treasury account that pays fees of the managed accounts using x/feegrant module.
+type Sponsor struct {
+	// Signer of the treasury account
+	Signer signer.Signer
+
+	// SpendLimit of the allowance granted automatically, it is required with AutoGrant
+	SpendLimit sdk.Coins
+
+	// Expiration of the allowance granted automatically, zero means never expires
+	Expiration time.Duration
+
+	// AutoGrant grants allowance to the account on the first sponsored transaction
+	AutoGrant bool
+}
+
+// sponsoredMsgs messages that the sponsor pays fees of, MsgExec covers mints and updates executed through authz grants
+var sponsoredMsgs = []string{
+	sdk.MsgTypeURL(&obadatypes.MsgMintNFT{}),
+	sdk.MsgTypeURL(&obadatypes.MsgBatchMintNFT{}),
+	sdk.MsgTypeURL(&obadatypes.MsgUpdateUriHash{}),
+	sdk.MsgTypeURL(&authz.MsgExec{}),
+}
+
+// SetSponsor enables sponsor mode, mints and metadata updates will be paid by the sponsor.
+func (bs *Service) SetSponsor(sponsor *Sponsor) {
+	bs.sponsor = sponsor
+}
+
+func (bs Service) sponsorAddress() (sdk.AccAddress, error) {
+	if bs.sponsor == nil {
+		return nil, ErrSponsorDisabled
+	}
+
+	return signer.Address(bs.sponsor.Signer), nil
+}
+
+// Allowance returns fee allowance granted by the sponsor to the account.
+func (bs Service) Allowance(ctx context.Context, address string) (services.FeeAllowance, error) {
+	var fa services.FeeAllowance
+
+	granter, err := bs.sponsorAddress()
+	if err != nil {
+		return fa, err
+	}
+
+	allowance, err := bs.nodeClient.Allowance(ctx, granter.String(), address)
+	if err != nil {
+		if errors.Is(err, obadanode.ErrAllowanceNotFound) {
+			return fa, ErrAllowanceNotFound
+		}
+
+		return fa, err
+	}
+
+	fa = services.FeeAllowance{
+		Granter: granter.String(),
+		Grantee: address,
+	}
+
+	if basic := basicAllowance(allowance); basic != nil {
+		fa.SpendLimit = basic.SpendLimit
+		fa.Expiration = basic.Expiration
+	}
+
+	return fa, nil
+}
+
+func basicAllowance(allowance feegrant.FeeAllowanceI) *feegrant.BasicAllowance {
+	switch a := allowance.(type) {
+	case *feegrant.BasicAllowance:
+		return a
+	case *feegrant.PeriodicAllowance:
+		return &a.Basic
+	case *feegrant.AllowedMsgAllowance:
+		inner, err := a.GetAllowance()
+		if err != nil {
+			return nil
+		}
+
+		return basicAllowance(inner)
+	}
+
+	return nil
+}
+
+// GrantAllowance grants (or replaces existing) fee allowance from the sponsor to the account, the allowance
+// pays only fees of sponsored messages. The existing allowance is revoked in the same transaction.
+func (bs Service) GrantAllowance(ctx context.Context, address string, spendLimit sdk.Coins, expiration *time.Time) error {
+	granter, err := bs.sponsorAddress()
+	if err != nil {
+		return err
+	}
+
+	grantee, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return err
+	}
+
+	allowance, err := feegrant.NewAllowedMsgAllowance(&feegrant.BasicAllowance{
+		SpendLimit: spendLimit,
+		Expiration: expiration,
+	}, sponsoredMsgs)
+	if err != nil {
+		return err
+	}
+
+	grant, err := feegrant.NewMsgGrantAllowance(allowance, granter, grantee)
+	if err != nil {
+		return err
+	}
+
+	msgs := []sdk.Msg{grant}
+
+	if _, er := bs.Allowance(ctx, address); er == nil {
+		revoke := feegrant.NewMsgRevokeAllowance(granter, grantee)
+		msgs = []sdk.Msg{&revoke, grant}
+	} else if !errors.Is(er, ErrAllowanceNotFound) {
+		return er
+	}
+
+	if err := bs.sendSponsorTx(ctx, msgs...); err != nil {
+		return err
+	}
+
+	bs.logger.Info("Fee allowance was granted", address, spendLimit)
+
+	return nil
+}
+
+// RevokeAllowance revokes fee allowance granted by the sponsor to the account.
+func (bs Service) RevokeAllowance(ctx context.Context, address string) error {
+	granter, err := bs.sponsorAddress()
+	if err != nil {
+		return err
+	}
+
+	grantee, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return err
+	}
+
+	msg := feegrant.NewMsgRevokeAllowance(granter, grantee)
+
+	if err := bs.sendSponsorTx(ctx, &msg); err != nil {
+		return err
+	}
+
+	bs.logger.Info("Fee allowance was revoked", address)
+
+	return nil
+}
+
+// sendSponsorTx sends transaction of the messages signed by the sponsor and waits for the commit,
+// so next transactions of the sponsor and the grantee see the updated state
+func (bs Service) sendSponsorTx(ctx context.Context, msgs ...sdk.Msg) error {
+	gasLimit := int64(MinGasLimit * len(msgs))
+
+	resp, err := bs.nodeClient.SendTx(ctx, obadanode.TxCustomConfig{
+		Msg:       msgs[0],
+		Msgs:      msgs[1:],
+		GasLimit:  uint64(gasLimit),
+		FeeAmount: sdkmath.NewInt(gasLimit),
+		Signer:    bs.sponsor.Signer,
+	})
+	if err != nil {
+		if errors.Is(err, obadanode.ErrInsufficientFunds) {
+			return fmt.Errorf("sponsor: %w", ErrInsufficientFunds)
+		}
+
+		return err
+	}
+
+	audit.SetTxHash(ctx, resp.Hash.String())
+
+	ctx, cancel := context.WithTimeout(ctx, txCommitTimeout)
+	defer cancel()
+
+	if _, err := bs.nodeClient.WaitForTx(ctx, resp.Hash); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// feeGranter returns the sponsor address if the sponsor pays fees for the account,
+// the allowance is granted on the first transaction when auto grant is enabled.
+func (bs Service) feeGranter(ctx context.Context, address string) (sdk.AccAddress, error) {
+	if bs.sponsor == nil {
+		return nil, nil
+	}
+
+	granter, err := bs.sponsorAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = bs.Allowance(ctx, address)
+	if err == nil {
+		return granter, nil
+	}
+
+	if !errors.Is(err, ErrAllowanceNotFound) {
+		return nil, err
+	}
+
+	if !bs.sponsor.AutoGrant {
+		return nil, nil
+	}
+
+	var expiration *time.Time
+
+	if bs.sponsor.Expiration > 0 {
+		exp := time.Now().Add(bs.sponsor.Expiration)
+		expiration = &exp
+	}
+
+	if err := bs.GrantAllowance(ctx, address, bs.sponsor.SpendLimit, expiration); err != nil {
+		return nil, err
+	}
+
+	return granter, nil
+}
@@ -0,0 +1,125 @@
+package blockchain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cosmossdk.io/x/feegrant"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/system/signer"
+	"github.com/obada-foundation/client-helper/testutil"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSponsor(t *testing.T) {
+	logger, lgDefer := testutil.MakeLoger()
+	defer lgDefer()
+
+	nodeClient := mocks.NewClient(t)
+	service := blockchain.NewService(nodeClient, logger, "")
+	ctx := context.Background()
+
+	sponsorKey := signer.NewPrivKeySigner(secp256k1.GenPrivKey())
+	sponsorAddress := sdk.AccAddress(sponsorKey.PubKey().Address()).String()
+
+	ownerKey := signer.NewPrivKeySigner(secp256k1.GenPrivKey())
+	ownerAddress := sdk.AccAddress(ownerKey.PubKey().Address()).String()
+
+	t.Log("Test allowance cannot be managed without sponsor")
+	{
+		_, err := service.Allowance(ctx, ownerAddress)
+		require.ErrorIs(t, err, blockchain.ErrSponsorDisabled)
+	}
+
+	spendLimit := sdk.NewCoins(sdk.NewInt64Coin("rohi", 5000000))
+
+	service.SetSponsor(&blockchain.Sponsor{
+		Signer:     sponsorKey,
+		SpendLimit: spendLimit,
+		AutoGrant:  true,
+	})
+
+	t.Log("Test allowance is granted on the first mint and sponsor pays the fee")
+	{
+		nodeClient.On("Allowance", mock.Anything, sponsorAddress, ownerAddress).
+			Return(nil, obadanode.ErrAllowanceNotFound).Twice()
+
+		nodeClient.On("SendTx", mock.Anything, mock.MatchedBy(func(cnf obadanode.TxCustomConfig) bool {
+			msg, ok := cnf.Msg.(*feegrant.MsgGrantAllowance)
+			if !ok || cnf.Signer != sponsorKey || msg.Grantee != ownerAddress || len(cnf.Msgs) != 0 {
+				return false
+			}
+
+			allowance, err := msg.GetFeeAllowanceI()
+			if err != nil {
+				return false
+			}
+
+			allowed, ok := allowance.(*feegrant.AllowedMsgAllowance)
+			return ok && assert.ObjectsAreEqual([]string{
+				sdk.MsgTypeURL(&obadatypes.MsgMintNFT{}),
+				sdk.MsgTypeURL(&obadatypes.MsgBatchMintNFT{}),
+				sdk.MsgTypeURL(&obadatypes.MsgUpdateUriHash{}),
+				sdk.MsgTypeURL(&authz.MsgExec{}),
+			}, allowed.AllowedMessages)
+		})).Return(&ctypes.ResultBroadcastTx{Hash: []byte{0x01}}, nil).Once()
+
+		nodeClient.On("WaitForTx", mock.Anything, []byte{0x01}).Return(&ctypes.ResultTx{}, nil).Once()
+		nodeClient.On("HasAccount", mock.Anything, ownerAddress).Return(true, nil).Once()
+
+		nodeClient.On("SendTx", mock.Anything, mock.MatchedBy(func(cnf obadanode.TxCustomConfig) bool {
+			_, ok := cnf.Msg.(*obadatypes.MsgMintNFT)
+			return ok && cnf.Signer == ownerKey && cnf.FeeGranter.String() == sponsorAddress
+		})).Return(&ctypes.ResultBroadcastTx{Hash: []byte{0x02}}, nil).Once()
+
+		err := service.MintNFT(ctx, services.Device{DID: "did:obada:12345"}, ownerKey)
+		require.NoError(t, err)
+	}
+
+	t.Log("Test granted allowance is returned")
+	{
+		expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		nodeClient.On("Allowance", mock.Anything, sponsorAddress, ownerAddress).
+			Return(&feegrant.BasicAllowance{SpendLimit: spendLimit, Expiration: &expiration}, nil).Once()
+
+		allowance, err := service.Allowance(ctx, ownerAddress)
+		require.NoError(t, err)
+
+		assert.Equal(t, sponsorAddress, allowance.Granter)
+		assert.Equal(t, ownerAddress, allowance.Grantee)
+		assert.Equal(t, spendLimit, allowance.SpendLimit)
+		assert.Equal(t, &expiration, allowance.Expiration)
+	}
+
+	t.Log("Test existing allowance is revoked and granted again in the same transaction")
+	{
+		nodeClient.On("Allowance", mock.Anything, sponsorAddress, ownerAddress).
+			Return(&feegrant.BasicAllowance{SpendLimit: spendLimit}, nil).Once()
+
+		nodeClient.On("SendTx", mock.Anything, mock.MatchedBy(func(cnf obadanode.TxCustomConfig) bool {
+			revoke, ok := cnf.Msg.(*feegrant.MsgRevokeAllowance)
+			if !ok || revoke.Grantee != ownerAddress || len(cnf.Msgs) != 1 {
+				return false
+			}
+
+			grant, ok := cnf.Msgs[0].(*feegrant.MsgGrantAllowance)
+			return ok && grant.Grantee == ownerAddress
+		})).Return(&ctypes.ResultBroadcastTx{Hash: []byte{0x03}}, nil).Once()
+
+		nodeClient.On("WaitForTx", mock.Anything, []byte{0x03}).Return(&ctypes.ResultTx{}, nil).Once()
+
+		require.NoError(t, service.GrantAllowance(ctx, ownerAddress, spendLimit, nil))
+	}
+}
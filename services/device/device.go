@@ -18,9 +18,11 @@ import (
 	svcs "github.com/obada-foundation/client-helper/services"
 	"github.com/obada-foundation/client-helper/system/encoder"
 	ipfssh "github.com/obada-foundation/client-helper/system/ipfs"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
 	"github.com/obada-foundation/fullcore/x/obit/types"
 	regapi "github.com/obada-foundation/registry/api"
+	pbacc "github.com/obada-foundation/registry/api/pb/v1/account"
 	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
 	"github.com/obada-foundation/registry/client"
 	regtypes "github.com/obada-foundation/registry/types"
@@ -158,7 +160,7 @@ func (ds Service) handleDocuments(_ context.Context, sd svcs.SaveDevice, pk cryp
 }
 
 // Save a device and register it in DID registry
-func (ds Service) Save(ctx context.Context, sd svcs.SaveDevice, pk cryptotypes.PrivKey) (svcs.Device, error) {
+func (ds Service) Save(ctx context.Context, sd svcs.SaveDevice, s signer.Signer) (svcs.Device, error) {
 	var device svcs.Device
 
 	userID := auth.GetClaims(ctx).UserID
@@ -196,7 +198,7 @@ func (ds Service) Save(ctx context.Context, sd svcs.SaveDevice, pk cryptotypes.P
 			Id:              verifyMethodID,
 			Type:            regtypes.Ed25519VerificationKey2018JSONLD,
 			Controller:      DID.String(),
-			PublicKeyBase58: base58.Encode(pk.PubKey().Bytes()),
+			PublicKeyBase58: base58.Encode(s.PubKey().Bytes()),
 		})
 
 		// Register DID in OBADA registry
@@ -212,7 +214,7 @@ func (ds Service) Save(ctx context.Context, sd svcs.SaveDevice, pk cryptotypes.P
 		}
 	}
 
-	documents, err := ds.handleDocuments(ctx, sd, pk.PubKey(), true)
+	documents, err := ds.handleDocuments(ctx, sd, s.PubKey(), true)
 	if err != nil {
 		return device, err
 	}
@@ -247,7 +249,7 @@ func (ds Service) Save(ctx context.Context, sd svcs.SaveDevice, pk cryptotypes.P
 		return device, err
 	}
 
-	signature, err := pk.Sign(hash[:])
+	signature, err := s.Sign(ctx, hash[:])
 	if err != nil {
 		return device, err
 	}
@@ -319,6 +321,15 @@ func (ds Service) Save(ctx context.Context, sd svcs.SaveDevice, pk cryptotypes.P
 
 // ImportDevice imports a device from a given DID
 func (ds Service) ImportDevice(ctx context.Context, nft types.NFT, address string) error {
+	return ds.importDevice(ctx, nft, address, false)
+}
+
+// ImportReadOnlyDevice imports a device owned by the watch-only account, the profile only tracks it
+func (ds Service) ImportReadOnlyDevice(ctx context.Context, nft types.NFT, address string) error {
+	return ds.importDevice(ctx, nft, address, true)
+}
+
+func (ds Service) importDevice(ctx context.Context, nft types.NFT, address string, readOnly bool) error {
 	userID := auth.GetUserID(ctx)
 	nftData := &types.NFTData{}
 	deviceDocuments := make([]svcs.DeviceDocument, 0)
@@ -379,6 +390,12 @@ func (ds Service) ImportDevice(ctx context.Context, nft types.NFT, address strin
 		Manufacturer: physicalAssetIdentifier.Manufacturer,
 		PartNumber:   physicalAssetIdentifier.PartNumber,
 		Address:      address,
+		ReadOnly:     readOnly,
+	}
+
+	// the device returned back keeps its ownership history
+	if existing, er := ds.GetByDID(ctx, nft.Id); er == nil {
+		device.History = existing.History
 	}
 
 	batch := ds.db.NewBatch()
@@ -415,6 +432,95 @@ func (ds Service) ImportDevice(ctx context.Context, nft types.NFT, address strin
 	return ds.eventBus.Emit(ctx, events.DeviceSaved, evt)
 }
 
+// HandOver replaces the authentication key of the device DID document with the registry key of the receiver,
+// the document change is signed by the current owner
+func (ds Service) HandOver(ctx context.Context, did, receiver string, s signer.Signer) error {
+	resp, err := ds.registry.GetPublicKey(ctx, &pbacc.GetPublicKeyRequest{
+		Address: receiver,
+	})
+	if err != nil {
+		return err
+	}
+
+	DIDDoc, err := ds.registry.Get(ctx, &diddoc.GetRequest{Did: did})
+	if err != nil {
+		return err
+	}
+
+	vms := make([]*diddoc.VerificationMethod, 0)
+	authID := fmt.Sprintf("%s#keys-1", did)
+
+	for _, doc := range DIDDoc.GetDocument().GetVerificationMethod() {
+		if doc.GetId() == authID {
+			doc.PublicKeyBase58 = resp.GetPubkey()
+		}
+
+		vms = append(vms, doc)
+	}
+
+	data := &diddoc.MsgSaveVerificationMethods_Data{
+		Did:                 did,
+		AuthenticationKeyId: authID,
+		Authentication:      DIDDoc.Document.Authentication,
+		VerificationMethods: vms,
+	}
+
+	hash, err := regapi.ProtoDeterministicChecksum(data)
+	if err != nil {
+		return err
+	}
+
+	signature, err := s.Sign(ctx, hash[:])
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.registry.SaveVerificationMethods(ctx, &diddoc.MsgSaveVerificationMethods{
+		Data:      data,
+		Signature: signature,
+	})
+
+	return err
+}
+
+// MarkTransferred marks the device as transferred to another owner and removes it from the owner devices lists.
+// The device is still available by DID together with the ownership history.
+func (ds Service) MarkTransferred(ctx context.Context, did string, h svcs.DeviceHistory) error {
+	userID := auth.GetUserID(ctx)
+
+	device, err := ds.GetByDID(ctx, did)
+	if err != nil {
+		return err
+	}
+
+	h.Event = svcs.DeviceStatusTransferred
+
+	device.Status = svcs.DeviceStatusTransferred
+	device.History = append(device.History, h)
+
+	deviceBytes, err := encoder.DataEncode(device)
+	if err != nil {
+		return err
+	}
+
+	batch := ds.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(makeDIDKey(userID, did), deviceBytes); err != nil {
+		return err
+	}
+
+	if err := batch.Delete(makeUSNKey(userID, device.Usn)); err != nil {
+		return err
+	}
+
+	if err := batch.Delete(makeAddressKey(userID, device.Address, did)); err != nil {
+		return err
+	}
+
+	return batch.WriteSync()
+}
+
 // GetByAddress fetch all devices owned by the given address
 func (ds Service) GetByAddress(ctx context.Context, address string) ([]svcs.Device, error) {
 	profileID := auth.GetUserID(ctx)
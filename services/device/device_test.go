@@ -9,6 +9,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/obada-foundation/client-helper/auth"
 	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/system/signer"
 	"github.com/obada-foundation/client-helper/system/validate"
 	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
 	"github.com/obada-foundation/registry/types"
@@ -199,11 +201,71 @@ func TestService_DeleteByAddress(t *testing.T) {
 
 }
 
-func GenKeys(t *testing.T) (cryptotypes.PrivKey, cryptotypes.PubKey, string) {
+func TestService_MarkTransferred(t *testing.T) {
+	service, registryClient, ctx, teardown := createTestService(t)
+	defer teardown()
+
+	privKey, _, addr := GenKeys(t)
+	_, _, receiver := GenKeys(t)
+
+	ctx = auth.SetClaims(ctx, auth.Claims{
+		UserID: "1",
+	})
+
+	registryClient.EXPECT().Get(gomock.Any(), gomock.Any()).Times(2).Return(&diddoc.GetResponse{}, nil)
+	registryClient.EXPECT().SaveMetadata(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+
+	d, err := service.Save(ctx, svcs.SaveDevice{
+		SerialNumber: "SN123456",
+		Manufacturer: "IBM",
+		PartNumber:   "PN123456",
+		Address:      addr,
+	}, privKey)
+	require.NoError(t, err, "Cannot save device")
+
+	err = service.MarkTransferred(ctx, d.DID, svcs.DeviceHistory{
+		Counterparty: receiver,
+		TxHash:       "0A1B",
+		Height:       42,
+	})
+	require.NoError(t, err, "Cannot mark device as transferred")
+
+	t.Log("Transferred device is removed from the owner lists")
+	{
+		devices, err := service.GetByAddress(ctx, addr)
+		require.NoError(t, err)
+		assert.Empty(t, devices)
+
+		_, err = service.GetByUSN(ctx, d.Usn)
+		require.ErrorIs(t, err, device.ErrDeviceNotExists)
+	}
+
+	t.Log("Transferred device keeps the history")
+	{
+		transferred, err := service.GetByDID(ctx, d.DID)
+		require.NoError(t, err)
+
+		assert.Equal(t, svcs.DeviceStatusTransferred, transferred.Status)
+		assert.Equal(t, []svcs.DeviceHistory{{
+			Event:        svcs.DeviceStatusTransferred,
+			Counterparty: receiver,
+			TxHash:       "0A1B",
+			Height:       42,
+		}}, transferred.History)
+	}
+
+	t.Log("Unknown device cannot be marked")
+	{
+		err := service.MarkTransferred(ctx, "did:obada:unknown", svcs.DeviceHistory{})
+		require.ErrorIs(t, err, device.ErrDeviceNotExists)
+	}
+}
+
+func GenKeys(t *testing.T) (signer.Signer, cryptotypes.PubKey, string) {
 	privKey := secp256k1.GenPrivKey()
 	pubKey := privKey.PubKey()
 	addr, err := sdk.AccAddressFromHexUnsafe(pubKey.Address().String())
 	require.NoError(t, err)
 
-	return privKey, pubKey, addr.String()
+	return signer.NewPrivKeySigner(privKey), pubKey, addr.String()
 }
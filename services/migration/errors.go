@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidReceiver assets are moved only to another account of the profile which key the profile controls
+	ErrInvalidReceiver = errors.New("assets can be moved only to another account of the profile that is not watch-only")
+
+	// ErrRotationNotExists the wallet of the profile was never rotated
+	ErrRotationNotExists = errors.New("wallet rotation doesn't exists")
+
+	// ErrRotationNotCompleted the new rotation cannot be started until the previous one is completed
+	ErrRotationNotCompleted = errors.New("wallet rotation is not completed, resume it")
+
+	// ErrRotationRunning the rotation is moving assets at the moment
+	ErrRotationRunning = errors.New("wallet rotation is running")
+
+	// ErrRotationCompleted the completed rotation cannot be resumed
+	ErrRotationCompleted = errors.New("wallet rotation is already completed")
+)
+
+// IsMigrationError errors that can send back to the client
+func IsMigrationError(err error) bool {
+	return errors.Is(err, ErrInvalidReceiver) ||
+		errors.Is(err, ErrRotationNotExists) ||
+		errors.Is(err, ErrRotationNotCompleted) ||
+		errors.Is(err, ErrRotationRunning) ||
+		errors.Is(err, ErrRotationCompleted)
+}
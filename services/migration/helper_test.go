@@ -0,0 +1,88 @@
+package migration_test
+
+import (
+	"context"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/services/migration"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/obada-foundation/client-helper/testutil"
+	registryclient "github.com/obada-foundation/registry/client/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+)
+
+//nolint:gochecknoinits //needed for the test
+func init() {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("obada", "obada"+sdk.PrefixPublic)
+	config.Seal()
+
+	if err := sdk.RegisterDenom("rohi", sdkmath.LegacyNewDec(1)); err != nil {
+		panic(err)
+	}
+
+	if err := sdk.RegisterDenom("obd", sdkmath.LegacyNewDec(1000000)); err != nil {
+		panic(err)
+	}
+}
+
+type testServices struct {
+	accountSvc *account.Service
+	svc        *migration.Service
+	nodeClient *mocks.Client
+	registry   *registryclient.MockClient
+	keyring    keyring.Keyring
+}
+
+func createTestService(t *testing.T) (testServices, context.Context, func()) {
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	logger, deleteLogFile := testutil.MakeLoger()
+
+	v, err := validate.NewValidator()
+	require.NoError(t, err)
+
+	eventBus, err := bus.NewBus(bus.Next(func() string { return "afakeid" }))
+	require.NoError(t, err)
+
+	eventBus.RegisterTopics(events.AccountDeleted, events.AccountCreated)
+
+	nodeClient := mocks.NewClient(t)
+	registry := registryclient.NewMockClient(gomock.NewController(t))
+	kr := keyring.NewInMemory(cosmostestutil.MakeTestEncodingConfig().Codec)
+
+	accountSvc := account.NewService(v, db.NewMemDB(), nodeClient, kr, eventBus)
+
+	svc := migration.NewService(migration.Config{
+		DB:            db.NewMemDB(),
+		AccountSvc:    accountSvc,
+		BlockchainSvc: blockchain.NewService(nodeClient, logger, ""),
+		DeviceSvc:     device.NewService(device.Config{Registry: registry}),
+		Logger:        logger,
+	})
+
+	_, err = accountSvc.RegisterProfile(ctx, svcs.NewProfile{ID: "1", Email: "jon.doe@supermail.com"})
+	require.NoError(t, err)
+
+	return testServices{
+		accountSvc: accountSvc,
+		svc:        svc,
+		nodeClient: nodeClient,
+		registry:   registry,
+		keyring:    kr,
+	}, ctx, deleteLogFile
+}
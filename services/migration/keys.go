@@ -0,0 +1,11 @@
+package migration
+
+import "fmt"
+
+const (
+	prefix = "wallet-rotations:"
+)
+
+func makeRotationKey(profileID string) []byte {
+	return []byte(fmt.Sprintf(prefix+"%s", profileID))
+}
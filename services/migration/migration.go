@@ -0,0 +1,174 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/services/device"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
+	db "github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+// Config is the migration service configuration
+type Config struct {
+	DB            db.DB
+	AccountSvc    *account.Service
+	BlockchainSvc *blockchain.Service
+	DeviceSvc     *device.Service
+	Logger        *zap.SugaredLogger
+}
+
+// Service moves NFTs and coins between accounts of the profile and rotates the profile wallet
+type Service struct {
+	db            db.DB
+	accountSvc    *account.Service
+	blockchainSvc *blockchain.Service
+	deviceSvc     *device.Service
+	logger        *zap.SugaredLogger
+
+	// running profiles which wallet rotation is moving assets at the moment
+	running *sync.Map
+}
+
+// NewService creates a new migration service
+func NewService(cfg Config) *Service {
+	return &Service{
+		db:            cfg.DB,
+		accountSvc:    cfg.AccountSvc,
+		blockchainSvc: cfg.BlockchainSvc,
+		deviceSvc:     cfg.DeviceSvc,
+		logger:        cfg.Logger,
+		running:       &sync.Map{},
+	}
+}
+
+// MoveAssets transfers all NFTs and coins of the account to another account of the profile. NFTs are handed over
+// the same way as the NFT transfer does, devices of the receiver are imported by the chain listener.
+// The fee of the last coins transfer stays on the account.
+func (s Service) MoveAssets(ctx context.Context, from, to string) (svcs.MovedAssets, error) {
+	moved := svcs.MovedAssets{
+		From:  from,
+		To:    to,
+		NFTs:  make([]string, 0),
+		Coins: sdk.NewCoins(),
+	}
+
+	if from == to || !s.accountSvc.HasAccount(ctx, to) {
+		return moved, ErrInvalidReceiver
+	}
+
+	receiver, err := s.accountSvc.GetProfileAccount(ctx, to)
+	if err != nil {
+		return moved, err
+	}
+
+	if receiver.WatchOnly {
+		return moved, ErrInvalidReceiver
+	}
+
+	sender, err := s.accountSvc.GetAccountSigner(ctx, from)
+	if err != nil {
+		return moved, err
+	}
+
+	// every transfer is committed before the next one, it is signed with the updated sequence
+	ctx = blockchain.WithCommit(ctx)
+
+	nfts, err := s.blockchainSvc.GetNFTByAddress(ctx, from)
+	if err != nil {
+		return moved, err
+	}
+
+	for _, nft := range nfts {
+		if err := s.moveNFT(ctx, nft.Id, to, sender); err != nil {
+			return moved, fmt.Errorf("cannot move NFT %s: %w", nft.Id, err)
+		}
+
+		moved.NFTs = append(moved.NFTs, nft.Id)
+	}
+
+	if err := s.moveCoins(ctx, from, to, sender, &moved); err != nil {
+		return moved, fmt.Errorf("cannot move coins: %w", err)
+	}
+
+	s.logger.Infow("account assets were moved", "from", from, "to", to, "nfts", len(moved.NFTs), "coins", moved.Coins.String())
+
+	return moved, nil
+}
+
+func (s Service) moveNFT(ctx context.Context, did, to string, sender signer.Signer) error {
+	if err := s.blockchainSvc.TransferNFT(ctx, did, to, sender); err != nil {
+		return err
+	}
+
+	return s.deviceSvc.HandOver(ctx, did, to, sender)
+}
+
+// moveCoins sends coins of every denom, the base denom pays fees, so it is sent the last
+// without the fee of its own transfer
+func (s Service) moveCoins(ctx context.Context, from, to string, sender signer.Signer, moved *svcs.MovedAssets) error {
+	coins, err := s.balance(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	for _, coin := range coins {
+		if coin.Denom == obadanode.BaseDenom {
+			continue
+		}
+
+		if err := s.blockchainSvc.Send(ctx, svcs.Account{Address: from}, to, coin, sender); err != nil {
+			return err
+		}
+
+		moved.Coins = moved.Coins.Add(coin)
+	}
+
+	coins, err = s.balance(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	amount := coins.AmountOf(obadanode.BaseDenom).Sub(sdkmath.NewInt(blockchain.MinGasLimit))
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	coin := sdk.NewCoin(obadanode.BaseDenom, amount)
+
+	if err := s.blockchainSvc.Send(ctx, svcs.Account{Address: from}, to, coin, sender); err != nil {
+		return err
+	}
+
+	moved.Coins = moved.Coins.Add(coin)
+
+	return nil
+}
+
+func (s Service) balance(ctx context.Context, address string) (sdk.Coins, error) {
+	balance, err := s.accountSvc.BalanceByAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	coins := sdk.NewCoins()
+
+	for _, b := range balance.Balances {
+		amount, ok := sdkmath.NewIntFromString(b.Amount)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s balance %q", b.Denom, b.Amount)
+		}
+
+		coins = coins.Add(sdk.NewCoin(b.Denom, amount))
+	}
+
+	return coins, nil
+}
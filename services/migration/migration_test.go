@@ -0,0 +1,169 @@
+package migration_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/golang/mock/gomock"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/migration"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+	pbacc "github.com/obada-foundation/registry/api/pb/v1/account"
+	"github.com/obada-foundation/registry/api/pb/v1/diddoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_MoveAssets(t *testing.T) {
+	ts, ctx, deferFn := createTestService(t)
+	defer deferFn()
+
+	accountSvc, svc, nodeClient := ts.accountSvc, ts.svc, ts.nodeClient
+
+	addresses := make([]string, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		privKey := secp256k1.GenPrivKey()
+
+		err := accountSvc.ImportAccount(ctx, hex.EncodeToString(privKey.Bytes()), "", account.Account{})
+		require.NoError(t, err)
+
+		addresses = append(addresses, sdk.AccAddress(privKey.PubKey().Address()).String())
+	}
+
+	from, to := addresses[0], addresses[1]
+
+	nodeClient.On("HasAccount", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	nodeClient.On("DenomsMetadata", mock.Anything).Return(nil, nil).Maybe()
+	nodeClient.On("GetNFTByAddress", mock.Anything, mock.Anything).Return(nil, nil)
+	nodeClient.On("AllBalances", mock.Anything, to).Return(sdk.NewCoins(), nil).Maybe()
+
+	t.Log("Test that assets are moved only to another account of the profile")
+	{
+		_, err := svc.MoveAssets(ctx, from, from)
+		require.ErrorIs(t, err, migration.ErrInvalidReceiver)
+
+		_, err = svc.MoveAssets(ctx, from, "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg")
+		require.ErrorIs(t, err, migration.ErrInvalidReceiver)
+	}
+
+	t.Log("Test that coins are moved and the base denom keeps the fee of the last transfer")
+	{
+		nodeClient.On("AllBalances", mock.Anything, from).
+			Return(sdk.NewCoins(sdk.NewInt64Coin("foo", 500), sdk.NewInt64Coin(obadanode.BaseDenom, 1000000)), nil).Once()
+		nodeClient.On("AllBalances", mock.Anything, from).
+			Return(sdk.NewCoins(sdk.NewInt64Coin(obadanode.BaseDenom, 900000)), nil).Once()
+		nodeClient.On("SendTx", mock.Anything, mock.Anything).Return(&ctypes.ResultBroadcastTx{Hash: []byte{1}}, nil).Twice()
+		nodeClient.On("WaitForTx", mock.Anything, mock.Anything).Return(&ctypes.ResultTx{}, nil).Twice()
+
+		moved, err := svc.MoveAssets(ctx, from, to)
+		require.NoError(t, err)
+
+		assert.Equal(t, from, moved.From)
+		assert.Equal(t, to, moved.To)
+		assert.Empty(t, moved.NFTs)
+		assert.Equal(t, "500foo,800000rohi", moved.Coins.String())
+	}
+}
+
+const walletMnemonic = "radio distance sweet artefact attack liar until video army raccoon green error ceiling size spread burst galaxy bottom cave rubber setup west address must"
+
+func TestService_WalletRotation(t *testing.T) {
+	ts, ctx, deferFn := createTestService(t)
+	defer deferFn()
+
+	from := "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg"
+	did := "did:obada:12345"
+
+	ts.nodeClient.On("HasAccount", mock.Anything, mock.Anything).Return(true, nil)
+	ts.nodeClient.On("DenomsMetadata", mock.Anything).Return(nil, nil)
+	ts.nodeClient.On("GetNFTByAddress", mock.Anything, from).Return(nil, nil).Once()
+	ts.nodeClient.On("GetNFTByAddress", mock.Anything, from).Return([]obadatypes.NFT{{Id: did}}, nil).Once()
+	ts.nodeClient.On("GetNFTByAddress", mock.Anything, from).Return(nil, nil)
+	ts.nodeClient.On("AllBalances", mock.Anything, from).Return(sdk.NewCoins(), nil).Once()
+	ts.nodeClient.On("AllBalances", mock.Anything, from).
+		Return(sdk.NewCoins(sdk.NewInt64Coin(obadanode.BaseDenom, 1000000)), nil).Twice()
+	ts.nodeClient.On("SendTx", mock.Anything, mock.Anything).Return(&ctypes.ResultBroadcastTx{Hash: []byte{1}}, nil).Twice()
+	ts.nodeClient.On("WaitForTx", mock.Anything, mock.Anything).Return(&ctypes.ResultTx{}, nil).Twice()
+
+	_, err := ts.accountSvc.NewWallet(ctx, walletMnemonic, false)
+	require.NoError(t, err)
+
+	ts.registry.EXPECT().GetPublicKey(gomock.Any(), gomock.Any()).Times(2).Return(&pbacc.GetPublicKeyResponse{}, nil)
+	ts.registry.EXPECT().Get(gomock.Any(), gomock.Any()).Times(2).Return(&diddoc.GetResponse{Document: &diddoc.DIDDocument{}}, nil)
+
+	gomock.InOrder(
+		ts.registry.EXPECT().SaveVerificationMethods(gomock.Any(), gomock.Any()).Return(nil, errors.New("registry is down")),
+		ts.registry.EXPECT().SaveVerificationMethods(gomock.Any(), gomock.Any()).Return(nil, nil),
+	)
+
+	waitFor := func(status string) svcs.WalletRotation {
+		var rot svcs.WalletRotation
+
+		require.Eventually(t, func() bool {
+			rot, err = ts.svc.WalletRotation(ctx)
+			return err == nil && rot.Status == status
+		}, 5*time.Second, 10*time.Millisecond)
+
+		return rot
+	}
+
+	t.Log("Test that the rotation stops with the NFT that was transferred but not handed over")
+	{
+		rot, err := ts.svc.StartWalletRotation(ctx)
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, rot.Mnemonic)
+		require.Len(t, rot.Accounts, 1)
+		assert.Equal(t, from, rot.Accounts[0].From)
+
+		rot = waitFor(svcs.WalletRotationFailed)
+
+		assert.Empty(t, rot.Mnemonic)
+		assert.Contains(t, rot.Error, "registry is down")
+		assert.Equal(t, []string{did}, rot.Accounts[0].HandOvers)
+		assert.False(t, rot.Accounts[0].Moved)
+
+		_, err = ts.svc.StartWalletRotation(ctx)
+		require.ErrorIs(t, err, migration.ErrRotationNotCompleted)
+
+		_, err = ts.accountSvc.NewAccounts(ctx, account.Account{}, 1)
+		require.ErrorIs(t, err, account.ErrWalletRotationInProgress)
+	}
+
+	t.Log("Test that the resumed rotation hands over the NFT, moves coins and replaces the wallet")
+	{
+		_, err := ts.svc.ResumeWalletRotation(ctx)
+		require.NoError(t, err)
+
+		rot := waitFor(svcs.WalletRotationCompleted)
+
+		acc := rot.Accounts[0]
+		assert.True(t, acc.Moved)
+		assert.Empty(t, acc.HandOvers)
+		assert.Equal(t, []string{did}, acc.NFTs)
+		assert.Equal(t, "900000rohi", acc.Coins.String())
+
+		key, err := ts.keyring.Key("1_0")
+		require.NoError(t, err)
+
+		addr, err := key.GetAddress()
+		require.NoError(t, err)
+		assert.Equal(t, acc.To, addr.String())
+
+		wallet, err := ts.accountSvc.GetWallet(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, walletMnemonic, wallet.Mnemonic)
+
+		_, err = ts.svc.ResumeWalletRotation(ctx)
+		require.ErrorIs(t, err, migration.ErrRotationCompleted)
+	}
+}
@@ -0,0 +1,276 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/account"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/signer"
+)
+
+// StartWalletRotation generates the new mnemonic, derives accounts matching HD accounts of the profile wallet
+// and moves NFTs and coins of every old account to the new one in the background. The old wallet is retired
+// when all accounts are moved. The mnemonic is returned only here, the progress is reported by WalletRotation.
+func (s Service) StartWalletRotation(ctx context.Context) (svcs.WalletRotation, error) {
+	profileID := auth.GetUserID(ctx)
+
+	rot, err := s.get(profileID)
+	if err != nil && !errors.Is(err, ErrRotationNotExists) {
+		return rot, err
+	}
+
+	if err == nil && rot.Status != svcs.WalletRotationCompleted {
+		return rot, ErrRotationNotCompleted
+	}
+
+	mnemonic, err := account.NewMnemonic()
+	if err != nil {
+		return svcs.WalletRotation{}, err
+	}
+
+	accounts, err := s.accountSvc.AddRotationWallet(ctx, mnemonic)
+	if errors.Is(err, account.ErrWalletRotationInProgress) {
+		// the rotation wallet was added, but the rotation was not saved, it continues with the stored mnemonic
+		// that can be revealed once the rotation is completed
+		mnemonic = ""
+		accounts, err = s.accountSvc.RotationAccounts(ctx)
+	}
+
+	if err != nil {
+		return svcs.WalletRotation{}, err
+	}
+
+	rot = svcs.WalletRotation{
+		Status:    svcs.WalletRotationRunning,
+		Accounts:  accounts,
+		StartedAt: time.Now().UTC(),
+	}
+
+	if err := s.run(ctx, rot); err != nil {
+		return rot, err
+	}
+
+	rot.Mnemonic = mnemonic
+
+	return rot, nil
+}
+
+// ResumeWalletRotation continues the failed or interrupted rotation from the account where it stopped
+func (s Service) ResumeWalletRotation(ctx context.Context) (svcs.WalletRotation, error) {
+	rot, err := s.WalletRotation(ctx)
+	if err != nil {
+		return rot, err
+	}
+
+	switch rot.Status {
+	case svcs.WalletRotationCompleted:
+		return rot, ErrRotationCompleted
+	case svcs.WalletRotationRunning:
+		return rot, ErrRotationRunning
+	}
+
+	rot.Status = svcs.WalletRotationRunning
+	rot.Error = ""
+
+	if err := s.run(ctx, rot); err != nil {
+		return rot, err
+	}
+
+	return rot, nil
+}
+
+// WalletRotation returns the progress of the profile wallet rotation, the rotation that was interrupted
+// by the shutdown is reported as failed
+func (s Service) WalletRotation(ctx context.Context) (svcs.WalletRotation, error) {
+	profileID := auth.GetUserID(ctx)
+
+	rot, err := s.get(profileID)
+	if err != nil {
+		return rot, err
+	}
+
+	if _, ok := s.running.Load(profileID); !ok && rot.Status == svcs.WalletRotationRunning {
+		rot.Status = svcs.WalletRotationFailed
+		rot.Error = "wallet rotation was interrupted"
+	}
+
+	return rot, nil
+}
+
+// run saves the rotation and moves assets in the background. Signers of accounts that are not moved yet
+// are unlocked here, so the signing passphrase of the request is verified before the rotation starts.
+func (s Service) run(ctx context.Context, rot svcs.WalletRotation) error {
+	profileID := auth.GetUserID(ctx)
+
+	if _, loaded := s.running.LoadOrStore(profileID, true); loaded {
+		return ErrRotationRunning
+	}
+
+	signers := make(map[string]signer.Signer, len(rot.Accounts))
+
+	for _, acc := range rot.Accounts {
+		if acc.Moved {
+			continue
+		}
+
+		sender, err := s.accountSvc.GetAccountSigner(ctx, acc.From)
+		if err != nil {
+			s.running.Delete(profileID)
+			return err
+		}
+
+		signers[acc.From] = sender
+	}
+
+	if err := s.save(profileID, &rot); err != nil {
+		s.running.Delete(profileID)
+		return err
+	}
+
+	// the rotation outlives the request, it keeps only the profile of the request
+	bgCtx := blockchain.WithCommit(auth.SetClaims(context.Background(), auth.GetClaims(ctx)))
+
+	// the progress is changed by the background rotation, while the caller responds with the initial state
+	job := rot
+	job.Accounts = append(make([]svcs.RotatedAccount, 0, len(rot.Accounts)), rot.Accounts...)
+
+	go func() {
+		defer s.running.Delete(profileID)
+
+		if err := s.rotate(bgCtx, profileID, &job, signers); err != nil {
+			job.Status = svcs.WalletRotationFailed
+			job.Error = err.Error()
+
+			s.logger.Errorw("wallet rotation failed", "profile", profileID, "error", err)
+		}
+
+		if err := s.save(profileID, &job); err != nil {
+			s.logger.Errorw("cannot save wallet rotation", "profile", profileID, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+func (s Service) rotate(ctx context.Context, profileID string, rot *svcs.WalletRotation, signers map[string]signer.Signer) error {
+	for i := range rot.Accounts {
+		acc := &rot.Accounts[i]
+
+		if acc.Moved {
+			continue
+		}
+
+		if err := s.rotateAccount(ctx, profileID, rot, acc, signers[acc.From]); err != nil {
+			return fmt.Errorf("cannot move assets of %s: %w", acc.From, err)
+		}
+	}
+
+	if err := s.accountSvc.CompleteWalletRotation(ctx); err != nil {
+		return fmt.Errorf("cannot retire the wallet: %w", err)
+	}
+
+	rot.Status = svcs.WalletRotationCompleted
+
+	s.logger.Infow("wallet was rotated", "profile", profileID, "accounts", len(rot.Accounts))
+
+	return nil
+}
+
+// rotateAccount moves assets of the account, the progress is saved after every step. NFTs that were transferred
+// on chain are kept as hand overs until their registry keys are rotated, the old account doesn't list them anymore.
+func (s Service) rotateAccount(ctx context.Context, profileID string, rot *svcs.WalletRotation, acc *svcs.RotatedAccount,
+	sender signer.Signer) error {
+	if err := s.handOver(ctx, profileID, rot, acc, sender); err != nil {
+		return err
+	}
+
+	nfts, err := s.blockchainSvc.GetNFTByAddress(ctx, acc.From)
+	if err != nil {
+		return err
+	}
+
+	for _, nft := range nfts {
+		if err := s.blockchainSvc.TransferNFT(ctx, nft.Id, acc.To, sender); err != nil {
+			return fmt.Errorf("cannot move NFT %s: %w", nft.Id, err)
+		}
+
+		acc.HandOvers = append(acc.HandOvers, nft.Id)
+
+		if err := s.save(profileID, rot); err != nil {
+			return err
+		}
+
+		if err := s.handOver(ctx, profileID, rot, acc, sender); err != nil {
+			return err
+		}
+	}
+
+	if err := s.moveCoins(ctx, acc.From, acc.To, sender, &acc.MovedAssets); err != nil {
+		return fmt.Errorf("cannot move coins: %w", err)
+	}
+
+	acc.Moved = true
+
+	return s.save(profileID, rot)
+}
+
+// handOver rotates registry keys of NFTs that were transferred to the new account
+func (s Service) handOver(ctx context.Context, profileID string, rot *svcs.WalletRotation, acc *svcs.RotatedAccount,
+	sender signer.Signer) error {
+	for len(acc.HandOvers) > 0 {
+		did := acc.HandOvers[0]
+
+		if err := s.deviceSvc.HandOver(ctx, did, acc.To, sender); err != nil {
+			return fmt.Errorf("cannot hand over NFT %s: %w", did, err)
+		}
+
+		acc.HandOvers = acc.HandOvers[1:]
+		acc.NFTs = append(acc.NFTs, did)
+
+		if err := s.save(profileID, rot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Service) get(profileID string) (svcs.WalletRotation, error) {
+	var rot svcs.WalletRotation
+
+	b, err := s.db.Get(makeRotationKey(profileID))
+	if err != nil {
+		return rot, err
+	}
+
+	if b == nil {
+		return rot, ErrRotationNotExists
+	}
+
+	if err := json.Unmarshal(b, &rot); err != nil {
+		return rot, err
+	}
+
+	return rot, nil
+}
+
+func (s Service) save(profileID string, rot *svcs.WalletRotation) error {
+	rot.UpdatedAt = time.Now().UTC()
+
+	b, err := json.Marshal(rot)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.SetSync(makeRotationKey(profileID), b); err != nil {
+		return fmt.Errorf("cannot save wallet rotation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package multisig
+
+import (
+	"errors"
+)
+
+var (
+	// ErrTxNotExists multisig transaction not exists
+	ErrTxNotExists = errors.New("multisig transaction doesn't exists")
+
+	// ErrNotCosigner the key is not co-signer of the multisig account
+	ErrNotCosigner = errors.New("key is not co-signer of the multisig account")
+
+	// ErrAlreadySigned the co-signer already signed the transaction
+	ErrAlreadySigned = errors.New("co-signer already signed the transaction")
+
+	// ErrInvalidSignature the signature doesn't match sign bytes of the transaction
+	ErrInvalidSignature = errors.New("invalid co-signer signature")
+
+	// ErrTxNotPending the transaction was already broadcasted
+	ErrTxNotPending = errors.New("multisig transaction is not pending")
+
+	// ErrThresholdNotMet not enough co-signers signed the transaction
+	ErrThresholdNotMet = errors.New("multisig transaction doesn't have enough signatures")
+
+	// ErrNoHandOver the transaction has no pending hand over of the registry key
+	ErrNoHandOver = errors.New("multisig transaction has no pending hand over")
+)
+
+// IsMultisigError errors that can send back to the client
+func IsMultisigError(err error) bool {
+	return errors.Is(err, ErrNotCosigner) ||
+		errors.Is(err, ErrAlreadySigned) ||
+		errors.Is(err, ErrInvalidSignature) ||
+		errors.Is(err, ErrTxNotPending) ||
+		errors.Is(err, ErrThresholdNotMet) ||
+		errors.Is(err, ErrNoHandOver)
+}
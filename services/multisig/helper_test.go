@@ -0,0 +1,12 @@
+package multisig_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+//nolint:gochecknoinits //needed for the test
+func init() {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("obada", "obada"+sdk.PrefixPublic)
+	config.Seal()
+}
@@ -0,0 +1,16 @@
+package multisig
+
+import "fmt"
+
+const (
+	prefix   = "multisig-txs:"
+	idPrefix = "multisig-tx-ids:"
+)
+
+func makeTxKey(address, id string) []byte {
+	return []byte(fmt.Sprintf(prefix+"%s:%s", address, id))
+}
+
+func makeTxIDKey(id string) []byte {
+	return []byte(idPrefix + id)
+}
@@ -0,0 +1,397 @@
+package multisig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/google/uuid"
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/signer"
+	db "github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+// Config is the multisig service configuration
+type Config struct {
+	DB            db.DB
+	BlockchainSvc *blockchain.Service
+	Logger        *zap.SugaredLogger
+}
+
+// Service keeps transactions of multisig accounts until co-signers of the threshold signed them.
+// Transactions are kept by the multisig account, so co-signers of different profiles sign the same transaction,
+// callers check that the profile owns or co-signs the multisig account.
+type Service struct {
+	db            db.DB
+	blockchainSvc *blockchain.Service
+	logger        *zap.SugaredLogger
+
+	// mu serializes signing, so concurrent co-signers don't overwrite signatures of each other
+	mu *sync.Mutex
+}
+
+// record is the stored transaction together with signatures of co-signers keyed by their addresses
+// and co-signers keyed by profiles that signed the transaction
+type record struct {
+	svcs.MultisigTx
+	Tx         obadanode.MultisigTx `json:"tx"`
+	Signatures map[string][]byte    `json:"signatures"`
+	Profiles   map[string]string    `json:"profiles"`
+}
+
+// NewService creates a new multisig service
+func NewService(cfg Config) *Service {
+	return &Service{
+		db:            cfg.DB,
+		blockchainSvc: cfg.BlockchainSvc,
+		logger:        cfg.Logger,
+		mu:            &sync.Mutex{},
+	}
+}
+
+// Propose builds the transaction of the multisig account, it is broadcasted once co-signers of the threshold signed it
+func (ms Service) Propose(ctx context.Context, pubKey *kmultisig.LegacyAminoPubKey, req blockchain.UnsignedTxRequest) (svcs.MultisigTx, error) {
+	mtx, err := ms.blockchainSvc.BuildMultisigTx(ctx, req, pubKey)
+	if err != nil {
+		return svcs.MultisigTx{}, err
+	}
+
+	rec := record{
+		MultisigTx: svcs.MultisigTx{
+			ID:        uuid.New().String(),
+			Address:   sdk.AccAddress(pubKey.Address()).String(),
+			Type:      req.Type,
+			DID:       req.Device.DID,
+			Receiver:  req.Receiver,
+			Threshold: uint(pubKey.Threshold),
+			Signers:   make([]string, 0),
+			SignBytes: mtx.SignBytes,
+			Status:    svcs.MultisigTxPending,
+			CreatedAt: time.Now().UTC(),
+		},
+		Tx:         mtx,
+		Signatures: make(map[string][]byte),
+		Profiles:   make(map[string]string),
+	}
+
+	if err := ms.save(rec); err != nil {
+		return svcs.MultisigTx{}, err
+	}
+
+	ms.logger.Infow("Multisig transaction was proposed", "id", rec.ID, "address", rec.Address, "type", rec.Type)
+
+	return rec.MultisigTx, nil
+}
+
+// Get fetches the multisig transaction
+func (ms Service) Get(_ context.Context, id string) (svcs.MultisigTx, error) {
+	rec, err := ms.get(id)
+	if err != nil {
+		return svcs.MultisigTx{}, err
+	}
+
+	return rec.MultisigTx, nil
+}
+
+// List fetches multisig transactions of the given multisig account, or of all multisig accounts
+// when the address is empty
+func (ms Service) List(_ context.Context, address string) ([]svcs.MultisigTx, error) {
+	txs := make([]svcs.MultisigTx, 0)
+
+	keyPrefix := []byte(prefix)
+	if address != "" {
+		keyPrefix = makeTxKey(address, "")
+	}
+
+	prefixDB := db.NewPrefixDB(ms.db, keyPrefix)
+
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return txs, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		var rec record
+
+		if err := json.Unmarshal(itr.Value(), &rec); err != nil {
+			return txs, err
+		}
+
+		txs = append(txs, rec.MultisigTx)
+	}
+
+	return txs, nil
+}
+
+// Sign signs the transaction by the co-signer that is the profile account, the caller checks that the profile
+// owns the co-signer
+func (ms Service) Sign(ctx context.Context, id string, pubKey *kmultisig.LegacyAminoPubKey, s signer.Signer) (svcs.MultisigTx, error) {
+	tx, err := ms.Get(ctx, id)
+	if err != nil {
+		return tx, err
+	}
+
+	signature, err := s.Sign(ctx, tx.SignBytes)
+	if err != nil {
+		return tx, err
+	}
+
+	return ms.AddProfileSignature(ctx, id, pubKey, s.PubKey(), signature)
+}
+
+// AddProfileSignature adds the signature of the co-signer key that the profile owns, every profile signs
+// the transaction by its own keys once
+func (ms Service) AddProfileSignature(ctx context.Context, id string, pubKey *kmultisig.LegacyAminoPubKey,
+	cosigner cryptotypes.PubKey, signature []byte,
+) (svcs.MultisigTx, error) {
+	return ms.addSignature(ctx, id, pubKey, cosigner, signature, true)
+}
+
+// AddSignature adds the signature of the external co-signer that signed sign bytes outside of client-helper,
+// the profile may submit signatures of several external co-signers
+func (ms Service) AddSignature(ctx context.Context, id string, pubKey *kmultisig.LegacyAminoPubKey, cosigner cryptotypes.PubKey,
+	signature []byte,
+) (svcs.MultisigTx, error) {
+	return ms.addSignature(ctx, id, pubKey, cosigner, signature, false)
+}
+
+// addSignature adds the co-signer signature, the transaction is broadcasted when signatures of the threshold
+// are collected
+func (ms Service) addSignature(ctx context.Context, id string, pubKey *kmultisig.LegacyAminoPubKey, cosigner cryptotypes.PubKey,
+	signature []byte, profileKey bool,
+) (svcs.MultisigTx, error) {
+	profileID := auth.GetUserID(ctx)
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	rec, err := ms.get(id)
+	if err != nil {
+		return svcs.MultisigTx{}, err
+	}
+
+	if err := checkAccount(rec, pubKey); err != nil {
+		return rec.MultisigTx, err
+	}
+
+	if rec.Status != svcs.MultisigTxPending {
+		return rec.MultisigTx, ErrTxNotPending
+	}
+
+	if signed, ok := rec.Profiles[profileID]; ok && profileKey {
+		return rec.MultisigTx, fmt.Errorf("%w: the profile signed the transaction by %s", ErrAlreadySigned, signed)
+	}
+
+	if !isCosigner(pubKey, cosigner) {
+		return rec.MultisigTx, ErrNotCosigner
+	}
+
+	address := sdk.AccAddress(cosigner.Address()).String()
+
+	if _, ok := rec.Signatures[address]; ok {
+		return rec.MultisigTx, ErrAlreadySigned
+	}
+
+	if !cosigner.VerifySignature(rec.SignBytes, signature) {
+		return rec.MultisigTx, ErrInvalidSignature
+	}
+
+	rec.Signatures[address] = signature
+	rec.Signers = append(rec.Signers, address)
+	if profileKey {
+		rec.Profiles[profileID] = address
+	}
+
+	if err := ms.save(rec); err != nil {
+		return rec.MultisigTx, err
+	}
+
+	if uint(len(rec.Signatures)) < rec.Threshold {
+		return rec.MultisigTx, nil
+	}
+
+	return ms.broadcast(ctx, rec, pubKey)
+}
+
+// Broadcast broadcasts the transaction that has signatures of the threshold, e.g. when the previous attempt failed
+func (ms Service) Broadcast(ctx context.Context, id string, pubKey *kmultisig.LegacyAminoPubKey) (svcs.MultisigTx, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	rec, err := ms.get(id)
+	if err != nil {
+		return svcs.MultisigTx{}, err
+	}
+
+	if err := checkAccount(rec, pubKey); err != nil {
+		return rec.MultisigTx, err
+	}
+
+	if rec.Status != svcs.MultisigTxPending {
+		return rec.MultisigTx, ErrTxNotPending
+	}
+
+	if uint(len(rec.Signatures)) < rec.Threshold {
+		return rec.MultisigTx, ErrThresholdNotMet
+	}
+
+	return ms.broadcast(ctx, rec, pubKey)
+}
+
+// Cancel deletes the pending transaction
+func (ms Service) Cancel(_ context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	rec, err := ms.get(id)
+	if err != nil {
+		return err
+	}
+
+	if rec.Status != svcs.MultisigTxPending {
+		return ErrTxNotPending
+	}
+
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Delete(makeTxKey(rec.Address, id)); err != nil {
+		return err
+	}
+
+	if err := batch.Delete(makeTxIDKey(id)); err != nil {
+		return err
+	}
+
+	return batch.WriteSync()
+}
+
+func (ms Service) broadcast(ctx context.Context, rec record, pubKey *kmultisig.LegacyAminoPubKey) (svcs.MultisigTx, error) {
+	hash, err := ms.blockchainSvc.BroadcastMultisigTx(ctx, pubKey, rec.Tx, rec.Signatures)
+	if err != nil {
+		return rec.MultisigTx, fmt.Errorf("cannot broadcast multisig transaction %s: %w", rec.ID, err)
+	}
+
+	rec.Status = svcs.MultisigTxBroadcasted
+	rec.TxHash = hash
+
+	// the registry key of the transferred NFT is rotated by the profile that owns the multisig account
+	rec.HandOverPending = rec.Type == blockchain.TxMsgTransfer
+
+	if err := ms.save(rec); err != nil {
+		return rec.MultisigTx, err
+	}
+
+	ms.logger.Infow("Multisig transaction was broadcasted", "id", rec.ID, "address", rec.Address, "hash", hash)
+
+	return rec.MultisigTx, nil
+}
+
+// CompleteHandOver marks the registry key of the transferred NFT as rotated
+func (ms Service) CompleteHandOver(_ context.Context, id string) (svcs.MultisigTx, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	rec, err := ms.get(id)
+	if err != nil {
+		return svcs.MultisigTx{}, err
+	}
+
+	if !rec.HandOverPending {
+		return rec.MultisigTx, ErrNoHandOver
+	}
+
+	rec.HandOverPending = false
+
+	if err := ms.save(rec); err != nil {
+		return rec.MultisigTx, err
+	}
+
+	return rec.MultisigTx, nil
+}
+
+// get fetches the transaction by the multisig account that is indexed by the transaction ID
+func (ms Service) get(id string) (record, error) {
+	var rec record
+
+	address, err := ms.db.Get(makeTxIDKey(id))
+	if err != nil {
+		return rec, err
+	}
+
+	if address == nil {
+		return rec, ErrTxNotExists
+	}
+
+	b, err := ms.db.Get(makeTxKey(string(address), id))
+	if err != nil {
+		return rec, err
+	}
+
+	if b == nil {
+		return rec, ErrTxNotExists
+	}
+
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return rec, err
+	}
+
+	if rec.Profiles == nil {
+		rec.Profiles = make(map[string]string)
+	}
+
+	return rec, nil
+}
+
+func (ms Service) save(rec record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(makeTxKey(rec.Address, rec.ID), b); err != nil {
+		return err
+	}
+
+	if err := batch.Set(makeTxIDKey(rec.ID), []byte(rec.Address)); err != nil {
+		return err
+	}
+
+	if err := batch.WriteSync(); err != nil {
+		return fmt.Errorf("cannot save multisig transaction: %w", err)
+	}
+
+	return nil
+}
+
+// checkAccount checks that the public key is the key of the multisig account that proposed the transaction
+func checkAccount(rec record, pubKey *kmultisig.LegacyAminoPubKey) error {
+	if sdk.AccAddress(pubKey.Address()).String() != rec.Address {
+		return fmt.Errorf("%w: transaction %s belongs to %s", ErrNotCosigner, rec.ID, rec.Address)
+	}
+
+	return nil
+}
+
+func isCosigner(pubKey *kmultisig.LegacyAminoPubKey, cosigner cryptotypes.PubKey) bool {
+	for _, pk := range pubKey.GetPubKeys() {
+		if pk.Equals(cosigner) {
+			return true
+		}
+	}
+
+	return false
+}
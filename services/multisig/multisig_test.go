@@ -0,0 +1,219 @@
+package multisig_test
+
+import (
+	"context"
+	"testing"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/obada-foundation/client-helper/auth"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/blockchain"
+	"github.com/obada-foundation/client-helper/services/multisig"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/client-helper/system/obadanode/mocks"
+	"github.com/obada-foundation/client-helper/system/signer"
+	"github.com/obada-foundation/client-helper/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+)
+
+const receiverAddress = "obada1yxxnd624tgwqm3eyv5smdvjrrydfh9h943qptg"
+
+func TestService(t *testing.T) {
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+	cosignerCtx := auth.SetClaims(context.Background(), auth.Claims{UserID: "2"})
+
+	logger, deleteLogFile := testutil.MakeLoger()
+	defer deleteLogFile()
+
+	nodeClient := mocks.NewClient(t)
+
+	svc := multisig.NewService(multisig.Config{
+		DB:            db.NewMemDB(),
+		BlockchainSvc: blockchain.NewService(nodeClient, logger, ""),
+		Logger:        logger,
+	})
+
+	privKeys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKeys := make([]cryptotypes.PubKey, 0, len(privKeys))
+
+	for _, pk := range privKeys {
+		pubKeys = append(pubKeys, pk.PubKey())
+	}
+
+	pubKey := kmultisig.NewLegacyAminoPubKey(2, pubKeys)
+	address := sdk.AccAddress(pubKey.Address()).String()
+
+	mtx := obadanode.MultisigTx{
+		TxBytes:   []byte("unsigned"),
+		SignBytes: []byte("sign bytes"),
+		SignDoc:   obadanode.SignDoc{ChainID: "obada-testnet", AccountNumber: 7, Sequence: 3},
+	}
+
+	nodeClient.On("HasAccount", mock.Anything, address).Return(true, nil)
+	nodeClient.On("BuildMultisigTx", mock.Anything, pubKey, mock.Anything).Return(mtx, nil)
+
+	var tx svcs.MultisigTx
+
+	t.Log("Test the transfer of the multisig account is proposed")
+	{
+		var err error
+
+		tx, err = svc.Propose(ctx, pubKey, blockchain.UnsignedTxRequest{
+			Type:     blockchain.TxMsgTransfer,
+			Receiver: receiverAddress,
+			Device:   svcs.Device{DID: "did:obada:12345"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, address, tx.Address)
+		assert.Equal(t, uint(2), tx.Threshold)
+		assert.Equal(t, svcs.MultisigTxPending, tx.Status)
+		assert.Equal(t, mtx.SignBytes, tx.SignBytes)
+		assert.Empty(t, tx.Signers)
+	}
+
+	t.Log("Test the profile co-signer signs the transaction")
+	{
+		var err error
+
+		tx, err = svc.Sign(ctx, tx.ID, pubKey, signer.NewPrivKeySigner(privKeys[0]))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{sdk.AccAddress(pubKeys[0].Address()).String()}, tx.Signers)
+		assert.Equal(t, svcs.MultisigTxPending, tx.Status)
+
+		_, err = svc.Sign(ctx, tx.ID, pubKey, signer.NewPrivKeySigner(privKeys[0]))
+		require.ErrorIs(t, err, multisig.ErrAlreadySigned)
+	}
+
+	t.Log("Test the profile cannot sign the transaction by the second co-signer")
+	{
+		_, err := svc.Sign(ctx, tx.ID, pubKey, signer.NewPrivKeySigner(privKeys[1]))
+		require.ErrorIs(t, err, multisig.ErrAlreadySigned)
+	}
+
+	t.Log("Test keys that are not co-signers and invalid signatures are rejected")
+	{
+		_, err := svc.Sign(cosignerCtx, tx.ID, pubKey, signer.NewPrivKeySigner(secp256k1.GenPrivKey()))
+		require.ErrorIs(t, err, multisig.ErrNotCosigner)
+
+		_, err = svc.AddSignature(cosignerCtx, tx.ID, pubKey, pubKeys[2], []byte("forged"))
+		require.ErrorIs(t, err, multisig.ErrInvalidSignature)
+
+		_, err = svc.Broadcast(ctx, tx.ID, pubKey)
+		require.ErrorIs(t, err, multisig.ErrThresholdNotMet)
+	}
+
+	t.Log("Test the co-signer of another profile signs the same transaction and reaches the threshold")
+	{
+		stored, err := svc.Get(cosignerCtx, tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tx, stored)
+
+		txs, err := svc.List(cosignerCtx, address)
+		require.NoError(t, err)
+		assert.Len(t, txs, 1)
+
+		nodeClient.On("BroadcastMultisigTx", mock.Anything, pubKey, mtx, mock.MatchedBy(func(sigs map[string][]byte) bool {
+			return len(sigs) == 2
+		})).Return(&ctypes.ResultBroadcastTx{Hash: []byte{0xAB}}, nil).Once()
+
+		tx, err = svc.Sign(cosignerCtx, tx.ID, pubKey, signer.NewPrivKeySigner(privKeys[2]))
+		require.NoError(t, err)
+
+		assert.Equal(t, svcs.MultisigTxBroadcasted, tx.Status)
+		assert.Equal(t, "AB", tx.TxHash)
+		assert.True(t, tx.HandOverPending)
+		assert.Equal(t, []string{
+			sdk.AccAddress(pubKeys[0].Address()).String(),
+			sdk.AccAddress(pubKeys[2].Address()).String(),
+		}, tx.Signers)
+
+		stored, err = svc.Get(ctx, tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tx, stored)
+	}
+
+	t.Log("Test the hand over of the transferred NFT is completed once")
+	{
+		var err error
+
+		tx, err = svc.CompleteHandOver(ctx, tx.ID)
+		require.NoError(t, err)
+		assert.False(t, tx.HandOverPending)
+
+		_, err = svc.CompleteHandOver(ctx, tx.ID)
+		require.ErrorIs(t, err, multisig.ErrNoHandOver)
+	}
+
+	t.Log("Test the broadcasted transaction cannot be signed or canceled")
+	{
+		_, err := svc.Sign(ctx, tx.ID, pubKey, signer.NewPrivKeySigner(privKeys[1]))
+		require.ErrorIs(t, err, multisig.ErrTxNotPending)
+
+		require.ErrorIs(t, svc.Cancel(ctx, tx.ID), multisig.ErrTxNotPending)
+	}
+
+	t.Log("Test pending transactions are listed by the account and canceled")
+	{
+		pending, err := svc.Propose(ctx, pubKey, blockchain.UnsignedTxRequest{
+			Type:   blockchain.TxMsgMint,
+			Device: svcs.Device{DID: "did:obada:67890"},
+		})
+		require.NoError(t, err)
+
+		txs, err := svc.List(ctx, address)
+		require.NoError(t, err)
+		assert.Len(t, txs, 2)
+
+		txs, err = svc.List(ctx, receiverAddress)
+		require.NoError(t, err)
+		assert.Empty(t, txs)
+
+		require.NoError(t, svc.Cancel(ctx, pending.ID))
+
+		_, err = svc.Get(ctx, pending.ID)
+		require.ErrorIs(t, err, multisig.ErrTxNotExists)
+	}
+
+	t.Log("Test the profile signs by its own key and submits the signature of the external co-signer")
+	{
+		mixed, err := svc.Propose(ctx, pubKey, blockchain.UnsignedTxRequest{
+			Type:   blockchain.TxMsgMint,
+			Device: svcs.Device{DID: "did:obada:13579"},
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Sign(ctx, mixed.ID, pubKey, signer.NewPrivKeySigner(privKeys[0]))
+		require.NoError(t, err)
+
+		nodeClient.On("BroadcastMultisigTx", mock.Anything, pubKey, mtx, mock.MatchedBy(func(sigs map[string][]byte) bool {
+			return len(sigs) == 2
+		})).Return(&ctypes.ResultBroadcastTx{Hash: []byte{0xCD}}, nil).Once()
+
+		signature, err := privKeys[1].Sign(mixed.SignBytes)
+		require.NoError(t, err)
+
+		mixed, err = svc.AddSignature(ctx, mixed.ID, pubKey, pubKeys[1], signature)
+		require.NoError(t, err)
+
+		assert.Equal(t, svcs.MultisigTxBroadcasted, mixed.Status)
+		assert.Equal(t, "CD", mixed.TxHash)
+		assert.False(t, mixed.HandOverPending)
+	}
+
+	t.Log("Test the transaction of another multisig account is rejected")
+	{
+		other := kmultisig.NewLegacyAminoPubKey(1, pubKeys)
+
+		_, err := svc.Broadcast(ctx, tx.ID, other)
+		require.ErrorIs(t, err, multisig.ErrNotCosigner)
+	}
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"encoding/json"
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -16,6 +19,11 @@ type Profile struct {
 	Email string `json:"email"`
 }
 
+// SigningPassphrase passphrase or PIN that protects signing with the profile accounts
+type SigningPassphrase struct {
+	Passphrase string `json:"passphrase" validate:"required,min=4,max=128"`
+}
+
 // MasterKey stores master key, needs to be removed once we stop storing aster key
 type MasterKey struct {
 	ID  string `json:"id"`
@@ -26,27 +34,256 @@ type MasterKey struct {
 type Wallet struct {
 	Mnemonic     string `json:"-"`
 	AccountIndex uint   `json:"-"`
+
+	// Signer is the name of the wallet on the remote signer, the mnemonic is kept by the signer then
+	Signer string `json:"-"`
 }
 
 // ProfileAccounts stores all accounts separated on accout types
 type ProfileAccounts struct {
 	HDAccounts       []Account `json:"hd_accounts"`
 	ImportedAccounts []Account `json:"imported_accounts"`
+	MultisigAccounts []Account `json:"multisig_accounts"`
+	WatchAccounts    []Account `json:"watch_accounts"`
+}
+
+// WithTag returns accounts tagged with the given tag
+func (pa ProfileAccounts) WithTag(tag string) ProfileAccounts {
+	filter := func(accounts []Account) []Account {
+		tagged := make([]Account, 0, len(accounts))
+
+		for _, acc := range accounts {
+			if acc.HasTag(tag) {
+				tagged = append(tagged, acc)
+			}
+		}
+
+		return tagged
+	}
+
+	return ProfileAccounts{
+		HDAccounts:       filter(pa.HDAccounts),
+		ImportedAccounts: filter(pa.ImportedAccounts),
+		MultisigAccounts: filter(pa.MultisigAccounts),
+		WatchAccounts:    filter(pa.WatchAccounts),
+	}
 }
 
 // Account client helper account
 type Account struct {
-	Name      string      `json:"name"`
-	PublicKey string      `json:"pub_key"`
-	Address   string      `json:"address"`
-	Balance   sdk.DecCoin `json:"balance"`
-	NFTsCount uint        `json:"nft_count"`
+	Name              string        `json:"name"`
+	Description       string        `json:"description"`
+	Tags              []string      `json:"tags"`
+	DefaultForMinting bool          `json:"default_for_minting"`
+	CreatedAt         *time.Time    `json:"created_at,omitempty"`
+	PublicKey         string        `json:"pub_key"`
+	Address           string        `json:"address"`
+	Balance           sdk.DecCoin   `json:"balance"`
+	Balances          []CoinBalance `json:"balances"`
+	NFTsCount         uint          `json:"nft_count"`
+	Multisig          *MultisigInfo `json:"multisig,omitempty"`
+
+	// WatchOnly the profile tracks the account, but doesn't control its key
+	WatchOnly bool `json:"watch_only"`
+}
+
+// NewWatchAccount watch-only account of the address which key the profile doesn't control, e.g. partner or cold wallet
+type NewWatchAccount struct {
+	Address string `json:"address" validate:"required"`
+	Name    string `json:"account_name"`
+}
+
+// HasTag returns true when the account is tagged with the given tag
+func (a Account) HasTag(tag string) bool {
+	for _, t := range a.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateAccount changes of the account metadata, fields that are not set stay the same
+type UpdateAccount struct {
+	Name        *string `json:"account_name" validate:"omitempty,max=128"`
+	Description *string `json:"description" validate:"omitempty,max=512"`
+
+	// Tags replace tags of the account, the empty list removes them
+	Tags []string `json:"tags" validate:"omitempty,max=32,dive,required,max=64"`
+
+	// DefaultForMinting makes the account the profile minting account, devices are saved to it by default
+	DefaultForMinting *bool `json:"default_for_minting"`
+}
+
+// AccountDeletionCheck on-chain assets of the account, the profile cannot move them once the account is deleted
+type AccountDeletionCheck struct {
+	Address   string        `json:"address"`
+	Balances  []CoinBalance `json:"balances"`
+	NFTsCount uint          `json:"nft_count"`
+
+	// Exported the private key was exported, so the assets stay reachable by the exported key
+	Exported bool `json:"exported"`
+
+	// Deletable the account holds no assets or its key was exported, otherwise the deletion has to be forced
+	Deletable bool `json:"deletable"`
+}
+
+// MovedAssets NFTs and coins moved from one account to another
+type MovedAssets struct {
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	NFTs  []string  `json:"nfts"`
+	Coins sdk.Coins `json:"coins"`
+}
+
+// Wallet rotation statuses
+const (
+	WalletRotationRunning   = "running"
+	WalletRotationFailed    = "failed"
+	WalletRotationCompleted = "completed"
+)
+
+// WalletRotation moves assets of every HD account of the profile wallet to the account with the same index
+// of the new wallet, the old wallet is retired when all accounts are moved
+type WalletRotation struct {
+	// Mnemonic of the new wallet, it is returned only when the rotation is started
+	Mnemonic  string           `json:"mnemonic,omitempty"`
+	Status    string           `json:"status"`
+	Accounts  []RotatedAccount `json:"accounts"`
+	Error     string           `json:"error,omitempty"`
+	StartedAt time.Time        `json:"started_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// RotatedAccount progress of moving assets of the HD account to the account of the new wallet
+type RotatedAccount struct {
+	MovedAssets
+	Index uint `json:"index"`
+
+	// HandOvers NFTs that were transferred on chain, but their registry keys are not rotated yet
+	HandOvers []string `json:"hand_overs"`
+	Moved     bool     `json:"moved"`
+}
+
+// PubKeyBundle public part of the account key, it is exported without the private key
+type PubKeyBundle struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	PubKey  string `json:"pub_key"`
+}
+
+// MultisigInfo co-signers of the multisig account
+type MultisigInfo struct {
+	Threshold uint             `json:"threshold"`
+	Members   []MultisigMember `json:"members"`
+}
+
+// MultisigMember co-signer of the multisig account
+type MultisigMember struct {
+	Address string `json:"address"`
+	PubKey  string `json:"pub_key"`
+}
+
+// Multisig transaction statuses
+const (
+	MultisigTxPending     = "pending"
+	MultisigTxBroadcasted = "broadcasted"
+)
+
+// MultisigTx is a transaction of the multisig account that waits for approvals of co-signers,
+// external co-signers sign SignBytes by their secp256k1 keys. HandOverPending is set when the NFT was
+// transferred, but the profile that owns the multisig account did not rotate its registry key yet.
+type MultisigTx struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Type      string    `json:"type"`
+	DID       string    `json:"did,omitempty"`
+	Receiver  string    `json:"receiver,omitempty"`
+	Threshold uint      `json:"threshold"`
+	Signers   []string  `json:"signers"`
+	SignBytes []byte    `json:"sign_bytes"`
+	Status    string    `json:"status"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	HandOverPending bool `json:"hand_over_pending,omitempty"`
+}
+
+// NewMultisig multisig account made of profile accounts and external public keys
+type NewMultisig struct {
+	Name      string `json:"name"`
+	Threshold uint   `json:"threshold" validate:"required,min=1"`
+
+	// Addresses co-signers that are accounts of the profile
+	Addresses []string `json:"addresses"`
+
+	// PubKeys hex encoded secp256k1 public keys of external co-signers
+	PubKeys []string `json:"pub_keys"`
 }
 
 // Balance account balance
 type Balance struct {
-	Address string      `json:"address"`
-	Balance sdk.DecCoin `json:"balance"`
+	Address  string        `json:"address"`
+	Balance  sdk.DecCoin   `json:"balance"`
+	Balances []CoinBalance `json:"balances"`
+}
+
+// CoinBalance balance of a single denom in the base and display units
+type CoinBalance struct {
+	Denom   string      `json:"denom"`
+	Amount  string      `json:"amount"`
+	Display sdk.DecCoin `json:"display"`
+}
+
+// FeeAllowance fee allowance granted by the sponsor to the account
+type FeeAllowance struct {
+	Granter    string     `json:"granter"`
+	Grantee    string     `json:"grantee"`
+	SpendLimit sdk.Coins  `json:"spend_limit"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// AuthzGrant permission to execute messages of given type on behalf of the granter
+type AuthzGrant struct {
+	Granter    string     `json:"granter"`
+	Grantee    string     `json:"grantee"`
+	MsgType    string     `json:"msg_type"`
+	MsgTypeURL string     `json:"msg_type_url"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// AuthzGrants grants given and received by the account
+type AuthzGrants struct {
+	Granted  []AuthzGrant `json:"granted"`
+	Received []AuthzGrant `json:"received"`
+}
+
+// TxHistory paginated list of account transactions
+type TxHistory struct {
+	Txs     []Tx `json:"txs"`
+	Total   int  `json:"total"`
+	Page    int  `json:"page"`
+	PerPage int  `json:"per_page"`
+}
+
+// Tx decoded blockchain transaction
+type Tx struct {
+	Hash     string  `json:"hash"`
+	Height   int64   `json:"height"`
+	Code     uint32  `json:"code"`
+	Memo     string  `json:"memo"`
+	Messages []TxMsg `json:"messages"`
+}
+
+// TxMsg decoded transaction message
+type TxMsg struct {
+	Type     string    `json:"type"`
+	Sender   string    `json:"sender"`
+	Receiver string    `json:"receiver,omitempty"`
+	Amount   sdk.Coins `json:"amount,omitempty"`
+	DIDs     []string  `json:"dids,omitempty"`
+	URIHash  string    `json:"uri_hash,omitempty"`
 }
 
 // SaveDeviceDocument request data for saving device documents
@@ -95,6 +332,22 @@ type Device struct {
 	PartNumber   string           `json:"part_number"`
 	Documents    []DeviceDocument `json:"documents"`
 	Address      string           `json:"address"`
+	Status       string           `json:"status,omitempty"`
+	History      []DeviceHistory  `json:"history,omitempty"`
+
+	// ReadOnly the device is owned by the watch-only account, so it cannot be minted or transferred
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// DeviceStatusTransferred the device NFT was transferred to another owner
+const DeviceStatusTransferred = "transferred"
+
+// DeviceHistory is a change of the device ownership
+type DeviceHistory struct {
+	Event        string `json:"event"`
+	Counterparty string `json:"counterparty"`
+	TxHash       string `json:"tx_hash"`
+	Height       int64  `json:"height"`
 }
 
 // SendNFT request data for sending NFT
@@ -106,3 +359,93 @@ type SendNFT struct {
 type MintBatchNFT struct {
 	Nfts []string `json:"nfts"`
 }
+
+// SaveWebhook request data for registering a webhook
+type SaveWebhook struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=account.created account.deleted device.saved nft.minted nft.transfered nft.metadata.updated"` //nolint:lll //list of events
+}
+
+// Webhook is the profile URL that receives events
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookDelivery is the attempt to deliver the event to the webhook
+type WebhookDelivery struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  int             `json:"response_code,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Audit entry results
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEntry is the record of the state-changing operation
+type AuditEntry struct {
+	ID        string    `json:"id"`
+	Subject   string    `json:"subject"`
+	ProfileID string    `json:"profile_id"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	TraceID   string    `json:"trace_id"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditFilter filters of the audit log query, empty fields match any entry
+type AuditFilter struct {
+	Action string
+	Target string
+	Result string
+	From   time.Time
+	To     time.Time
+	Limit  int
+}
+
+// EventFailure is the event that was not handled after all attempts, it is kept for the manual retry
+type EventFailure struct {
+	EventID    string          `json:"event_id"`
+	Event      string          `json:"event"`
+	Handler    string          `json:"handler"`
+	ProfileID  string          `json:"profile_id,omitempty"`
+	Data       json.RawMessage `json:"data"`
+	Attempts   int             `json:"attempts"`
+	Error      string          `json:"error"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	FailedAt   time.Time       `json:"failed_at"`
+}
+
+// RetryEvents request data for retrying failed events, empty list retries all failures
+type RetryEvents struct {
+	EventIDs []string `json:"event_ids"`
+}
+
+// RetriedEvents is the result of the failed events retry
+type RetriedEvents struct {
+	Queued int `json:"queued"`
+}
@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/validate"
+)
+
+// Headers of the webhook request
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the HMAC-SHA256 signature of the timestamp and the body joined with a dot.
+// Receivers compare it with the X-Webhook-Signature header to verify the delivery.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// attempt sends the delivery and records the result, failed deliveries are rescheduled
+// with exponential backoff until the max attempts is reached. The status of the delivery is returned.
+func (ws Service) attempt(ctx context.Context, key []byte, d svcs.WebhookDelivery) (string, error) {
+	profileID := strings.TrimSuffix(strings.TrimPrefix(string(key), deliveryPrefix), ":"+d.WebhookID+":"+d.ID)
+
+	webhook, err := ws.get(profileID, d.WebhookID)
+	if err != nil {
+		if !errors.Is(err, ErrWebhookNotExists) {
+			return d.Status, err
+		}
+
+		// webhook was deleted, the delivery is left in the log only
+		d.Status = svcs.WebhookDeliveryDead
+		d.LastError = err.Error()
+	} else {
+		ws.send(ctx, webhook, &d)
+
+		// the attempt was interrupted by the shutdown, it is repeated on the next run
+		if ctx.Err() != nil {
+			return d.Status, ctx.Err()
+		}
+	}
+
+	batch := ws.db.NewBatch()
+	defer batch.Close()
+
+	if err := ws.saveDelivery(batch, profileID, d); err != nil {
+		return d.Status, err
+	}
+
+	return d.Status, batch.WriteSync()
+}
+
+func (ws Service) send(ctx context.Context, webhook svcs.Webhook, d *svcs.WebhookDelivery) {
+	now := time.Now().UTC()
+
+	d.Attempts++
+	d.UpdatedAt = now
+
+	code, err := ws.post(ctx, webhook, d)
+
+	d.ResponseCode = code
+
+	if err == nil {
+		d.Status = svcs.WebhookDeliveryDelivered
+		d.LastError = ""
+		d.NextAttemptAt = time.Time{}
+
+		return
+	}
+
+	d.LastError = err.Error()
+
+	if d.Attempts >= ws.maxAttempts {
+		d.Status = svcs.WebhookDeliveryDead
+		d.NextAttemptAt = time.Time{}
+
+		ws.logger.Warnw("webhook delivery was dead-lettered", "webhook", webhook.ID, "delivery", d.ID, "error", err)
+
+		return
+	}
+
+	d.NextAttemptAt = now.Add(ws.backoff(d.Attempts))
+}
+
+func (ws Service) post(ctx context.Context, webhook svcs.Webhook, d *svcs.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, d.Payload))
+
+	resp, err := ws.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// checkURL requires https for hosts that are not allowed explicitly, addresses are checked when the delivery is sent,
+// so hosts that resolve to private addresses later are rejected too
+func (ws Service) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme == "https" || ws.allowedHosts[strings.ToLower(u.Hostname())] {
+		return nil
+	}
+
+	return validate.FieldErrors{
+		validate.FieldError{
+			Field: "url",
+			Error: "url must use https",
+		},
+	}
+}
+
+// newHTTPClient returns the client that connects only to public addresses, except allowed hosts.
+// Redirects are not followed, the receiver responds to the webhook URL itself.
+func (ws Service) newHTTPClient() *http.Client {
+	public := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		},
+	}
+
+	allowed := &net.Dialer{Timeout: requestTimeout}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if ws.allowedHosts[strings.ToLower(host)] {
+			return allowed.DialContext(ctx, network, address)
+		}
+
+		return public.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkAddress rejects the resolved address that is not public, e.g. the loopback, private network or cloud metadata
+func checkAddress(address string) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := addrPort.Addr().Unmap()
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	return nil
+}
+
+// backoff returns delay before the next attempt
+func (ws Service) backoff(attempts int) time.Duration {
+	delay := ws.minBackoff
+
+	for i := 1; i < attempts; i++ {
+		if delay *= 2; delay >= ws.maxBackoff {
+			return ws.maxBackoff
+		}
+	}
+
+	return delay
+}
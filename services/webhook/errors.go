@@ -0,0 +1,13 @@
+package webhook
+
+import (
+	"errors"
+)
+
+var (
+	// ErrWebhookNotExists webhook not exists
+	ErrWebhookNotExists = errors.New("webhook doesn't exists")
+
+	// ErrForbiddenAddress the webhook host resolves to the loopback, private or link-local address
+	ErrForbiddenAddress = errors.New("webhook address is not allowed")
+)
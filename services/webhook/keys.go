@@ -0,0 +1,22 @@
+package webhook
+
+import "fmt"
+
+const (
+	prefix         = "webhooks:"
+	deliveryPrefix = "webhook-deliveries:"
+	outboxPrefix   = "webhook-outbox:"
+)
+
+func makeWebhookKey(profileID, id string) []byte {
+	return []byte(fmt.Sprintf(prefix+"%s:%s", profileID, id))
+}
+
+func makeDeliveryKey(profileID, webhookID, id string) []byte {
+	return []byte(fmt.Sprintf(deliveryPrefix+"%s:%s:%s", profileID, webhookID, id))
+}
+
+// makeOutboxKey the outbox keeps keys of pending deliveries ordered by the event
+func makeOutboxKey(id, webhookID string) []byte {
+	return []byte(fmt.Sprintf(outboxPrefix+"%s:%s", id, webhookID))
+}
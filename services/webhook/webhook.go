@@ -0,0 +1,555 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mustafaturan/bus/v3"
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/events/envelope"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/system/validate"
+	db "github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+const (
+	// handlerKey the bus handler key of the webhooks
+	handlerKey = "webhooks:outbox"
+
+	// secretSize size of the generated signing secret in bytes
+	secretSize = 32
+
+	// DefaultMaxAttempts how many times the delivery is attempted before it is dead-lettered
+	DefaultMaxAttempts = 10
+
+	// DefaultMinBackoff delay before the first retry, doubled with each attempt
+	DefaultMinBackoff = 10 * time.Second
+
+	// DefaultMaxBackoff maximum delay between retries
+	DefaultMaxBackoff = time.Hour
+
+	// pollInterval how often the outbox is checked for due deliveries
+	pollInterval = time.Second
+
+	// requestTimeout how long the webhook endpoint is awaited
+	requestTimeout = 10 * time.Second
+)
+
+// Config is the webhook service configuration
+type Config struct {
+	Validator *validate.Validator
+	DB        db.DB
+	Bus       *bus.Bus
+	Logger    *zap.SugaredLogger
+
+	// HTTPClient sends deliveries, the client with the request timeout that dials only public addresses
+	// is used when empty
+	HTTPClient *http.Client
+
+	// AllowedHosts may use plain http and private addresses, e.g. receivers of the internal network.
+	// URLs of other hosts must be https and resolve to public addresses.
+	AllowedHosts []string
+
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Service holds dependencies
+type Service struct {
+	validator    *validate.Validator
+	db           db.DB
+	logger       *zap.SugaredLogger
+	httpClient   *http.Client
+	allowedHosts map[string]bool
+	senders      *senders
+
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+// NewService creates a new webhook service, events of the bus are written to the outbox
+func NewService(cfg Config) *Service {
+	ws := &Service{
+		validator:    cfg.Validator,
+		db:           cfg.DB,
+		logger:       cfg.Logger,
+		httpClient:   cfg.HTTPClient,
+		allowedHosts: make(map[string]bool, len(cfg.AllowedHosts)),
+		senders:      &senders{running: make(map[string]bool)},
+		maxAttempts:  cfg.MaxAttempts,
+		minBackoff:   cfg.MinBackoff,
+		maxBackoff:   cfg.MaxBackoff,
+		wake:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+
+	for _, host := range cfg.AllowedHosts {
+		ws.allowedHosts[strings.ToLower(host)] = true
+	}
+
+	if ws.httpClient == nil {
+		ws.httpClient = ws.newHTTPClient()
+	}
+
+	if ws.maxAttempts <= 0 {
+		ws.maxAttempts = DefaultMaxAttempts
+	}
+
+	if ws.minBackoff <= 0 {
+		ws.minBackoff = DefaultMinBackoff
+	}
+
+	if ws.maxBackoff <= 0 {
+		ws.maxBackoff = DefaultMaxBackoff
+	}
+
+	cfg.Bus.RegisterHandler(handlerKey, bus.Handler{
+		Handle:  ws.enqueue,
+		Matcher: ".*",
+	})
+
+	return ws
+}
+
+// Create registers a new webhook of the profile, the signing secret is returned only once
+func (ws Service) Create(ctx context.Context, sw svcs.SaveWebhook) (svcs.Webhook, error) {
+	if err := ws.validator.Check(sw); err != nil {
+		return svcs.Webhook{}, err
+	}
+
+	if err := ws.checkURL(sw.URL); err != nil {
+		return svcs.Webhook{}, err
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return svcs.Webhook{}, fmt.Errorf("cannot generate webhook secret: %w", err)
+	}
+
+	webhook := svcs.Webhook{
+		ID:        uuid.New().String(),
+		URL:       sw.URL,
+		Events:    sw.Events,
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	b, err := json.Marshal(webhook)
+	if err != nil {
+		return svcs.Webhook{}, err
+	}
+
+	if err := ws.db.SetSync(makeWebhookKey(auth.GetUserID(ctx), webhook.ID), b); err != nil {
+		return svcs.Webhook{}, fmt.Errorf("cannot save webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// Get fetches the profile webhook, the secret is not returned
+func (ws Service) Get(ctx context.Context, id string) (svcs.Webhook, error) {
+	webhook, err := ws.get(auth.GetUserID(ctx), id)
+	if err != nil {
+		return svcs.Webhook{}, err
+	}
+
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+func (ws Service) get(profileID, id string) (svcs.Webhook, error) {
+	var webhook svcs.Webhook
+
+	b, err := ws.db.Get(makeWebhookKey(profileID, id))
+	if err != nil {
+		return webhook, err
+	}
+
+	if b == nil {
+		return webhook, ErrWebhookNotExists
+	}
+
+	if err := json.Unmarshal(b, &webhook); err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+// List fetches all webhooks of the profile
+func (ws Service) List(ctx context.Context) ([]svcs.Webhook, error) {
+	webhooks, err := ws.list(auth.GetUserID(ctx))
+	if err != nil {
+		return webhooks, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+func (ws Service) list(profileID string) ([]svcs.Webhook, error) {
+	webhooks := make([]svcs.Webhook, 0)
+
+	prefixDB := db.NewPrefixDB(ws.db, makeWebhookKey(profileID, ""))
+
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return webhooks, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		var webhook svcs.Webhook
+
+		if err := json.Unmarshal(itr.Value(), &webhook); err != nil {
+			return webhooks, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+// Delete deletes the webhook with its delivery log, pending deliveries are dropped
+func (ws Service) Delete(ctx context.Context, id string) error {
+	profileID := auth.GetUserID(ctx)
+
+	if _, err := ws.get(profileID, id); err != nil {
+		return err
+	}
+
+	deliveries, err := ws.Deliveries(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	batch := ws.db.NewBatch()
+	defer batch.Close()
+
+	for _, d := range deliveries {
+		if err := batch.Delete(makeDeliveryKey(profileID, id, d.ID)); err != nil {
+			return err
+		}
+
+		if err := batch.Delete(makeOutboxKey(d.ID, id)); err != nil {
+			return err
+		}
+	}
+
+	if err := batch.Delete(makeWebhookKey(profileID, id)); err != nil {
+		return err
+	}
+
+	return batch.WriteSync()
+}
+
+// Deliveries returns the delivery log of the webhook
+func (ws Service) Deliveries(ctx context.Context, id string) ([]svcs.WebhookDelivery, error) {
+	profileID := auth.GetUserID(ctx)
+
+	deliveries := make([]svcs.WebhookDelivery, 0)
+
+	if _, err := ws.get(profileID, id); err != nil {
+		return deliveries, err
+	}
+
+	prefixDB := db.NewPrefixDB(ws.db, makeDeliveryKey(profileID, id, ""))
+
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return deliveries, err
+	}
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		var d svcs.WebhookDelivery
+
+		if err := json.Unmarshal(itr.Value(), &d); err != nil {
+			return deliveries, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+// enqueue writes deliveries of the event to the outbox of every profile webhook subscribed to it
+func (ws Service) enqueue(ctx context.Context, e bus.Event) {
+	evt, err := envelope.New(ctx, e)
+	if err != nil {
+		ws.logger.Errorw("cannot marshal webhook event", "topic", e.Topic, "error", err)
+		return
+	}
+
+	if evt.ProfileID == "" {
+		return
+	}
+
+	if err := ws.enqueueEnvelope(evt); err != nil {
+		ws.logger.Errorw("cannot enqueue webhook deliveries", "topic", e.Topic, "error", err)
+		return
+	}
+
+	select {
+	case ws.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ws Service) enqueueEnvelope(evt envelope.Envelope) error {
+	webhooks, err := ws.list(evt.ProfileID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	batch := ws.db.NewBatch()
+	defer batch.Close()
+
+	now := time.Now().UTC()
+
+	for _, webhook := range webhooks {
+		if !subscribed(webhook, evt.Type) {
+			continue
+		}
+
+		d := svcs.WebhookDelivery{
+			ID:            evt.ID,
+			WebhookID:     webhook.ID,
+			Event:         evt.Type,
+			Payload:       payload,
+			Status:        svcs.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		if err := ws.saveDelivery(batch, evt.ProfileID, d); err != nil {
+			return err
+		}
+	}
+
+	return batch.WriteSync()
+}
+
+func subscribed(webhook svcs.Webhook, event string) bool {
+	for _, e := range webhook.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// saveDelivery writes the delivery to the log, pending deliveries are kept in the outbox
+func (ws Service) saveDelivery(batch db.Batch, profileID string, d svcs.WebhookDelivery) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	deliveryKey := makeDeliveryKey(profileID, d.WebhookID, d.ID)
+
+	if err := batch.Set(deliveryKey, b); err != nil {
+		return err
+	}
+
+	if d.Status == svcs.WebhookDeliveryPending {
+		return batch.Set(makeOutboxKey(d.ID, d.WebhookID), deliveryKey)
+	}
+
+	return batch.Delete(makeOutboxKey(d.ID, d.WebhookID))
+}
+
+// Run delivers the outbox until the context is canceled, deliveries that are sent are finished before Done is closed
+func (ws Service) Run(ctx context.Context) {
+	defer close(ws.done)
+	defer ws.senders.wg.Wait()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := ws.startDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			ws.logger.Errorw("webhook outbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ws.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Done is closed when the delivery worker is stopped
+func (ws Service) Done() <-chan struct{} {
+	return ws.done
+}
+
+// DeliverDue attempts deliveries of the outbox which are due and waits until they are sent
+func (ws Service) DeliverDue(ctx context.Context) error {
+	started, err := ws.startDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	started.Wait()
+
+	return nil
+}
+
+// senders tracks webhooks whose deliveries are being sent
+type senders struct {
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// dueDelivery is the delivery of the outbox that is due
+type dueDelivery struct {
+	key      []byte
+	delivery svcs.WebhookDelivery
+}
+
+// startDue starts sending deliveries of the outbox which are due. Every webhook has its own sender that sends
+// deliveries in order and stops at the first failure, so the dead endpoint does not delay other webhooks.
+// Webhooks whose sender is still running are skipped.
+func (ws Service) startDue(ctx context.Context) (*sync.WaitGroup, error) {
+	var started sync.WaitGroup
+
+	due, order, err := ws.due()
+	if err != nil {
+		return &started, err
+	}
+
+	for _, webhookID := range order {
+		if ctx.Err() != nil {
+			return &started, ctx.Err()
+		}
+
+		ws.senders.mu.Lock()
+		running := ws.senders.running[webhookID]
+		ws.senders.running[webhookID] = true
+		ws.senders.mu.Unlock()
+
+		if running {
+			continue
+		}
+
+		started.Add(1)
+		ws.senders.wg.Add(1)
+
+		go func(webhookID string, deliveries []dueDelivery) {
+			defer func() {
+				ws.senders.mu.Lock()
+				delete(ws.senders.running, webhookID)
+				ws.senders.mu.Unlock()
+
+				ws.senders.wg.Done()
+				started.Done()
+			}()
+
+			for _, d := range deliveries {
+				status, err := ws.attempt(ctx, d.key, d.delivery)
+				if err != nil {
+					if !errors.Is(err, context.Canceled) {
+						ws.logger.Errorw("webhook outbox", "webhook", webhookID, "error", err)
+					}
+
+					return
+				}
+
+				// next deliveries of the webhook wait for the retry of the failed one
+				if status == svcs.WebhookDeliveryPending {
+					return
+				}
+			}
+		}(webhookID, due[webhookID])
+	}
+
+	return &started, nil
+}
+
+// due returns deliveries of the outbox which are due grouped by the webhook and webhook IDs in the outbox order
+func (ws Service) due() (map[string][]dueDelivery, []string, error) {
+	// keys are collected first, the outbox is modified by deliveries
+	var keys [][]byte
+
+	prefixDB := db.NewPrefixDB(ws.db, []byte(outboxPrefix))
+
+	itr, err := prefixDB.Iterator(nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, append([]byte(nil), itr.Value()...))
+	}
+
+	if err := itr.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	due := make(map[string][]dueDelivery)
+	order := make([]string, 0)
+
+	for _, key := range keys {
+		b, err := ws.db.Get(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// the delivery was sent by the running sender meanwhile
+		if b == nil {
+			continue
+		}
+
+		var d svcs.WebhookDelivery
+
+		if err := json.Unmarshal(b, &d); err != nil {
+			return nil, nil, fmt.Errorf("cannot read webhook delivery %s: %w", key, err)
+		}
+
+		if d.Status != svcs.WebhookDeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		if _, ok := due[d.WebhookID]; !ok {
+			order = append(order, d.WebhookID)
+		}
+
+		due[d.WebhookID] = append(due[d.WebhookID], dueDelivery{key: key, delivery: d})
+	}
+
+	return due, order, nil
+}
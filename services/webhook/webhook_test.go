@@ -0,0 +1,220 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/obada-foundation/client-helper/auth"
+	"github.com/obada-foundation/client-helper/bus"
+	"github.com/obada-foundation/client-helper/events"
+	svcs "github.com/obada-foundation/client-helper/services"
+	"github.com/obada-foundation/client-helper/services/webhook"
+	"github.com/obada-foundation/client-helper/system/validate"
+	"github.com/obada-foundation/client-helper/testutil"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+)
+
+const testDID = "did:obada:64925be84b586363670c1f7e5ada86a37904e590d1f6570d834436331dd3eb88"
+
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	w.WriteHeader(rc.status)
+}
+
+func TestService(t *testing.T) {
+	ctx := auth.SetClaims(context.Background(), auth.Claims{UserID: "1"})
+
+	b, err := bus.NewBus()
+	require.NoError(t, err)
+
+	b.RegisterTopics(events.NftMinted, events.NftTransfered)
+
+	validator, err := validate.NewValidator()
+	require.NoError(t, err)
+
+	logger, deleteLogFile := testutil.MakeLoger()
+	defer deleteLogFile()
+
+	svc := webhook.NewService(webhook.Config{
+		Validator:    validator,
+		DB:           db.NewMemDB(),
+		Bus:          b,
+		Logger:       logger,
+		MaxAttempts:  2,
+		MinBackoff:   time.Millisecond,
+		AllowedHosts: []string{"127.0.0.1"},
+	})
+
+	rc := &receiver{status: http.StatusOK}
+
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	t.Log("Requires https for hosts that are not allowed")
+	{
+		_, err := svc.Create(ctx, svcs.SaveWebhook{URL: "http://example.com/hook", Events: []string{events.NftMinted}})
+		require.Error(t, err)
+		require.True(t, validate.IsFieldErrors(err))
+	}
+
+	t.Log("Validates subscribed events")
+	{
+		_, err := svc.Create(ctx, svcs.SaveWebhook{URL: srv.URL, Events: []string{"nft.burned"}})
+		require.Error(t, err)
+		require.True(t, validate.IsFieldErrors(err))
+	}
+
+	wh, err := svc.Create(ctx, svcs.SaveWebhook{URL: srv.URL, Events: []string{events.NftMinted}})
+	require.NoError(t, err)
+	require.NotEmpty(t, wh.Secret)
+
+	t.Log("Does not return the secret after the creation")
+	{
+		webhooks, err := svc.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Empty(t, webhooks[0].Secret)
+	}
+
+	t.Log("Delivers signed subscribed events of the profile")
+	{
+		require.NoError(t, b.Emit(ctx, events.NftMinted, testDID))
+		require.NoError(t, b.Emit(ctx, events.NftTransfered, testDID))
+		require.NoError(t, b.Emit(auth.SetClaims(ctx, auth.Claims{UserID: "2"}), events.NftMinted, testDID))
+
+		require.NoError(t, svc.DeliverDue(ctx))
+
+		require.Len(t, rc.requests, 1)
+
+		req := rc.requests[0]
+		require.Equal(t, events.NftMinted, req.Header.Get(webhook.HeaderEvent))
+
+		timestamp, err := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		require.Equal(t, webhook.Sign(wh.Secret, timestamp, rc.bodies[0]), req.Header.Get(webhook.HeaderSignature))
+		require.Contains(t, string(rc.bodies[0]), testDID)
+
+		deliveries, err := svc.Deliveries(ctx, wh.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, svcs.WebhookDeliveryDelivered, deliveries[0].Status)
+		require.Equal(t, http.StatusOK, deliveries[0].ResponseCode)
+		require.Equal(t, 1, deliveries[0].Attempts)
+	}
+
+	t.Log("Retries failed deliveries and dead-letters them after max attempts")
+	{
+		rc.status = http.StatusInternalServerError
+
+		require.NoError(t, b.Emit(ctx, events.NftMinted, testDID))
+		require.NoError(t, svc.DeliverDue(ctx))
+
+		deliveries, err := svc.Deliveries(ctx, wh.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		require.Equal(t, svcs.WebhookDeliveryPending, deliveries[1].Status)
+		require.Equal(t, http.StatusInternalServerError, deliveries[1].ResponseCode)
+		require.NotEmpty(t, deliveries[1].LastError)
+
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, svc.DeliverDue(ctx))
+
+		deliveries, err = svc.Deliveries(ctx, wh.ID)
+		require.NoError(t, err)
+		require.Equal(t, svcs.WebhookDeliveryDead, deliveries[1].Status)
+		require.Equal(t, 2, deliveries[1].Attempts)
+
+		require.NoError(t, svc.DeliverDue(ctx))
+		require.Len(t, rc.requests, 3)
+	}
+
+	t.Log("Deletes webhook with the delivery log")
+	{
+		require.NoError(t, svc.Delete(ctx, wh.ID))
+
+		_, err := svc.Deliveries(ctx, wh.ID)
+		require.ErrorIs(t, err, webhook.ErrWebhookNotExists)
+	}
+
+	t.Log("Does not send deliveries to the loopback address of hosts that are not allowed")
+	{
+		wh, err := svc.Create(ctx, svcs.SaveWebhook{
+			URL:    strings.Replace(srv.URL, "http://127.0.0.1", "https://localhost", 1),
+			Events: []string{events.NftMinted},
+		})
+		require.NoError(t, err)
+
+		requests := len(rc.requests)
+
+		require.NoError(t, b.Emit(ctx, events.NftMinted, testDID))
+		require.NoError(t, svc.DeliverDue(ctx))
+		require.Len(t, rc.requests, requests)
+
+		deliveries, err := svc.Deliveries(ctx, wh.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, svcs.WebhookDeliveryPending, deliveries[0].Status)
+		require.Contains(t, deliveries[0].LastError, webhook.ErrForbiddenAddress.Error())
+
+		require.NoError(t, svc.Delete(ctx, wh.ID))
+	}
+
+	t.Log("Does not delay deliveries of other webhooks while the endpoint is not responding")
+	{
+		release := make(chan struct{})
+
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer slow.Close()
+
+		rc.status = http.StatusOK
+		requests := len(rc.requests)
+
+		_, err := svc.Create(ctx, svcs.SaveWebhook{URL: slow.URL, Events: []string{events.NftMinted}})
+		require.NoError(t, err)
+
+		_, err = svc.Create(ctx, svcs.SaveWebhook{URL: srv.URL, Events: []string{events.NftMinted}})
+		require.NoError(t, err)
+
+		require.NoError(t, b.Emit(ctx, events.NftMinted, testDID))
+
+		done := make(chan error)
+
+		go func() {
+			done <- svc.DeliverDue(ctx)
+		}()
+
+		require.Eventually(t, func() bool {
+			rc.mu.Lock()
+			defer rc.mu.Unlock()
+
+			return len(rc.requests) == requests+1
+		}, time.Second, 5*time.Millisecond)
+
+		close(release)
+		require.NoError(t, <-done)
+	}
+}
@@ -1,9 +1,17 @@
 package keyring
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	dkeyring "github.com/99designs/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
 	"github.com/cosmos/go-bip39"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -11,11 +19,250 @@ const (
 	defaultEntropySize = 256
 	addressSuffix      = "address"
 	infoSuffix         = "info"
+
+	appName = "client-helper"
+
+	// BackendFile stores keys encrypted with the passphrase
+	BackendFile = keyring.BackendFile
+
+	// BackendTest stores keys unencrypted, it is meant for development only
+	BackendTest = keyring.BackendTest
+
+	// MinPassphraseLength the shortest passphrase accepted by the file backend
+	MinPassphraseLength = 8
+
+	// the same layout as the Cosmos SDK keyring, so keys can be used by the SDK tools
+	fileDirName = "keyring-file"
+	testDirName = "keyring-test"
+	keyhashFile = "keyhash"
+
+	// mnemonics of the remote signer wallets are kept next to the keys
+	walletsDirName      = "wallets-"
+	testBackendPassword = "test"
 )
 
-// NewKeying creates a new keyring with a single key
-func NewKeying(_, keyringPath string) (keyring.Keyring, error) {
-	return keyring.New("client-helper", keyring.BackendFile, keyringPath, nil, cosmostestutil.MakeTestEncodingConfig().Codec)
+var (
+	// ErrInvalidPassphrase is returned when the passphrase does not match the passphrase of the existing keyring
+	ErrInvalidPassphrase = errors.New("invalid keyring passphrase")
+
+	// ErrShortPassphrase is returned when the file backend passphrase is too short
+	ErrShortPassphrase = fmt.Errorf("keyring passphrase must be at least %d characters", MinPassphraseLength)
+
+	// ErrUnsupportedBackend is returned for backends other than file and test
+	ErrUnsupportedBackend = errors.New("unsupported keyring backend")
+
+	// ErrKeyMismatch is returned when the destination keyring has another key under the same name
+	ErrKeyMismatch = errors.New("destination keyring has another key under the same name")
+
+	// ErrNotMigrated is returned when keys are stored by the test backend only
+	ErrNotMigrated = errors.New("keys of the test backend are not migrated")
+)
+
+// New opens the keyring in the directory. Keys of the file backend are encrypted with the passphrase,
+// the passphrase is not used by the test backend.
+func New(backend, dir, passphrase string) (keyring.Keyring, error) {
+	cdc := cosmostestutil.MakeTestEncodingConfig().Codec
+
+	switch backend {
+	case BackendTest:
+		return keyring.New(appName, keyring.BackendTest, dir, nil, cdc)
+	case BackendFile:
+		fileDir := filepath.Join(dir, fileDirName)
+
+		if err := checkPassphrase(fileDir, passphrase); err != nil {
+			return nil, err
+		}
+
+		kr, err := dkeyring.Open(dkeyring.Config{
+			AllowedBackends:  []dkeyring.BackendType{dkeyring.FileBackend},
+			ServiceName:      appName,
+			FileDir:          fileDir,
+			FilePasswordFunc: dkeyring.FixedStringPrompt(passphrase),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return keyring.NewInMemoryWithKeyring(kr, cdc), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBackend, backend)
+	}
+}
+
+// OpenWallets opens the store of HD wallet mnemonics in the keyring directory, mnemonics of the file backend
+// are encrypted with the keyring passphrase
+func OpenWallets(backend, dir, passphrase string) (dkeyring.Keyring, error) {
+	switch backend {
+	case BackendTest:
+		passphrase = testBackendPassword
+	case BackendFile:
+		if err := checkPassphrase(filepath.Join(dir, fileDirName), passphrase); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBackend, backend)
+	}
+
+	return dkeyring.Open(dkeyring.Config{
+		AllowedBackends:  []dkeyring.BackendType{dkeyring.FileBackend},
+		ServiceName:      appName + "-wallets",
+		FileDir:          filepath.Join(dir, walletsDirName+backend),
+		FilePasswordFunc: dkeyring.FixedStringPrompt(passphrase),
+	})
+}
+
+// checkPassphrase compares the passphrase with the hash of the keyring passphrase, the hash is created with the keyring
+func checkPassphrase(fileDir, passphrase string) error {
+	if len(passphrase) < MinPassphraseLength {
+		return ErrShortPassphrase
+	}
+
+	keyhashPath := filepath.Join(fileDir, keyhashFile)
+
+	keyhash, err := os.ReadFile(keyhashPath)
+	if err == nil {
+		if bcrypt.CompareHashAndPassword(keyhash, []byte(passphrase)) != nil {
+			return ErrInvalidPassphrase
+		}
+
+		return nil
+	}
+
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", keyhashPath, err)
+	}
+
+	if err := os.MkdirAll(fileDir, 0o700); err != nil {
+		return err
+	}
+
+	keyhash, err = bcrypt.GenerateFromPassword([]byte(passphrase), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(keyhashPath, keyhash, 0o600)
+}
+
+// Migrate copies keys that are missing in the destination keyring, names of the keys are preserved.
+// Keys that exist in the destination already are verified by address, the migration fails when the address
+// differs. It returns names of the copied and verified keys, only they can be deleted from the source.
+func Migrate(from, to keyring.Keyring) ([]string, error) {
+	records, err := from.List()
+	if err != nil {
+		return nil, err
+	}
+
+	migrated := make([]string, 0, len(records))
+
+	for _, record := range records {
+		existing, err := to.Key(record.Name)
+		switch {
+		case err == nil:
+			if err := sameAddress(record, existing); err != nil {
+				return migrated, fmt.Errorf("cannot migrate key %q: %w", record.Name, err)
+			}
+		case errors.Is(err, sdkerrors.ErrKeyNotFound):
+			if err := copyKey(from, to, record); err != nil {
+				return migrated, fmt.Errorf("cannot migrate key %q: %w", record.Name, err)
+			}
+		default:
+			return migrated, err
+		}
+
+		migrated = append(migrated, record.Name)
+	}
+
+	return migrated, nil
+}
+
+// CheckMigrated fails with ErrNotMigrated when the keyring directory has keys of the test backend
+// and the keyring has none, e.g. the deployment that used the test backend was upgraded without the migration
+func CheckMigrated(dir string, kr keyring.Keyring) error {
+	if _, err := os.Stat(filepath.Join(dir, testDirName)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	records, err := kr.List()
+	if err != nil {
+		return err
+	}
+
+	if len(records) > 0 {
+		return nil
+	}
+
+	testKr, err := New(BackendTest, dir, "")
+	if err != nil {
+		return err
+	}
+
+	testRecords, err := testKr.List()
+	if err != nil {
+		return err
+	}
+
+	if len(testRecords) > 0 {
+		return fmt.Errorf("%w: %d keys in %s", ErrNotMigrated, len(testRecords), filepath.Join(dir, testDirName))
+	}
+
+	return nil
+}
+
+// sameAddress checks that both records are keys of the same account
+func sameAddress(record, existing *keyring.Record) error {
+	address, err := record.GetAddress()
+	if err != nil {
+		return err
+	}
+
+	existingAddress, err := existing.GetAddress()
+	if err != nil {
+		return err
+	}
+
+	if !address.Equals(existingAddress) {
+		return fmt.Errorf("%w: %s, expected %s", ErrKeyMismatch, existingAddress, address)
+	}
+
+	return nil
+}
+
+func copyKey(from, to keyring.Keyring, record *keyring.Record) error {
+	switch record.GetType() {
+	case keyring.TypeLocal:
+		// the armor is only kept in memory, so the export passphrase protects nothing and stays empty
+		armor, err := from.ExportPrivKeyArmor(record.Name, "")
+		if err != nil {
+			return err
+		}
+
+		return to.ImportPrivKey(record.Name, armor, "")
+	case keyring.TypeOffline:
+		pubKey, err := record.GetPubKey()
+		if err != nil {
+			return err
+		}
+
+		_, err = to.SaveOfflineKey(record.Name, pubKey)
+
+		return err
+	case keyring.TypeMulti:
+		pubKey, err := record.GetPubKey()
+		if err != nil {
+			return err
+		}
+
+		_, err = to.SaveMultisig(record.Name, pubKey)
+
+		return err
+	default:
+		return fmt.Errorf("keys of type %s cannot be migrated", record.GetType())
+	}
 }
 
 // NewMnemonic generates a new mnemonic, derives a hierarchical deterministic
@@ -0,0 +1,137 @@
+package keyring_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	cosmoskeyring "github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/obada-foundation/client-helper/system/keyring"
+	"github.com/stretchr/testify/require"
+)
+
+const passphrase = "correct horse battery staple"
+
+func TestFileBackend(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Log("Requires a strong passphrase")
+	{
+		_, err := keyring.New(keyring.BackendFile, dir, "short")
+		require.ErrorIs(t, err, keyring.ErrShortPassphrase)
+	}
+
+	kr, err := keyring.New(keyring.BackendFile, dir, passphrase)
+	require.NoError(t, err)
+
+	mnemonic, err := keyring.NewMnemonic()
+	require.NoError(t, err)
+
+	record, err := kr.NewAccount("key", mnemonic, "", hd.CreateHDPath(118, 0, 0).String(), hd.Secp256k1)
+	require.NoError(t, err)
+
+	address, err := record.GetAddress()
+	require.NoError(t, err)
+
+	t.Log("Stores keys encrypted")
+	{
+		files, err := filepath.Glob(filepath.Join(dir, "keyring-file", "*"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			require.NoError(t, err)
+			require.False(t, strings.Contains(string(b), "key"), "file %s is not encrypted", f)
+		}
+	}
+
+	t.Log("Rejects another passphrase")
+	{
+		_, err := keyring.New(keyring.BackendFile, dir, "another passphrase")
+		require.ErrorIs(t, err, keyring.ErrInvalidPassphrase)
+	}
+
+	t.Log("Opens keys with the passphrase")
+	{
+		kr, err := keyring.New(keyring.BackendFile, dir, passphrase)
+		require.NoError(t, err)
+
+		_, err = kr.KeyByAddress(address)
+		require.NoError(t, err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+
+	testKr, err := keyring.New(keyring.BackendTest, dir, "")
+	require.NoError(t, err)
+
+	mnemonic, err := keyring.NewMnemonic()
+	require.NoError(t, err)
+
+	record, err := testKr.NewAccount("hd", mnemonic, "", hd.CreateHDPath(118, 0, 0).String(), hd.Secp256k1)
+	require.NoError(t, err)
+
+	pubKey, err := record.GetPubKey()
+	require.NoError(t, err)
+
+	_, err = testKr.SaveOfflineKey("watch", pubKey)
+	require.NoError(t, err)
+
+	fileKr, err := keyring.New(keyring.BackendFile, dir, passphrase)
+	require.NoError(t, err)
+
+	t.Log("Reports keys of the test backend that are not migrated")
+	{
+		require.ErrorIs(t, keyring.CheckMigrated(dir, fileKr), keyring.ErrNotMigrated)
+		require.NoError(t, keyring.CheckMigrated(t.TempDir(), fileKr))
+	}
+
+	t.Log("Copies keys to the file backend")
+	{
+		migrated, err := keyring.Migrate(testKr, fileKr)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"hd", "watch"}, migrated)
+		require.NoError(t, keyring.CheckMigrated(dir, fileKr))
+
+		migratedRecord, err := fileKr.Key("hd")
+		require.NoError(t, err)
+		require.Equal(t, cosmoskeyring.TypeLocal, migratedRecord.GetType())
+
+		migratedPubKey, err := migratedRecord.GetPubKey()
+		require.NoError(t, err)
+		require.True(t, pubKey.Equals(migratedPubKey))
+
+		signature, _, err := fileKr.Sign("hd", []byte("msg"), signing.SignMode_SIGN_MODE_DIRECT)
+		require.NoError(t, err)
+		require.True(t, pubKey.VerifySignature([]byte("msg"), signature))
+	}
+
+	t.Log("Verifies keys that are migrated already")
+	{
+		migrated, err := keyring.Migrate(testKr, fileKr)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"hd", "watch"}, migrated)
+	}
+
+	t.Log("Fails when the destination has another key under the same name")
+	{
+		_, err := testKr.NewAccount("collision", mnemonic, "", hd.CreateHDPath(118, 1, 0).String(), hd.Secp256k1)
+		require.NoError(t, err)
+
+		_, err = fileKr.NewAccount("collision", mnemonic, "", hd.CreateHDPath(118, 2, 0).String(), hd.Secp256k1)
+		require.NoError(t, err)
+
+		migrated, err := keyring.Migrate(testKr, fileKr)
+		require.ErrorIs(t, err, keyring.ErrKeyMismatch)
+		require.NotContains(t, migrated, "collision", "the colliding key must not be deleted from the source")
+
+		_, err = testKr.Key("collision")
+		require.NoError(t, err)
+	}
+}
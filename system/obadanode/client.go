@@ -5,18 +5,17 @@ import (
 	"math"
 
 	sdkmath "cosmossdk.io/math"
+	"cosmossdk.io/x/feegrant"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/types/module/testutil"
 	"github.com/cosmos/cosmos-sdk/types/tx"
-	txtypes "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
-	proto "github.com/gogo/protobuf/proto"
 	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
 	"google.golang.org/grpc"
 )
@@ -31,6 +30,12 @@ type Client interface {
 	// BalanceByAddress returns the balance of specified address
 	BalanceByAddress(ctx context.Context, address string) (*banktypes.QueryBalanceResponse, error)
 
+	// AllBalances returns balances of all denoms of specified address
+	AllBalances(ctx context.Context, address string) (sdk.Coins, error)
+
+	// DenomsMetadata returns the metadata of all registered denoms
+	DenomsMetadata(ctx context.Context) ([]banktypes.Metadata, error)
+
 	// BaseDenomMetadata returns the metadata of base denom
 	BaseDenomMetadata(ctx context.Context) (banktypes.Metadata, error)
 
@@ -49,22 +54,58 @@ type Client interface {
 	// Tx methods
 	SendTx(ctx context.Context, cnf TxCustomConfig) (*ctypes.ResultBroadcastTx, error)
 
+	// UnsignedTx builds a transaction for the offline signing
+	UnsignedTx(ctx context.Context, signer string, cnf TxCustomConfig) (UnsignedTx, error)
+
+	// BuildMultisigTx builds a transaction of the multisig account that waits for signatures of co-signers
+	BuildMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, cnf TxCustomConfig) (MultisigTx, error)
+
+	// BroadcastMultisigTx combines signatures of co-signers and broadcasts the multisig account transaction
+	BroadcastMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, mtx MultisigTx,
+		signatures map[string][]byte) (*ctypes.ResultBroadcastTx, error)
+
+	// BroadcastTx broadcasts a transaction signed outside of client-helper
+	BroadcastTx(ctx context.Context, txData []byte, isJSON bool) (*ctypes.ResultBroadcastTx, error)
+
 	// CalculateGas returns the gas needed to execute the given message
 	CalculateGas(ctx context.Context, msgs ...sdk.Msg) (*tx.SimulateResponse, uint64, error)
 
 	// DecodeTx decodes the given tx bytes
 	DecodeTx(b []byte) (Tx, error)
+
+	// Allowance returns the fee allowance granted by the granter to the grantee
+	Allowance(ctx context.Context, granter, grantee string) (feegrant.FeeAllowanceI, error)
+
+	// GranterGrants returns authz grants given by the granter
+	GranterGrants(ctx context.Context, granter string) ([]*authz.GrantAuthorization, error)
+
+	// GranteeGrants returns authz grants received by the grantee
+	GranteeGrants(ctx context.Context, grantee string) ([]*authz.GrantAuthorization, error)
+
+	// WaitForTx waits until the transaction is committed to the block
+	WaitForTx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error)
+
+	// TxSearch returns committed transactions matching the given CometBFT query, newest first
+	TxSearch(ctx context.Context, query string, page, perPage int) (*ctypes.ResultTxSearch, error)
+
+	// BlockTxs returns committed transactions of the blocks in the given height range, oldest first
+	BlockTxs(ctx context.Context, fromHeight, toHeight int64, page, perPage int) (*ctypes.ResultTxSearch, error)
+
+	// LatestHeight returns the height of the latest committed block
+	LatestHeight(ctx context.Context) (int64, error)
 }
 
 // NodeClient stores dependencies for OBADA client
 type NodeClient struct {
 	conn *grpc.ClientConn
 
-	clientHTTP    *rpchttp.HTTP
-	authClient    authtypes.QueryClient
-	bankClient    banktypes.QueryClient
-	obadaClient   obadatypes.QueryClient
-	serviceClient tx.ServiceClient
+	clientHTTP     *rpchttp.HTTP
+	authClient     authtypes.QueryClient
+	bankClient     banktypes.QueryClient
+	obadaClient    obadatypes.QueryClient
+	feegrantClient feegrant.QueryClient
+	authzClient    authz.QueryClient
+	serviceClient  tx.ServiceClient
 
 	cdc      *codec.ProtoCodec
 	txConfig client.TxConfig
@@ -84,8 +125,7 @@ func NewClient(ctx context.Context, chainID, rpcURI, grpcURI string) (NodeClient
 		c = NodeClient{
 			chainID: chainID,
 		}
-		encCfg = testutil.MakeTestEncodingConfig()
-		err    error
+		err error
 	)
 
 	if c.clientHTTP, err = rpchttp.New(rpcURI, "/websocket"); err != nil {
@@ -100,35 +140,27 @@ func NewClient(ctx context.Context, chainID, rpcURI, grpcURI string) (NodeClient
 	c.authClient = authtypes.NewQueryClient(c.conn)
 	c.bankClient = banktypes.NewQueryClient(c.conn)
 	c.obadaClient = obadatypes.NewQueryClient(c.conn)
+	c.feegrantClient = feegrant.NewQueryClient(c.conn)
+	c.authzClient = authz.NewQueryClient(c.conn)
 
-	encCfg.InterfaceRegistry.RegisterInterface("AccountI", (*sdk.AccountI)(nil), &authtypes.BaseAccount{})
-	encCfg.InterfaceRegistry.RegisterInterface("obadafoundation.fullcore.obit.NFTData", (*proto.Message)(nil), &obadatypes.NFTData{})
-	encCfg.InterfaceRegistry.RegisterImplementations((*sdk.Msg)(nil),
-		&obadatypes.MsgMintNFT{},
-		&obadatypes.MsgUpdateNFT{},
-		&obadatypes.MsgTransferNFT{},
-		&obadatypes.MsgUpdateUriHash{},
-		&obadatypes.MsgBatchTransferNFT{},
-		&obadatypes.MsgBatchMintNFT{},
-	)
-
-	c.cdc = codec.NewProtoCodec(encCfg.InterfaceRegistry)
+	enc := NewTxEncoding()
 
-	c.txConfig = txtypes.NewTxConfig(c.cdc, txtypes.DefaultSignModes)
+	c.cdc = enc.Codec
+	c.txConfig = enc.TxConfig
 
 	baseDenomMetdata, err := c.BaseDenomMetadata(ctx)
 	if err != nil {
 		return c, err
 	}
 
-	if _, ok := sdk.GetDenomUnit(baseDenom); !ok {
-		if er := sdk.RegisterDenom(baseDenom, sdkmath.LegacyNewDec(1)); er != nil {
+	if _, ok := sdk.GetDenomUnit(BaseDenom); !ok {
+		if er := sdk.RegisterDenom(BaseDenom, sdkmath.LegacyNewDec(1)); er != nil {
 			return c, er
 		}
 	}
 
 	for _, denomUnit := range baseDenomMetdata.DenomUnits {
-		if denomUnit.Denom != baseDenom {
+		if denomUnit.Denom != BaseDenom {
 			if _, ok := sdk.GetDenomUnit(denomUnit.Denom); !ok {
 				exp := int64(1 * math.Pow10(int(denomUnit.Exponent)))
 
@@ -149,7 +181,7 @@ func (c NodeClient) Close() {
 
 // DecodeTx decodes transaction from bytes
 func (c NodeClient) DecodeTx(b []byte) (Tx, error) {
-	transaction, err := txtypes.DefaultTxDecoder(c.cdc)(b)
+	transaction, err := c.txConfig.TxDecoder()(b)
 	if err != nil {
 		return Tx{}, err
 	}
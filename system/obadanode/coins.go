@@ -0,0 +1,99 @@
+package obadanode
+
+import (
+	"fmt"
+	"math/big"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// maxCoinBitLen is the maximum bit length of the coin amount supported by sdkmath.Int
+const maxCoinBitLen = 256
+
+// DisplayCoin converts coin in the base denom to the display denom described by the metadata.
+// Coins without registered metadata (e.g. IBC tokens) are returned as is.
+func DisplayCoin(coin sdk.Coin, metadatas []banktypes.Metadata) sdk.DecCoin {
+	decCoin := sdk.NewDecCoinFromCoin(coin)
+
+	for _, md := range metadatas {
+		if md.Base != coin.Denom || md.Display == "" || md.Display == md.Base {
+			continue
+		}
+
+		exp, ok := unitExponent(md, md.Display)
+		if !ok {
+			break
+		}
+
+		return sdk.DecCoin{
+			Denom:  md.Display,
+			Amount: decCoin.Amount.Quo(sdkmath.LegacyNewDecFromInt(pow10(int(exp)))),
+		}
+	}
+
+	return decCoin
+}
+
+// ParseDisplayCoin converts amount in any denom unit described by the metadata (e.g. "1.5" and "obd")
+// to the coin in the base denom. Denoms without metadata are treated as base denoms.
+func ParseDisplayCoin(amount, denom string, metadatas []banktypes.Metadata) (sdk.Coin, error) {
+	var coin sdk.Coin
+
+	dec, err := sdkmath.LegacyNewDecFromStr(amount)
+	if err != nil {
+		return coin, fmt.Errorf("%w: %q is not a number", ErrInvalidAmount, amount)
+	}
+
+	if !dec.IsPositive() {
+		return coin, fmt.Errorf("%w: amount should be positive", ErrInvalidAmount)
+	}
+
+	base, exp := denom, uint32(0)
+
+	for _, md := range metadatas {
+		if e, ok := unitExponent(md, denom); ok {
+			base, exp = md.Base, e
+			break
+		}
+	}
+
+	if er := sdk.ValidateDenom(base); er != nil {
+		return coin, fmt.Errorf("%w: %s", ErrInvalidAmount, er)
+	}
+
+	// dec keeps amount multiplied by 10^LegacyPrecision, so the base amount is dec * 10^exp / 10^LegacyPrecision
+	num := new(big.Int).Mul(dec.BigInt(), pow10(int(exp)).BigInt())
+	baseAmount, rem := new(big.Int).QuoRem(num, pow10(sdkmath.LegacyPrecision).BigInt(), new(big.Int))
+
+	if rem.Sign() != 0 {
+		return coin, fmt.Errorf("%w: %s supports up to %d decimal places", ErrInvalidAmount, denom, exp)
+	}
+
+	if baseAmount.BitLen() > maxCoinBitLen {
+		return coin, fmt.Errorf("%w: amount is too large", ErrInvalidAmount)
+	}
+
+	return sdk.NewCoin(base, sdkmath.NewIntFromBigInt(baseAmount)), nil
+}
+
+func unitExponent(md banktypes.Metadata, denom string) (uint32, bool) {
+	for _, unit := range md.DenomUnits {
+		if unit.Denom == denom {
+			return unit.Exponent, true
+		}
+
+		for _, alias := range unit.Aliases {
+			if alias == denom {
+				return unit.Exponent, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func pow10(exp int) sdkmath.Int {
+	return sdkmath.NewIntWithDecimal(1, exp)
+}
@@ -0,0 +1,72 @@
+package obadanode_test
+
+import (
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var metadatas = []banktypes.Metadata{
+	{
+		Base:    "rohi",
+		Display: "obd",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: "rohi", Exponent: 0},
+			{Denom: "obd", Exponent: 6, Aliases: []string{"OBD"}},
+		},
+	},
+}
+
+func TestDisplayCoin(t *testing.T) {
+	t.Log("Test coin with metadata is converted to display denom")
+	{
+		coin := obadanode.DisplayCoin(sdk.NewInt64Coin("rohi", 1500000), metadatas)
+		assert.Equal(t, "obd", coin.Denom)
+		assert.Equal(t, sdkmath.LegacyMustNewDecFromStr("1.5"), coin.Amount)
+	}
+
+	t.Log("Test coin without metadata is returned as is")
+	{
+		ibcDenom := "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+
+		coin := obadanode.DisplayCoin(sdk.NewInt64Coin(ibcDenom, 42), metadatas)
+		assert.Equal(t, ibcDenom, coin.Denom)
+		assert.Equal(t, sdkmath.LegacyNewDec(42), coin.Amount)
+	}
+}
+
+func TestParseDisplayCoin(t *testing.T) {
+	tests := []struct {
+		amount string
+		denom  string
+		want   string
+		err    bool
+	}{
+		{amount: "1.5", denom: "obd", want: "1500000rohi"},
+		{amount: "0.000001", denom: "OBD", want: "1rohi"},
+		{amount: "25", denom: "rohi", want: "25rohi"},
+		{amount: "7", denom: "uatom", want: "7uatom"},
+		{amount: "0.0000001", denom: "obd", err: true},
+		{amount: "1.5", denom: "rohi", err: true},
+		{amount: "0", denom: "obd", err: true},
+		{amount: "-1", denom: "obd", err: true},
+		{amount: "one", denom: "obd", err: true},
+		{amount: "1", denom: "", err: true},
+	}
+
+	for _, tc := range tests {
+		coin, err := obadanode.ParseDisplayCoin(tc.amount, tc.denom, metadatas)
+		if tc.err {
+			require.ErrorIs(t, err, obadanode.ErrInvalidAmount, "%s%s", tc.amount, tc.denom)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, coin.String())
+	}
+}
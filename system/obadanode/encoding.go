@@ -0,0 +1,47 @@
+package obadanode
+
+import (
+	"cosmossdk.io/x/feegrant"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module/testutil"
+	txtypes "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	proto "github.com/gogo/protobuf/proto"
+	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
+)
+
+// TxEncoding holds codec and tx config that know about OBADA and used Cosmos messages
+type TxEncoding struct {
+	Codec    *codec.ProtoCodec
+	TxConfig client.TxConfig
+}
+
+// NewTxEncoding creates tx encoding, doesn't require connection to the node
+func NewTxEncoding() TxEncoding {
+	encCfg := testutil.MakeTestEncodingConfig()
+
+	encCfg.InterfaceRegistry.RegisterInterface("AccountI", (*sdk.AccountI)(nil), &authtypes.BaseAccount{})
+	banktypes.RegisterInterfaces(encCfg.InterfaceRegistry)
+	feegrant.RegisterInterfaces(encCfg.InterfaceRegistry)
+	authz.RegisterInterfaces(encCfg.InterfaceRegistry)
+	encCfg.InterfaceRegistry.RegisterInterface("obadafoundation.fullcore.obit.NFTData", (*proto.Message)(nil), &obadatypes.NFTData{})
+	encCfg.InterfaceRegistry.RegisterImplementations((*sdk.Msg)(nil),
+		&obadatypes.MsgMintNFT{},
+		&obadatypes.MsgUpdateNFT{},
+		&obadatypes.MsgTransferNFT{},
+		&obadatypes.MsgUpdateUriHash{},
+		&obadatypes.MsgBatchTransferNFT{},
+		&obadatypes.MsgBatchMintNFT{},
+	)
+
+	cdc := codec.NewProtoCodec(encCfg.InterfaceRegistry)
+
+	return TxEncoding{
+		Codec:    cdc,
+		TxConfig: txtypes.NewTxConfig(cdc, txtypes.DefaultSignModes),
+	}
+}
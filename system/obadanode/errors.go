@@ -10,4 +10,16 @@ var (
 
 	// ErrInsufficientFunds is returned when an account has not enough balance to commit transaction.
 	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrTxNotSigned is returned when broadcasting transaction doesn't have signatures.
+	ErrTxNotSigned = errors.New("transaction is not signed")
+
+	// ErrTxDecode is returned when broadcasting transaction cannot be decoded.
+	ErrTxDecode = errors.New("cannot decode transaction")
+
+	// ErrInvalidAmount is returned when coin amount cannot be converted to the base denom.
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrAllowanceNotFound is returned when the granter didn't grant fee allowance to the grantee.
+	ErrAllowanceNotFound = errors.New("fee allowance not found")
 )
@@ -5,6 +5,10 @@ package mocks
 import (
 	context "context"
 
+	feegrant "cosmossdk.io/x/feegrant"
+
+	authz "github.com/cosmos/cosmos-sdk/x/authz"
+
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 
 	coretypes "github.com/cometbft/cometbft/rpc/core/types"
@@ -54,6 +58,52 @@ func (_m *Client) Account(ctx context.Context, address string) (cosmos_sdktypes.
 	return r0, r1
 }
 
+// AllBalances provides a mock function with given fields: ctx, address
+func (_m *Client) AllBalances(ctx context.Context, address string) (cosmos_sdktypes.Coins, error) {
+	ret := _m.Called(ctx, address)
+
+	var r0 cosmos_sdktypes.Coins
+	if rf, ok := ret.Get(0).(func(context.Context, string) cosmos_sdktypes.Coins); ok {
+		r0 = rf(ctx, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(cosmos_sdktypes.Coins)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Allowance provides a mock function with given fields: ctx, granter, grantee
+func (_m *Client) Allowance(ctx context.Context, granter string, grantee string) (feegrant.FeeAllowanceI, error) {
+	ret := _m.Called(ctx, granter, grantee)
+
+	var r0 feegrant.FeeAllowanceI
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) feegrant.FeeAllowanceI); ok {
+		r0 = rf(ctx, granter, grantee)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(feegrant.FeeAllowanceI)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, granter, grantee)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Balance provides a mock function with given fields: ctx, pubKey
 func (_m *Client) Balance(ctx context.Context, pubKey cryptotypes.PubKey) (*banktypes.QueryBalanceResponse, error) {
 	ret := _m.Called(ctx, pubKey)
@@ -100,6 +150,96 @@ func (_m *Client) BalanceByAddress(ctx context.Context, address string) (*bankty
 	return r0, r1
 }
 
+// BlockTxs provides a mock function with given fields: ctx, fromHeight, toHeight, page, perPage
+func (_m *Client) BlockTxs(ctx context.Context, fromHeight int64, toHeight int64, page int, perPage int) (*coretypes.ResultTxSearch, error) {
+	ret := _m.Called(ctx, fromHeight, toHeight, page, perPage)
+
+	var r0 *coretypes.ResultTxSearch
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int, int) *coretypes.ResultTxSearch); ok {
+		r0 = rf(ctx, fromHeight, toHeight, page, perPage)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultTxSearch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int, int) error); ok {
+		r1 = rf(ctx, fromHeight, toHeight, page, perPage)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BroadcastMultisigTx provides a mock function with given fields: ctx, pubKey, mtx, signatures
+func (_m *Client) BroadcastMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, mtx obadanode.MultisigTx, signatures map[string][]byte) (*coretypes.ResultBroadcastTx, error) {
+	ret := _m.Called(ctx, pubKey, mtx, signatures)
+
+	var r0 *coretypes.ResultBroadcastTx
+	if rf, ok := ret.Get(0).(func(context.Context, cryptotypes.PubKey, obadanode.MultisigTx, map[string][]byte) *coretypes.ResultBroadcastTx); ok {
+		r0 = rf(ctx, pubKey, mtx, signatures)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultBroadcastTx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, cryptotypes.PubKey, obadanode.MultisigTx, map[string][]byte) error); ok {
+		r1 = rf(ctx, pubKey, mtx, signatures)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BroadcastTx provides a mock function with given fields: ctx, txData, isJSON
+func (_m *Client) BroadcastTx(ctx context.Context, txData []byte, isJSON bool) (*coretypes.ResultBroadcastTx, error) {
+	ret := _m.Called(ctx, txData, isJSON)
+
+	var r0 *coretypes.ResultBroadcastTx
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, bool) *coretypes.ResultBroadcastTx); ok {
+		r0 = rf(ctx, txData, isJSON)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultBroadcastTx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, bool) error); ok {
+		r1 = rf(ctx, txData, isJSON)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BuildMultisigTx provides a mock function with given fields: ctx, pubKey, cnf
+func (_m *Client) BuildMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, cnf obadanode.TxCustomConfig) (obadanode.MultisigTx, error) {
+	ret := _m.Called(ctx, pubKey, cnf)
+
+	var r0 obadanode.MultisigTx
+	if rf, ok := ret.Get(0).(func(context.Context, cryptotypes.PubKey, obadanode.TxCustomConfig) obadanode.MultisigTx); ok {
+		r0 = rf(ctx, pubKey, cnf)
+	} else {
+		r0 = ret.Get(0).(obadanode.MultisigTx)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, cryptotypes.PubKey, obadanode.TxCustomConfig) error); ok {
+		r1 = rf(ctx, pubKey, cnf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CalculateGas provides a mock function with given fields: ctx, msgs
 func (_m *Client) CalculateGas(ctx context.Context, msgs ...cosmos_sdktypes.Msg) (*tx.SimulateResponse, uint64, error) {
 	_va := make([]interface{}, len(msgs))
@@ -158,6 +298,29 @@ func (_m *Client) DecodeTx(b []byte) (obadanode.Tx, error) {
 	return r0, r1
 }
 
+// DenomsMetadata provides a mock function with given fields: ctx
+func (_m *Client) DenomsMetadata(ctx context.Context) ([]banktypes.Metadata, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []banktypes.Metadata
+	if rf, ok := ret.Get(0).(func(context.Context) []banktypes.Metadata); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]banktypes.Metadata)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNFT provides a mock function with given fields: ctx, DID
 func (_m *Client) GetNFT(ctx context.Context, DID string) (*obittypes.NFT, error) {
 	ret := _m.Called(ctx, DID)
@@ -204,6 +367,52 @@ func (_m *Client) GetNFTByAddress(ctx context.Context, address string) ([]obitty
 	return r0, r1
 }
 
+// GranteeGrants provides a mock function with given fields: ctx, grantee
+func (_m *Client) GranteeGrants(ctx context.Context, grantee string) ([]*authz.GrantAuthorization, error) {
+	ret := _m.Called(ctx, grantee)
+
+	var r0 []*authz.GrantAuthorization
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*authz.GrantAuthorization); ok {
+		r0 = rf(ctx, grantee)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*authz.GrantAuthorization)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, grantee)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GranterGrants provides a mock function with given fields: ctx, granter
+func (_m *Client) GranterGrants(ctx context.Context, granter string) ([]*authz.GrantAuthorization, error) {
+	ret := _m.Called(ctx, granter)
+
+	var r0 []*authz.GrantAuthorization
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*authz.GrantAuthorization); ok {
+		r0 = rf(ctx, granter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*authz.GrantAuthorization)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, granter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasAccount provides a mock function with given fields: ctx, address
 func (_m *Client) HasAccount(ctx context.Context, address string) (bool, error) {
 	ret := _m.Called(ctx, address)
@@ -225,13 +434,34 @@ func (_m *Client) HasAccount(ctx context.Context, address string) (bool, error)
 	return r0, r1
 }
 
-// SendTx provides a mock function with given fields: ctx, msg, priv
-func (_m *Client) SendTx(ctx context.Context, msg cosmos_sdktypes.Msg, priv cryptotypes.PrivKey) (*coretypes.ResultBroadcastTx, error) {
-	ret := _m.Called(ctx, msg, priv)
+// LatestHeight provides a mock function with given fields: ctx
+func (_m *Client) LatestHeight(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendTx provides a mock function with given fields: ctx, cnf
+func (_m *Client) SendTx(ctx context.Context, cnf obadanode.TxCustomConfig) (*coretypes.ResultBroadcastTx, error) {
+	ret := _m.Called(ctx, cnf)
 
 	var r0 *coretypes.ResultBroadcastTx
-	if rf, ok := ret.Get(0).(func(context.Context, cosmos_sdktypes.Msg, cryptotypes.PrivKey) *coretypes.ResultBroadcastTx); ok {
-		r0 = rf(ctx, msg, priv)
+	if rf, ok := ret.Get(0).(func(context.Context, obadanode.TxCustomConfig) *coretypes.ResultBroadcastTx); ok {
+		r0 = rf(ctx, cnf)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*coretypes.ResultBroadcastTx)
@@ -239,8 +469,75 @@ func (_m *Client) SendTx(ctx context.Context, msg cosmos_sdktypes.Msg, priv cryp
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, cosmos_sdktypes.Msg, cryptotypes.PrivKey) error); ok {
-		r1 = rf(ctx, msg, priv)
+	if rf, ok := ret.Get(1).(func(context.Context, obadanode.TxCustomConfig) error); ok {
+		r1 = rf(ctx, cnf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TxSearch provides a mock function with given fields: ctx, query, page, perPage
+func (_m *Client) TxSearch(ctx context.Context, query string, page int, perPage int) (*coretypes.ResultTxSearch, error) {
+	ret := _m.Called(ctx, query, page, perPage)
+
+	var r0 *coretypes.ResultTxSearch
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *coretypes.ResultTxSearch); ok {
+		r0 = rf(ctx, query, page, perPage)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultTxSearch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, query, page, perPage)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsignedTx provides a mock function with given fields: ctx, signer, cnf
+func (_m *Client) UnsignedTx(ctx context.Context, signer string, cnf obadanode.TxCustomConfig) (obadanode.UnsignedTx, error) {
+	ret := _m.Called(ctx, signer, cnf)
+
+	var r0 obadanode.UnsignedTx
+	if rf, ok := ret.Get(0).(func(context.Context, string, obadanode.TxCustomConfig) obadanode.UnsignedTx); ok {
+		r0 = rf(ctx, signer, cnf)
+	} else {
+		r0 = ret.Get(0).(obadanode.UnsignedTx)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, obadanode.TxCustomConfig) error); ok {
+		r1 = rf(ctx, signer, cnf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaitForTx provides a mock function with given fields: ctx, hash
+func (_m *Client) WaitForTx(ctx context.Context, hash []byte) (*coretypes.ResultTx, error) {
+	ret := _m.Called(ctx, hash)
+
+	var r0 *coretypes.ResultTx
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *coretypes.ResultTx); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultTx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, hash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,145 @@
+package obadanode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// MultisigSignMode co-signers of the legacy amino multisig account sign the amino JSON, direct sign bytes
+// contain signer infos that are not known until all co-signers signed
+const MultisigSignMode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+
+// ErrNotMultisig is returned when the public key is not the legacy amino multisig key
+var ErrNotMultisig = errors.New("public key is not a multisig key")
+
+// MultisigTx is a transaction of the multisig account that waits for signatures of co-signers
+type MultisigTx struct {
+	TxBytes   []byte  `json:"tx_bytes"`
+	SignBytes []byte  `json:"sign_bytes"`
+	SignDoc   SignDoc `json:"sign_doc"`
+}
+
+// BuildMultisigTx builds a transaction of the multisig account given by the public key
+func (c NodeClient) BuildMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, cnf TxCustomConfig) (MultisigTx, error) {
+	var mtx MultisigTx
+
+	acc, err := c.Account(ctx, sdk.AccAddress(pubKey.Address()).String())
+	if err != nil {
+		return mtx, err
+	}
+
+	txBuilder, err := c.BuildUnsignedTx(cnf.Msg)
+	if err != nil {
+		return mtx, err
+	}
+
+	txBuilder.SetGasLimit(cnf.GasLimit)
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewCoin(BaseDenom, cnf.FeeAmount)))
+	txBuilder.SetFeeGranter(cnf.FeeGranter)
+
+	mtx.SignDoc = SignDoc{
+		ChainID:       c.chainID,
+		AccountNumber: acc.GetAccountNumber(),
+		Sequence:      acc.GetSequence(),
+	}
+
+	if mtx.SignBytes, err = MultisigSignBytes(ctx, c.txConfig, txBuilder, pubKey, mtx.SignDoc); err != nil {
+		return mtx, err
+	}
+
+	if mtx.TxBytes, err = c.txConfig.TxEncoder()(txBuilder.GetTx()); err != nil {
+		return mtx, err
+	}
+
+	return mtx, nil
+}
+
+// BroadcastMultisigTx combines signatures of co-signers, keyed by their addresses, into the multisig signature
+// and broadcasts the transaction
+func (c NodeClient) BroadcastMultisigTx(ctx context.Context, pubKey cryptotypes.PubKey, mtx MultisigTx,
+	signatures map[string][]byte,
+) (*ctypes.ResultBroadcastTx, error) {
+	transaction, err := c.txConfig.TxDecoder()(mtx.TxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTxDecode, err)
+	}
+
+	txBuilder, err := c.txConfig.WrapTxBuilder(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CombineMultisig(txBuilder, pubKey, mtx.SignDoc, signatures); err != nil {
+		return nil, err
+	}
+
+	txBytes, err := c.txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.broadcast(ctx, txBytes)
+}
+
+// MultisigSignBytes returns bytes that co-signers of the multisig account sign, the amino JSON sign bytes
+// don't depend on signatures, so they are known before anyone signed
+func MultisigSignBytes(ctx context.Context, txConfig client.TxConfig, txBuilder client.TxBuilder, pubKey cryptotypes.PubKey,
+	signDoc SignDoc,
+) ([]byte, error) {
+	signerData := authsigning.SignerData{
+		Address:       sdk.AccAddress(pubKey.Address()).String(),
+		ChainID:       signDoc.ChainID,
+		AccountNumber: signDoc.AccountNumber,
+		Sequence:      signDoc.Sequence,
+		PubKey:        pubKey,
+	}
+
+	return authsigning.GetSignBytesAdapter(ctx, txConfig.SignModeHandler(), MultisigSignMode, signerData, txBuilder.GetTx())
+}
+
+// CombineMultisig sets the multisig signature built from signatures of co-signers, keyed by their addresses
+func CombineMultisig(txBuilder client.TxBuilder, pubKey cryptotypes.PubKey, signDoc SignDoc, signatures map[string][]byte) error {
+	multisigPubKey, ok := pubKey.(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return ErrNotMultisig
+	}
+
+	pubKeys := multisigPubKey.GetPubKeys()
+	multisigSig := multisig.NewMultisig(len(pubKeys))
+
+	for _, pk := range pubKeys {
+		signature, ok := signatures[sdk.AccAddress(pk.Address()).String()]
+		if !ok {
+			continue
+		}
+
+		sig := signing.SignatureV2{
+			PubKey: pk,
+			Data: &signing.SingleSignatureData{
+				SignMode:  MultisigSignMode,
+				Signature: signature,
+			},
+			Sequence: signDoc.Sequence,
+		}
+
+		if err := multisig.AddSignatureV2(multisigSig, sig, pubKeys); err != nil {
+			return err
+		}
+	}
+
+	return txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     multisigSig,
+		Sequence: signDoc.Sequence,
+	})
+}
@@ -0,0 +1,107 @@
+package obadanode_test
+
+import (
+	"context"
+	"testing"
+
+	txsigning "cosmossdk.io/x/tx/signing"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/obada-foundation/client-helper/system/obadanode"
+	"github.com/obada-foundation/fullcore/x/obit/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombineMultisig(t *testing.T) {
+	ctx := context.Background()
+	enc := obadanode.NewTxEncoding()
+
+	privKeys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKeys := make([]cryptotypes.PubKey, 0, len(privKeys))
+
+	for _, pk := range privKeys {
+		pubKeys = append(pubKeys, pk.PubKey())
+	}
+
+	multisigPubKey := kmultisig.NewLegacyAminoPubKey(2, pubKeys)
+	multisigAddress := sdk.AccAddress(multisigPubKey.Address()).String()
+
+	signDoc := obadanode.SignDoc{ChainID: "obada-testnet", AccountNumber: 7, Sequence: 3}
+
+	txBuilder := enc.TxConfig.NewTxBuilder()
+	require.NoError(t, txBuilder.SetMsgs(&types.MsgTransferNFT{
+		Id:       "did:obada:fe096095-e0f0-4918-9607-6567bd5756b5",
+		Sender:   multisigAddress,
+		Receiver: sdk.AccAddress(pubKeys[0].Address()).String(),
+	}))
+	txBuilder.SetGasLimit(100000)
+
+	signBytes, err := obadanode.MultisigSignBytes(ctx, enc.TxConfig, txBuilder, multisigPubKey, signDoc)
+	require.NoError(t, err)
+
+	sign := func(idx int) (string, []byte) {
+		sig, er := privKeys[idx].Sign(signBytes)
+		require.NoError(t, er)
+
+		return sdk.AccAddress(pubKeys[idx].Address()).String(), sig
+	}
+
+	verify := func() error {
+		sigs, er := txBuilder.GetTx().GetSignaturesV2()
+		require.NoError(t, er)
+		require.Len(t, sigs, 1)
+
+		signerData := txsigning.SignerData{
+			Address:       multisigAddress,
+			ChainID:       signDoc.ChainID,
+			AccountNumber: signDoc.AccountNumber,
+			Sequence:      signDoc.Sequence,
+		}
+
+		adaptableTx := txBuilder.GetTx().(authsigning.V2AdaptableTx)
+
+		return authsigning.VerifySignature(ctx, multisigPubKey, signerData, sigs[0].Data, enc.TxConfig.SignModeHandler(),
+			adaptableTx.GetSigningTxData())
+	}
+
+	t.Log("Test the multisig signature below the threshold is rejected")
+	{
+		addr, sig := sign(2)
+
+		require.NoError(t, obadanode.CombineMultisig(txBuilder, multisigPubKey, signDoc, map[string][]byte{addr: sig}))
+		require.Error(t, verify())
+	}
+
+	t.Log("Test the multisig signature of threshold co-signers is valid and sign bytes stay the same")
+	{
+		firstAddr, firstSig := sign(0)
+		lastAddr, lastSig := sign(2)
+
+		require.NoError(t, obadanode.CombineMultisig(txBuilder, multisigPubKey, signDoc, map[string][]byte{
+			lastAddr:  lastSig,
+			firstAddr: firstSig,
+		}))
+		require.NoError(t, verify())
+
+		sigs, er := txBuilder.GetTx().GetSignaturesV2()
+		require.NoError(t, er)
+
+		data, ok := sigs[0].Data.(*signing.MultiSignatureData)
+		require.True(t, ok)
+		require.Len(t, data.Signatures, 2)
+
+		bytesAfter, er := obadanode.MultisigSignBytes(ctx, enc.TxConfig, txBuilder, multisigPubKey, signDoc)
+		require.NoError(t, er)
+		require.Equal(t, signBytes, bytesAfter)
+	}
+
+	t.Log("Test the single key is not accepted as multisig")
+	{
+		err := obadanode.CombineMultisig(txBuilder, pubKeys[0], signDoc, nil)
+		require.ErrorIs(t, err, obadanode.ErrNotMultisig)
+	}
+}
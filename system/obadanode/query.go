@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	"cosmossdk.io/x/feegrant"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	obadatypes "github.com/obada-foundation/fullcore/x/obit/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-const baseDenom = "rohi"
+// BaseDenom is the denom used for fees and balance accounting
+const BaseDenom = "rohi"
 
 // BaseDenomMetadata returns the metadata for the base denom
 func (c NodeClient) BaseDenomMetadata(ctx context.Context) (banktypes.Metadata, error) {
@@ -29,12 +34,12 @@ func (c NodeClient) BaseDenomMetadata(ctx context.Context) (banktypes.Metadata,
 	}
 
 	for _, md = range denoms.Metadatas {
-		if md.Base == baseDenom {
+		if md.Base == BaseDenom {
 			return md, nil
 		}
 	}
 
-	return md, fmt.Errorf("%q base denom metadata are not registered on chain", baseDenom)
+	return md, fmt.Errorf("%q base denom metadata are not registered on chain", BaseDenom)
 }
 
 // Balance implements the Balance method of the Client interface
@@ -43,7 +48,7 @@ func (c NodeClient) Balance(ctx context.Context, pubKey cryptotypes.PubKey) (*ba
 
 	req := &banktypes.QueryBalanceRequest{
 		Address: addr.String(),
-		Denom:   "rohi",
+		Denom:   BaseDenom,
 	}
 	res, err := c.bankClient.Balance(ctx, req)
 	if err != nil {
@@ -58,7 +63,7 @@ func (c NodeClient) Balance(ctx context.Context, pubKey cryptotypes.PubKey) (*ba
 func (c NodeClient) BalanceByAddress(ctx context.Context, address string) (*banktypes.QueryBalanceResponse, error) {
 	req := &banktypes.QueryBalanceRequest{
 		Address: address,
-		Denom:   "rohi",
+		Denom:   BaseDenom,
 	}
 
 	res, err := c.bankClient.Balance(ctx, req)
@@ -70,6 +75,28 @@ func (c NodeClient) BalanceByAddress(ctx context.Context, address string) (*bank
 
 }
 
+// AllBalances implements the AllBalances method of the Client interface
+func (c NodeClient) AllBalances(ctx context.Context, address string) (types.Coins, error) {
+	res, err := c.bankClient.AllBalances(ctx, &banktypes.QueryAllBalancesRequest{
+		Address: address,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Balances, nil
+}
+
+// DenomsMetadata implements the DenomsMetadata method of the Client interface
+func (c NodeClient) DenomsMetadata(ctx context.Context) ([]banktypes.Metadata, error) {
+	res, err := c.bankClient.DenomsMetadata(ctx, &banktypes.QueryDenomsMetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get denom metadata: %w", err)
+	}
+
+	return res.Metadatas, nil
+}
+
 // GetNFTByAddress implements the GetNFTByAddress method of the Client interface
 func (c NodeClient) GetNFTByAddress(ctx context.Context, address string) ([]obadatypes.NFT, error) {
 	resp, err := c.obadaClient.GetNFTByAddress(ctx, &obadatypes.QueryGetNFTByAddressRequest{
@@ -134,3 +161,128 @@ func (c NodeClient) Account(ctx context.Context, address string) (acc types.Acco
 
 	return
 }
+
+// Allowance implements the Allowance method of the Client interface
+func (c NodeClient) Allowance(ctx context.Context, granter, grantee string) (feegrant.FeeAllowanceI, error) {
+	res, err := c.feegrantClient.Allowance(ctx, &feegrant.QueryAllowanceRequest{
+		Granter: granter,
+		Grantee: grantee,
+	})
+	if err != nil {
+		statusError, ok := status.FromError(err)
+		if !ok {
+			return nil, err
+		}
+
+		switch statusError.Code() {
+		case codes.NotFound:
+			return nil, ErrAllowanceNotFound
+		case codes.Internal:
+			// feegrant module wraps the missing grant into codes.Internal, the grant is looked up
+			// among allowances of the grantee to tell it apart from the other failures
+			granted, er := c.hasAllowance(ctx, granter, grantee)
+			if er != nil {
+				return nil, er
+			}
+
+			if !granted {
+				return nil, ErrAllowanceNotFound
+			}
+		}
+
+		return nil, err
+	}
+
+	var allowance feegrant.FeeAllowanceI
+
+	if err := c.cdc.UnpackAny(res.GetAllowance().GetAllowance(), &allowance); err != nil {
+		return nil, err
+	}
+
+	return allowance, nil
+}
+
+// hasAllowance checks if the granter granted fee allowance to the grantee
+func (c NodeClient) hasAllowance(ctx context.Context, granter, grantee string) (bool, error) {
+	var nextKey []byte
+
+	for {
+		res, err := c.feegrantClient.Allowances(ctx, &feegrant.QueryAllowancesRequest{
+			Grantee:    grantee,
+			Pagination: &query.PageRequest{Key: nextKey},
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for _, grant := range res.GetAllowances() {
+			if grant.GetGranter() == granter {
+				return true, nil
+			}
+		}
+
+		nextKey = res.GetPagination().GetNextKey()
+		if len(nextKey) == 0 {
+			return false, nil
+		}
+	}
+}
+
+// GranterGrants implements the GranterGrants method of the Client interface
+func (c NodeClient) GranterGrants(ctx context.Context, granter string) ([]*authz.GrantAuthorization, error) {
+	res, err := c.authzClient.GranterGrants(ctx, &authz.QueryGranterGrantsRequest{
+		Granter: granter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.unpackGrants(res.GetGrants())
+}
+
+// GranteeGrants implements the GranteeGrants method of the Client interface
+func (c NodeClient) GranteeGrants(ctx context.Context, grantee string) ([]*authz.GrantAuthorization, error) {
+	res, err := c.authzClient.GranteeGrants(ctx, &authz.QueryGranteeGrantsRequest{
+		Grantee: grantee,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.unpackGrants(res.GetGrants())
+}
+
+// unpackGrants caches authorizations, so they are accessible with GetCachedValue
+func (c NodeClient) unpackGrants(grants []*authz.GrantAuthorization) ([]*authz.GrantAuthorization, error) {
+	for _, g := range grants {
+		var a authz.Authorization
+
+		if err := c.cdc.UnpackAny(g.Authorization, &a); err != nil {
+			return nil, err
+		}
+	}
+
+	return grants, nil
+}
+
+// TxSearch implements the TxSearch method of the Client interface
+func (c NodeClient) TxSearch(ctx context.Context, query string, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	return c.clientHTTP.TxSearch(ctx, query, false, &page, &perPage, "desc")
+}
+
+// BlockTxs implements the BlockTxs method of the Client interface
+func (c NodeClient) BlockTxs(ctx context.Context, fromHeight, toHeight int64, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	query := fmt.Sprintf("tx.height >= %d AND tx.height <= %d", fromHeight, toHeight)
+
+	return c.clientHTTP.TxSearch(ctx, query, false, &page, &perPage, "asc")
+}
+
+// LatestHeight implements the LatestHeight method of the Client interface
+func (c NodeClient) LatestHeight(ctx context.Context) (int64, error) {
+	status, err := c.clientHTTP.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return status.SyncInfo.LatestBlockHeight, nil
+}
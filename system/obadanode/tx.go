@@ -2,28 +2,59 @@ package obadanode
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	sdkmath "cosmossdk.io/math"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/tx"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txs "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/obada-foundation/client-helper/system/signer"
 )
 
+// txPollInterval how often node is polled for the committed transaction
+const txPollInterval = time.Second
+
+// SignDoc contains account data, besides the transaction itself, that is needed for signing.
+type SignDoc struct {
+	ChainID       string `json:"chain_id"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+}
+
+// UnsignedTx is a transaction prepared for the offline signing.
+type UnsignedTx struct {
+	Tx      json.RawMessage `json:"tx"`
+	TxBytes []byte          `json:"tx_bytes"`
+	SignDoc SignDoc         `json:"sign_doc"`
+}
+
 // TxCustomConfig defines a struct for configuring a TxBuilder.
 type TxCustomConfig struct {
-	Msg       sdk.Msg
-	Priv      cryptotypes.PrivKey
+	Msg sdk.Msg
+
+	// Msgs are sent after Msg in the same transaction, optional
+	Msgs []sdk.Msg
+
+	Signer    signer.Signer
 	AccSeq    uint64
 	GasLimit  uint64
 	FeeAmount sdkmath.Int
+
+	// FeeGranter pays the fee from the allowance granted to the signer, optional
+	FeeGranter sdk.AccAddress
+}
+
+// msgs returns all messages of the transaction
+func (cnf TxCustomConfig) msgs() []sdk.Msg {
+	return append([]sdk.Msg{cnf.Msg}, cnf.Msgs...)
 }
 
 // BuildUnsignedTx builds a transaction to be signed given a set of messages.
@@ -96,7 +127,7 @@ func (c NodeClient) CalculateGas(ctx context.Context, msgs ...sdk.Msg,
 
 // SendTx sends a transaction to the node.
 func (c NodeClient) SendTx(ctx context.Context, cnf TxCustomConfig) (*ctypes.ResultBroadcastTx, error) {
-	accAddress := sdk.AccAddress(cnf.Priv.PubKey().Address().Bytes()).String()
+	accAddress := signer.Address(cnf.Signer).String()
 	nonce, err := c.Nonce(ctx, accAddress)
 	if err != nil {
 		return nil, err
@@ -114,6 +145,10 @@ func (c NodeClient) SendTx(ctx context.Context, cnf TxCustomConfig) (*ctypes.Res
 		return nil, err
 	}
 
+	return c.broadcast(ctx, txBytes)
+}
+
+func (c NodeClient) broadcast(ctx context.Context, txBytes []byte) (*ctypes.ResultBroadcastTx, error) {
 	res, err := c.clientHTTP.BroadcastTxSync(ctx, txBytes)
 
 	if err != nil {
@@ -135,66 +170,184 @@ func (c NodeClient) SendTx(ctx context.Context, cnf TxCustomConfig) (*ctypes.Res
 	return res, nil
 }
 
-// BuildTx builds a transaction given a set of messages and a private key.
+// BuildTx builds a transaction given a set of messages and signs it by the signer.
 func (c NodeClient) BuildTx(ctx context.Context, cnf TxCustomConfig) (authsigning.Tx, error) {
 	txBuilder := c.txConfig.NewTxBuilder()
-	txBuilder.GetTx().GetFee()
-	privK := cnf.Priv
-	pubK := privK.PubKey()
 
-	err := txBuilder.SetMsgs(cnf.Msg)
+	err := txBuilder.SetMsgs(cnf.msgs()...)
 	if err != nil {
 		return nil, err
 	}
 	txBuilder.SetGasLimit(cnf.GasLimit)
-	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewCoin("rohi", cnf.FeeAmount)))
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewCoin(BaseDenom, cnf.FeeAmount)))
+	txBuilder.SetFeeGranter(cnf.FeeGranter)
 	//sdk.NewCoins(sdk.NewCoin("rohi", sdkmath.NewInt(100000))))
 
+	accAddress := signer.Address(cnf.Signer).String()
+
+	acc, err := c.Account(ctx, accAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	signDoc := SignDoc{
+		ChainID:       c.chainID,
+		AccountNumber: acc.GetAccountNumber(),
+		Sequence:      cnf.AccSeq,
+	}
+
+	if err := SignTx(ctx, c.txConfig, txBuilder, cnf.Signer, signDoc); err != nil {
+		return nil, err
+	}
+
+	return txBuilder.GetTx(), nil
+}
+
+// SignTx signs the transaction by the given signer, it doesn't require connection to the node
+// so can be used on the offline (air-gapped) machine.
+func SignTx(ctx context.Context, txConfig client.TxConfig, txBuilder client.TxBuilder, s signer.Signer, signDoc SignDoc) error {
+	pubK := s.PubKey()
+	signMode := signing.SignMode(txConfig.SignModeHandler().DefaultMode())
+
 	// First round: we gather all the signer infos. We use the "set empty signature" hack to do that.
 	if er := txBuilder.SetSignatures(signing.SignatureV2{
-		PubKey: cnf.Priv.PubKey(),
+		PubKey: pubK,
 		Data: &signing.SingleSignatureData{
-			SignMode:  signing.SignMode(c.txConfig.SignModeHandler().DefaultMode()),
+			SignMode:  signMode,
 			Signature: nil,
 		},
-		Sequence: cnf.AccSeq,
+		Sequence: signDoc.Sequence,
 	}); er != nil {
-		return nil, er
+		return er
 	}
 
-	accAddress := sdk.AccAddress(pubK.Address().Bytes()).String()
+	// Second round: all signer infos are set, so each signer can sign.
+	signerData := authsigning.SignerData{
+		Address:       sdk.AccAddress(pubK.Address().Bytes()).String(),
+		ChainID:       signDoc.ChainID,
+		AccountNumber: signDoc.AccountNumber,
+		Sequence:      signDoc.Sequence,
+		PubKey:        pubK,
+	}
 
-	acc, err := c.Account(ctx, accAddress)
+	signBytes, err := authsigning.GetSignBytesAdapter(ctx, txConfig.SignModeHandler(), signMode, signerData, txBuilder.GetTx())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Second round: all signer infos are set, so each signer can sign.
-	signerData := authsigning.SignerData{
-		Address:       accAddress,
+	signature, err := s.Sign(ctx, signBytes)
+	if err != nil {
+		return err
+	}
+
+	return txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey: pubK,
+		Data: &signing.SingleSignatureData{
+			SignMode:  signMode,
+			Signature: signature,
+		},
+		Sequence: signDoc.Sequence,
+	})
+}
+
+// UnsignedTx builds a transaction that should be signed outside of client-helper by the signer address.
+func (c NodeClient) UnsignedTx(ctx context.Context, signer string, cnf TxCustomConfig) (UnsignedTx, error) {
+	var utx UnsignedTx
+
+	acc, err := c.Account(ctx, signer)
+	if err != nil {
+		return utx, err
+	}
+
+	txBuilder, err := c.BuildUnsignedTx(cnf.msgs()...)
+	if err != nil {
+		return utx, err
+	}
+
+	txBuilder.SetGasLimit(cnf.GasLimit)
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewCoin(BaseDenom, cnf.FeeAmount)))
+	txBuilder.SetFeeGranter(cnf.FeeGranter)
+
+	if utx.Tx, err = c.txConfig.TxJSONEncoder()(txBuilder.GetTx()); err != nil {
+		return utx, err
+	}
+
+	if utx.TxBytes, err = c.txConfig.TxEncoder()(txBuilder.GetTx()); err != nil {
+		return utx, err
+	}
+
+	utx.SignDoc = SignDoc{
 		ChainID:       c.chainID,
 		AccountNumber: acc.GetAccountNumber(),
-		Sequence:      cnf.AccSeq,
-		PubKey:        pubK,
+		Sequence:      acc.GetSequence(),
+	}
+
+	return utx, nil
+}
+
+// BroadcastTx broadcasts a transaction that was signed outside of client-helper.
+// The transaction is accepted either as JSON or as protobuf encoded bytes.
+func (c NodeClient) BroadcastTx(ctx context.Context, txData []byte, isJSON bool) (*ctypes.ResultBroadcastTx, error) {
+	decoder := c.txConfig.TxDecoder()
+	if isJSON {
+		decoder = c.txConfig.TxJSONDecoder()
 	}
 
-	sigV2, err := tx.SignWithPrivKey(
-		ctx,
-		signing.SignMode(c.txConfig.SignModeHandler().DefaultMode()),
-		signerData,
-		txBuilder,
-		privK,
-		c.txConfig,
-		cnf.AccSeq,
-	)
+	transaction, err := decoder(txData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTxDecode, err)
+	}
+
+	sigTx, ok := transaction.(authsigning.SigVerifiableTx)
+	if !ok {
+		return nil, ErrTxNotSigned
+	}
+
+	sigs, err := sigTx.GetSignaturesV2()
 	if err != nil {
 		return nil, err
 	}
-	if err := txBuilder.SetSignatures(sigV2); err != nil {
+
+	if len(sigs) == 0 {
+		return nil, ErrTxNotSigned
+	}
+
+	for _, sig := range sigs {
+		data, ok := sig.Data.(*signing.SingleSignatureData)
+		if ok && len(data.Signature) == 0 {
+			return nil, ErrTxNotSigned
+		}
+	}
+
+	txBytes, err := c.txConfig.TxEncoder()(transaction)
+	if err != nil {
 		return nil, err
 	}
 
-	return txBuilder.GetTx(), nil
+	return c.broadcast(ctx, txBytes)
+}
+
+// WaitForTx polls the node until the transaction is committed to the block or the context is done.
+func (c NodeClient) WaitForTx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error) {
+	ticker := time.NewTicker(txPollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := c.clientHTTP.Tx(ctx, hash, false)
+		if err == nil {
+			if res.TxResult.Code != 0 {
+				return res, fmt.Errorf("code: %d, log: %s, codespace: %s", res.TxResult.Code, res.TxResult.Log, res.TxResult.Codespace)
+			}
+
+			return res, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %X was not committed: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
 }
 
 // Nonce returns the nonce for a given address.
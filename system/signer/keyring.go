@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// Keyring provides signers of the keyring accounts, keys are used in-process
+type Keyring struct {
+	kr keyring.Keyring
+}
+
+// NewKeyring creates the keyring signers provider
+func NewKeyring(kr keyring.Keyring) *Keyring {
+	return &Keyring{kr: kr}
+}
+
+// Signer returns the signer of the keyring account
+func (k *Keyring) Signer(_ context.Context, address string) (Signer, error) {
+	addr, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := k.kr.KeyByAddress(addr)
+	if err != nil {
+		if errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, address)
+		}
+
+		return nil, err
+	}
+
+	return NewKeyringSigner(k.kr, record.Name)
+}
+
+// keyringSigner signs with the keyring key
+type keyringSigner struct {
+	kr     keyring.Keyring
+	uid    string
+	pubKey cryptotypes.PubKey
+}
+
+// NewKeyringSigner returns the signer of the keyring key
+func NewKeyringSigner(kr keyring.Keyring, uid string) (Signer, error) {
+	record, err := kr.Key(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.GetLocal() == nil && record.GetLedger() == nil {
+		return nil, fmt.Errorf("%w: key %q cannot sign", ErrKeyNotFound, uid)
+	}
+
+	pubKey, err := record.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyringSigner{kr: kr, uid: uid, pubKey: pubKey}, nil
+}
+
+// PubKey implements the PubKey method of the Signer interface
+func (s keyringSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+// Sign implements the Sign method of the Signer interface
+func (s keyringSigner) Sign(_ context.Context, msg []byte) ([]byte, error) {
+	signature, _, err := s.kr.Sign(s.uid, msg, signing.SignMode_SIGN_MODE_DIRECT)
+
+	return signature, err
+}
+
+// privKeySigner signs with the private key that is already in memory
+type privKeySigner struct {
+	privKey cryptotypes.PrivKey
+}
+
+// NewPrivKeySigner returns the signer of the private key
+func NewPrivKeySigner(privKey cryptotypes.PrivKey) Signer {
+	return privKeySigner{privKey: privKey}
+}
+
+// PubKey implements the PubKey method of the Signer interface
+func (s privKeySigner) PubKey() cryptotypes.PubKey {
+	return s.privKey.PubKey()
+}
+
+// Sign implements the Sign method of the Signer interface
+func (s privKeySigner) Sign(_ context.Context, msg []byte) ([]byte, error) {
+	return s.privKey.Sign(msg)
+}
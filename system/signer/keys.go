@@ -0,0 +1,111 @@
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// RemoteKeys is the keyring of the remote signer. Records carry public keys only, private keys are added
+// to the signer and never come back.
+type RemoteKeys struct {
+	remote *Remote
+}
+
+// Keys returns the keyring of the remote signer
+func (r *Remote) Keys() *RemoteKeys {
+	return &RemoteKeys{remote: r}
+}
+
+// Key returns the record of the key by name
+func (k *RemoteKeys) Key(uid string) (*keyring.Record, error) {
+	return k.record(url.Values{"uid": {uid}})
+}
+
+// KeyByAddress returns the record of the key by address
+func (k *RemoteKeys) KeyByAddress(address sdk.Address) (*keyring.Record, error) {
+	return k.record(url.Values{"address": {address.String()}})
+}
+
+// Delete deletes the key by name
+func (k *RemoteKeys) Delete(uid string) error {
+	return k.delete(url.Values{"uid": {uid}})
+}
+
+// DeleteByAddress deletes the key by address
+func (k *RemoteKeys) DeleteByAddress(address sdk.Address) error {
+	return k.delete(url.Values{"address": {address.String()}})
+}
+
+// Rename renames the key
+func (k *RemoteKeys) Rename(from, to string) error {
+	return k.notFound(k.remote.call(context.Background(), http.MethodPost, renamePath, renameRequest{From: from, To: to}, nil))
+}
+
+// NewAccount is not supported, the signer derives keys from mnemonics of its wallets, see Remote.DeriveKey
+func (k *RemoteKeys) NewAccount(_, _, _, _ string, _ keyring.SignatureAlgo) (*keyring.Record, error) {
+	return nil, ErrMnemonicRequired
+}
+
+// ImportPrivKeyHex imports the secp256k1 private key into the signer keyring
+func (k *RemoteKeys) ImportPrivKeyHex(uid, privKey, algoStr string) error {
+	if algoStr != string(hd.Secp256k1Type) {
+		return fmt.Errorf("%w: %s", keyring.ErrUnsupportedSigningAlgo, algoStr)
+	}
+
+	bz, err := hex.DecodeString(privKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = k.remote.addRecord(context.Background(), recordRequest{UID: uid, PrivKey: bz})
+
+	return err
+}
+
+// SaveMultisig saves the multisig public key in the signer keyring
+func (k *RemoteKeys) SaveMultisig(uid string, pubKey cryptotypes.PubKey) (*keyring.Record, error) {
+	bz, err := k.remote.cdc.MarshalInterface(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.remote.addRecord(context.Background(), recordRequest{UID: uid, Multisig: bz})
+}
+
+// ExportPrivKeyArmor always fails, private keys never leave the signer
+func (k *RemoteKeys) ExportPrivKeyArmor(_, _ string) (string, error) {
+	return "", ErrKeyExport
+}
+
+func (k *RemoteKeys) record(query url.Values) (*keyring.Record, error) {
+	var resp recordResponse
+
+	if err := k.remote.call(context.Background(), http.MethodGet, recordsPath+"?"+query.Encode(), nil, &resp); err != nil {
+		return nil, k.notFound(err)
+	}
+
+	return k.remote.decodeRecord(resp)
+}
+
+func (k *RemoteKeys) delete(query url.Values) error {
+	return k.notFound(k.remote.call(context.Background(), http.MethodDelete, recordsPath+"?"+query.Encode(), nil, nil))
+}
+
+// notFound reports the missing key the same way the keyring does
+func (k *RemoteKeys) notFound(err error) error {
+	if errors.Is(err, ErrKeyNotFound) {
+		return sdkerrors.ErrKeyNotFound.Wrap(err.Error())
+	}
+
+	return err
+}
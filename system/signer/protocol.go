@@ -0,0 +1,70 @@
+package signer
+
+// The remote signer protocol is JSON over HTTP, byte fields are base64 encoded:
+//
+//	GET    /v1/keys/{address}                      returns keyResponse
+//	POST   /v1/keys/{address}/sign                 accepts signRequest, returns signResponse
+//	GET    /v1/records?uid={uid}|address={address} returns recordResponse
+//	POST   /v1/records                             accepts recordRequest, returns recordResponse
+//	POST   /v1/records/rename                      accepts renameRequest
+//	DELETE /v1/records?uid={uid}|address={address}
+//	PUT    /v1/wallets                             accepts walletRequest
+//	DELETE /v1/wallets?wallet={wallet}
+//	GET    /v1/wallets/address?wallet={wallet}&hd_path={path} returns addressResponse
+//
+// Requests are authorized by the "Authorization: Bearer <token>" header, errors are returned as errorResponse.
+// Keys are secp256k1, the signer hashes the message with SHA-256 before signing like cosmos-sdk keys do.
+// Records are the keyring records without private keys, mnemonics of wallets never leave the signer.
+
+const (
+	keysPath    = "/v1/keys/"
+	recordsPath = "/v1/records"
+	renamePath  = "/v1/records/rename"
+	walletsPath = "/v1/wallets"
+	addressPath = "/v1/wallets/address"
+)
+
+type keyResponse struct {
+	Address string `json:"address"`
+	PubKey  []byte `json:"pub_key"`
+}
+
+type signRequest struct {
+	Message []byte `json:"message"`
+}
+
+type signResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// recordRequest adds the key to the signer keyring, the private key is imported, the key of the wallet is derived
+// by the HD path, or the multisig public key is saved
+type recordRequest struct {
+	UID      string `json:"uid"`
+	PrivKey  []byte `json:"priv_key,omitempty"`
+	Wallet   string `json:"wallet,omitempty"`
+	HDPath   string `json:"hd_path,omitempty"`
+	Multisig []byte `json:"multisig,omitempty"`
+}
+
+type recordResponse struct {
+	Record []byte `json:"record"`
+}
+
+type renameRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type walletRequest struct {
+	Wallet   string `json:"wallet"`
+	Mnemonic string `json:"mnemonic"`
+}
+
+type addressResponse struct {
+	Address string `json:"address"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
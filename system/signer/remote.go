@@ -0,0 +1,232 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+)
+
+// defaultTimeout of the remote signer requests
+const defaultTimeout = 10 * time.Second
+
+// RemoteConfig remote signer options
+type RemoteConfig struct {
+	// URL of the signer, e.g. https://signer.internal:9443
+	URL string
+
+	// Token authorizes requests to the signer
+	Token string
+
+	Timeout time.Duration
+
+	// Client is used for requests, e.g. with the mutual TLS transport, http.DefaultClient otherwise
+	Client *http.Client
+}
+
+// Remote provides signers of the keys that are kept by the remote signer process, it also keeps mnemonics
+// of HD wallets on the signer and derives wallet keys there
+type Remote struct {
+	url     string
+	token   string
+	timeout time.Duration
+	client  *http.Client
+	cdc     codec.Codec
+}
+
+// NewRemote creates the remote signers provider
+func NewRemote(cfg RemoteConfig) (*Remote, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer url: %w", err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid signer url %q, expected http(s)://host:port", cfg.URL)
+	}
+
+	r := &Remote{
+		url:     strings.TrimSuffix(cfg.URL, "/"),
+		token:   cfg.Token,
+		timeout: cfg.Timeout,
+		client:  cfg.Client,
+		cdc:     cosmostestutil.MakeTestEncodingConfig().Codec,
+	}
+
+	if r.timeout <= 0 {
+		r.timeout = defaultTimeout
+	}
+
+	if r.client == nil {
+		r.client = http.DefaultClient
+	}
+
+	return r, nil
+}
+
+// Signer returns the signer of the account, the public key is fetched from the remote signer
+func (r *Remote) Signer(ctx context.Context, address string) (Signer, error) {
+	if _, err := sdk.AccAddressFromBech32(address); err != nil {
+		return nil, err
+	}
+
+	var resp keyResponse
+
+	if err := r.call(ctx, http.MethodGet, keysPath+address, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	pubKey := &secp256k1.PubKey{Key: resp.PubKey}
+
+	if len(resp.PubKey) != secp256k1.PubKeySize || sdk.AccAddress(pubKey.Address()).String() != address {
+		return nil, fmt.Errorf("signer returned public key of another account than %s", address)
+	}
+
+	return remoteSigner{remote: r, address: address, pubKey: pubKey}, nil
+}
+
+// SaveMnemonic hands over the mnemonic of the wallet to the signer
+func (r *Remote) SaveMnemonic(ctx context.Context, wallet, mnemonic string) error {
+	return r.call(ctx, http.MethodPut, walletsPath, walletRequest{Wallet: wallet, Mnemonic: mnemonic}, nil)
+}
+
+// DeleteMnemonic deletes the mnemonic of the wallet from the signer
+func (r *Remote) DeleteMnemonic(ctx context.Context, wallet string) error {
+	return r.call(ctx, http.MethodDelete, walletsPath+"?"+url.Values{"wallet": {wallet}}.Encode(), nil, nil)
+}
+
+// DeriveKey adds the key of the wallet account with the HD path to the signer keyring
+func (r *Remote) DeriveKey(ctx context.Context, wallet, uid, hdPath string) (*keyring.Record, error) {
+	return r.addRecord(ctx, recordRequest{UID: uid, Wallet: wallet, HDPath: hdPath})
+}
+
+// DeriveAddress returns the address of the wallet account with the HD path, the key is not stored
+func (r *Remote) DeriveAddress(ctx context.Context, wallet, hdPath string) (string, error) {
+	var resp addressResponse
+
+	query := url.Values{"wallet": {wallet}, "hd_path": {hdPath}}
+
+	if err := r.call(ctx, http.MethodGet, addressPath+"?"+query.Encode(), nil, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.Address, nil
+}
+
+func (r *Remote) addRecord(ctx context.Context, req recordRequest) (*keyring.Record, error) {
+	var resp recordResponse
+
+	if err := r.call(ctx, http.MethodPost, recordsPath, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return r.decodeRecord(resp)
+}
+
+func (r *Remote) decodeRecord(resp recordResponse) (*keyring.Record, error) {
+	var record keyring.Record
+
+	if err := r.cdc.Unmarshal(resp.Record, &record); err != nil {
+		return nil, fmt.Errorf("malformed signer record: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *Remote) call(ctx context.Context, method, path string, body, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var reqBody io.Reader
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach signer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<10)).Decode(&errResp)
+
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %s", ErrKeyNotFound, errResp.Error)
+		case http.StatusConflict:
+			return fmt.Errorf("%w: %s", ErrKeyExists, errResp.Error)
+		}
+
+		return fmt.Errorf("signer responded with status %d: %s", resp.StatusCode, errResp.Error)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("malformed signer response: %w", err)
+	}
+
+	return nil
+}
+
+// remoteSigner signs with the key of the remote signer
+type remoteSigner struct {
+	remote  *Remote
+	address string
+	pubKey  cryptotypes.PubKey
+}
+
+// PubKey implements the PubKey method of the Signer interface
+func (s remoteSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+// Sign implements the Sign method of the Signer interface
+func (s remoteSigner) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	var resp signResponse
+
+	if err := s.remote.call(ctx, http.MethodPost, keysPath+s.address+"/sign", signRequest{Message: msg}, &resp); err != nil {
+		return nil, err
+	}
+
+	// a compromised or misconfigured signer must not break the transaction
+	if !s.pubKey.VerifySignature(msg, resp.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	return resp.Signature, nil
+}
@@ -0,0 +1,402 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dkeyring "github.com/99designs/keyring"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	cosmostestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	"github.com/cosmos/go-bip39"
+	"go.uber.org/zap"
+)
+
+// maxRequestSize limits the size of the sign request
+const maxRequestSize = 1 << 20
+
+// ServerConfig remote signer server options
+type ServerConfig struct {
+	// Provider signs with keys, usually the keyring of the signer host
+	Provider Provider
+
+	// Keyring keeps keys that are added by client-helper, keys are not managed remotely when it is nil
+	Keyring keyring.Keyring
+
+	// Wallets keeps mnemonics of HD wallets, keys of wallet accounts are derived from them
+	Wallets dkeyring.Keyring
+
+	// Token authorizes requests, requests are not authorized when it is empty
+	Token string
+
+	Logger *zap.SugaredLogger
+}
+
+// Server serves the remote signer protocol, so keys stay on the signer host
+type Server struct {
+	provider Provider
+	keyring  keyring.Keyring
+	wallets  dkeyring.Keyring
+	cdc      codec.Codec
+	token    string
+	logger   *zap.SugaredLogger
+}
+
+// NewServer creates the remote signer server
+func NewServer(cfg ServerConfig) *Server {
+	return &Server{
+		provider: cfg.Provider,
+		keyring:  cfg.Keyring,
+		wallets:  cfg.Wallets,
+		cdc:      cosmostestutil.MakeTestEncodingConfig().Codec,
+		token:    cfg.Token,
+		logger:   cfg.Logger,
+	}
+}
+
+// ServeHTTP implements http.Handler interface
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		s.respond(w, http.StatusUnauthorized, errorResponse{Error: http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+
+	switch path := r.URL.Path; {
+	case strings.HasPrefix(path, keysPath):
+		s.keys(w, r)
+	case s.keyring == nil:
+		s.respond(w, http.StatusNotFound, errorResponse{Error: http.StatusText(http.StatusNotFound)})
+	case path == recordsPath && r.Method == http.MethodGet:
+		s.record(w, r)
+	case path == recordsPath && r.Method == http.MethodPost:
+		s.addRecord(w, r)
+	case path == recordsPath && r.Method == http.MethodDelete:
+		s.deleteRecord(w, r)
+	case path == renamePath && r.Method == http.MethodPost:
+		s.renameRecord(w, r)
+	case path == walletsPath && r.Method == http.MethodPut:
+		s.saveWallet(w, r)
+	case path == walletsPath && r.Method == http.MethodDelete:
+		s.deleteWallet(w, r)
+	case path == addressPath && r.Method == http.MethodGet:
+		s.walletAddress(w, r)
+	default:
+		s.respond(w, http.StatusNotFound, errorResponse{Error: http.StatusText(http.StatusNotFound)})
+	}
+}
+
+func (s *Server) keys(w http.ResponseWriter, r *http.Request) {
+	address, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, keysPath), "/")
+
+	if _, err := sdk.AccAddressFromBech32(address); err != nil {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "invalid account address"})
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "":
+		s.key(w, r, address)
+	case r.Method == http.MethodPost && action == "sign":
+		s.sign(w, r, address)
+	default:
+		s.respond(w, http.StatusNotFound, errorResponse{Error: http.StatusText(http.StatusNotFound)})
+	}
+}
+
+func (s *Server) key(w http.ResponseWriter, r *http.Request, address string) {
+	sg, err := s.provider.Signer(r.Context(), address)
+	if err != nil {
+		s.fail(w, address, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, keyResponse{Address: address, PubKey: sg.PubKey().Bytes()})
+}
+
+func (s *Server) sign(w http.ResponseWriter, r *http.Request, address string) {
+	var req signRequest
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "malformed sign request"})
+		return
+	}
+
+	sg, err := s.provider.Signer(r.Context(), address)
+	if err != nil {
+		s.fail(w, address, err)
+		return
+	}
+
+	signature, err := sg.Sign(r.Context(), req.Message)
+	if err != nil {
+		s.fail(w, address, err)
+		return
+	}
+
+	hash := sha256.Sum256(req.Message)
+
+	s.logger.Infow("message signed", "address", address, "message_hash", hex.EncodeToString(hash[:]))
+
+	s.respond(w, http.StatusOK, signResponse{Signature: signature})
+}
+
+func (s *Server) record(w http.ResponseWriter, r *http.Request) {
+	record, err := s.lookup(r)
+	if err != nil {
+		s.fail(w, r.URL.RawQuery, err)
+		return
+	}
+
+	s.respondRecord(w, record)
+}
+
+func (s *Server) addRecord(w http.ResponseWriter, r *http.Request) {
+	var req recordRequest
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil || req.UID == "" {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "malformed record request"})
+		return
+	}
+
+	var (
+		record *keyring.Record
+		err    error
+	)
+
+	switch {
+	case len(req.PrivKey) > 0:
+		if len(req.PrivKey) != secp256k1.PrivKeySize {
+			s.respond(w, http.StatusBadRequest, errorResponse{Error: "invalid secp256k1 private key"})
+			return
+		}
+
+		if err = s.keyring.ImportPrivKeyHex(req.UID, hex.EncodeToString(req.PrivKey), string(hd.Secp256k1Type)); err == nil {
+			record, err = s.keyring.Key(req.UID)
+		}
+	case req.Wallet != "":
+		var mnemonic string
+
+		if mnemonic, err = s.mnemonic(req.Wallet); err == nil {
+			record, err = s.keyring.NewAccount(req.UID, mnemonic, "", req.HDPath, hd.Secp256k1)
+		}
+	case len(req.Multisig) > 0:
+		var pubKey cryptotypes.PubKey
+
+		if er := s.cdc.UnmarshalInterface(req.Multisig, &pubKey); er != nil {
+			s.respond(w, http.StatusBadRequest, errorResponse{Error: "invalid multisig public key"})
+			return
+		}
+
+		record, err = s.keyring.SaveMultisig(req.UID, pubKey)
+	default:
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "private key, wallet or multisig public key is required"})
+		return
+	}
+
+	if err != nil {
+		s.fail(w, req.UID, err)
+		return
+	}
+
+	s.logger.Infow("key added", "uid", req.UID, "type", record.GetType().String())
+
+	s.respondRecord(w, record)
+}
+
+func (s *Server) deleteRecord(w http.ResponseWriter, r *http.Request) {
+	record, err := s.lookup(r)
+	if err != nil {
+		s.fail(w, r.URL.RawQuery, err)
+		return
+	}
+
+	if err := s.keyring.Delete(record.Name); err != nil {
+		s.fail(w, record.Name, err)
+		return
+	}
+
+	s.logger.Infow("key deleted", "uid", record.Name)
+
+	s.respond(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) renameRecord(w http.ResponseWriter, r *http.Request) {
+	var req renameRequest
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil || req.To == "" {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "malformed rename request"})
+		return
+	}
+
+	if err := s.keyring.Rename(req.From, req.To); err != nil {
+		s.fail(w, req.From, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) saveWallet(w http.ResponseWriter, r *http.Request) {
+	var req walletRequest
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil || req.Wallet == "" {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "malformed wallet request"})
+		return
+	}
+
+	if !bip39.IsMnemonicValid(req.Mnemonic) {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "invalid mnemonic"})
+		return
+	}
+
+	if s.wallets == nil {
+		s.respond(w, http.StatusNotFound, errorResponse{Error: "signer doesn't keep wallets"})
+		return
+	}
+
+	if err := s.wallets.Set(dkeyring.Item{Key: req.Wallet, Data: []byte(req.Mnemonic)}); err != nil {
+		s.fail(w, req.Wallet, err)
+		return
+	}
+
+	s.logger.Infow("wallet saved", "wallet", req.Wallet)
+
+	s.respond(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) deleteWallet(w http.ResponseWriter, r *http.Request) {
+	wallet := r.URL.Query().Get("wallet")
+
+	if s.wallets != nil {
+		if err := s.wallets.Remove(wallet); err != nil && !errors.Is(err, dkeyring.ErrKeyNotFound) {
+			s.fail(w, wallet, err)
+			return
+		}
+	}
+
+	s.logger.Infow("wallet deleted", "wallet", wallet)
+
+	s.respond(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) walletAddress(w http.ResponseWriter, r *http.Request) {
+	wallet := r.URL.Query().Get("wallet")
+
+	mnemonic, err := s.mnemonic(wallet)
+	if err != nil {
+		s.fail(w, wallet, err)
+		return
+	}
+
+	derived, err := hd.Secp256k1.Derive()(mnemonic, "", r.URL.Query().Get("hd_path"))
+	if err != nil {
+		s.respond(w, http.StatusBadRequest, errorResponse{Error: "invalid hd path"})
+		return
+	}
+
+	privKey := hd.Secp256k1.Generate()(derived)
+
+	s.respond(w, http.StatusOK, addressResponse{Address: sdk.AccAddress(privKey.PubKey().Address()).String()})
+}
+
+// lookup returns the record of the request by uid or address
+func (s *Server) lookup(r *http.Request) (*keyring.Record, error) {
+	if uid := r.URL.Query().Get("uid"); uid != "" {
+		return s.keyring.Key(uid)
+	}
+
+	address, err := sdk.AccAddressFromBech32(r.URL.Query().Get("address"))
+	if err != nil {
+		return nil, sdkerrors.ErrKeyNotFound.Wrap("invalid account address")
+	}
+
+	return s.keyring.KeyByAddress(address)
+}
+
+func (s *Server) mnemonic(wallet string) (string, error) {
+	if s.wallets == nil {
+		return "", fmt.Errorf("%w: signer doesn't keep wallets", ErrKeyNotFound)
+	}
+
+	item, err := s.wallets.Get(wallet)
+	if err != nil {
+		if errors.Is(err, dkeyring.ErrKeyNotFound) {
+			return "", fmt.Errorf("%w: wallet %s", ErrKeyNotFound, wallet)
+		}
+
+		return "", err
+	}
+
+	return string(item.Data), nil
+}
+
+// respondRecord responds with the public part of the record, private keys never leave the signer
+func (s *Server) respondRecord(w http.ResponseWriter, record *keyring.Record) {
+	if record.GetLocal() != nil {
+		pubKey, err := record.GetPubKey()
+		if err != nil {
+			s.fail(w, record.Name, err)
+			return
+		}
+
+		if record, err = keyring.NewOfflineRecord(record.Name, pubKey); err != nil {
+			s.fail(w, record.Name, err)
+			return
+		}
+	}
+
+	bz, err := s.cdc.Marshal(record)
+	if err != nil {
+		s.fail(w, record.Name, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, recordResponse{Record: bz})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) fail(w http.ResponseWriter, key string, err error) {
+	if errors.Is(err, ErrKeyNotFound) || errors.Is(err, sdkerrors.ErrKeyNotFound) {
+		s.respond(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if errors.Is(err, keyring.ErrDuplicatedAddress) || errors.Is(err, keyring.ErrOverwriteKey) ||
+		errors.Is(err, keyring.ErrKeyAlreadyExists) {
+		s.respond(w, http.StatusConflict, errorResponse{Error: err.Error()})
+		return
+	}
+
+	s.logger.Errorw("signer request failed", "key", key, "error", err)
+
+	s.respond(w, http.StatusInternalServerError, errorResponse{Error: http.StatusText(http.StatusInternalServerError)})
+}
+
+func (s *Server) respond(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		s.logger.Errorw("cannot write signer response", "error", err)
+	}
+}
@@ -0,0 +1,52 @@
+// Package signer signs transactions and registry documents with account keys. Keys may stay in the client-helper
+// keyring, or live on the separate host that is reached through the remote signer protocol.
+package signer
+
+import (
+	"context"
+	"errors"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// ErrKeyNotFound is returned when the signer has no key of the account
+	ErrKeyNotFound = errors.New("signer has no key of the account")
+
+	// ErrInvalidSignature is returned when the signature doesn't match the account public key
+	ErrInvalidSignature = errors.New("signer returned invalid signature")
+
+	// ErrKeyExists is returned when the signer already has the key of the name or address
+	ErrKeyExists = errors.New("signer already has the key")
+
+	// ErrKeyExport is returned when the private key of the remote signer is requested
+	ErrKeyExport = errors.New("private keys of the remote signer cannot be exported")
+
+	// ErrMnemonicRequired is returned when the key is derived from the mnemonic that is not kept by the signer
+	ErrMnemonicRequired = errors.New("keys of the remote signer are derived from mnemonics of its wallets")
+)
+
+// Signer signs messages with the key of the account
+type Signer interface {
+	// PubKey returns the public key of the account
+	PubKey() cryptotypes.PubKey
+
+	// Sign signs the message, the message is hashed by the signer the same way cryptotypes.PrivKey.Sign does
+	Sign(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// Provider returns signers of the accounts
+type Provider interface {
+	Signer(ctx context.Context, address string) (Signer, error)
+}
+
+// Address returns the account address of the signer
+func Address(s Signer) sdk.AccAddress {
+	return sdk.AccAddress(s.PubKey().Address())
+}
+
+// IsSignerError errors of the signer that can be sent back to the client
+func IsSignerError(err error) bool {
+	return errors.Is(err, ErrKeyNotFound) || errors.Is(err, ErrInvalidSignature)
+}
@@ -0,0 +1,211 @@
+package signer_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dkeyring "github.com/99designs/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/module/testutil"
+	"github.com/obada-foundation/client-helper/system/signer"
+	chtestutil "github.com/obada-foundation/client-helper/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemote(t *testing.T) {
+	ctx := context.Background()
+
+	logger, deleteLogFile := chtestutil.MakeLoger()
+	defer deleteLogFile()
+
+	kr := keyring.NewInMemory(testutil.MakeTestEncodingConfig().Codec)
+
+	record, _, err := kr.NewMnemonic("signer", keyring.English, sdk.FullFundraiserPath, "", hd.Secp256k1)
+	require.NoError(t, err)
+
+	addr, err := record.GetAddress()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(signer.NewServer(signer.ServerConfig{
+		Provider: signer.NewKeyring(kr),
+		Token:    "secret",
+		Logger:   logger,
+	}))
+	defer srv.Close()
+
+	remote, err := signer.NewRemote(signer.RemoteConfig{URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	msg := []byte("sign bytes")
+
+	t.Log("Signs with the key of the remote signer")
+	{
+		s, err := remote.Signer(ctx, addr.String())
+		require.NoError(t, err)
+		require.Equal(t, addr, signer.Address(s))
+
+		signature, err := s.Sign(ctx, msg)
+		require.NoError(t, err)
+		require.True(t, s.PubKey().VerifySignature(msg, signature))
+	}
+
+	t.Log("Returns the same signature as the keyring signer")
+	{
+		local, err := signer.NewKeyring(kr).Signer(ctx, addr.String())
+		require.NoError(t, err)
+
+		s, err := remote.Signer(ctx, addr.String())
+		require.NoError(t, err)
+
+		localSignature, err := local.Sign(ctx, msg)
+		require.NoError(t, err)
+
+		remoteSignature, err := s.Sign(ctx, msg)
+		require.NoError(t, err)
+		require.Equal(t, localSignature, remoteSignature)
+	}
+
+	t.Log("Fails for the unknown key")
+	{
+		unknown := sdk.AccAddress([]byte("unknown-account-addr")).String()
+
+		_, err := remote.Signer(ctx, unknown)
+		require.ErrorIs(t, err, signer.ErrKeyNotFound)
+
+		_, err = signer.NewKeyring(kr).Signer(ctx, unknown)
+		require.ErrorIs(t, err, signer.ErrKeyNotFound)
+	}
+
+	t.Log("Rejects requests without the token")
+	{
+		unauthorized, err := signer.NewRemote(signer.RemoteConfig{URL: srv.URL, Token: "wrong"})
+		require.NoError(t, err)
+
+		_, err = unauthorized.Signer(ctx, addr.String())
+		require.ErrorContains(t, err, "401")
+	}
+
+	t.Log("Rejects signatures that do not match the public key")
+	{
+		pubKey, err := record.GetPubKey()
+		require.NoError(t, err)
+
+		forged := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				_ = json.NewEncoder(w).Encode(map[string]any{"address": addr.String(), "pub_key": pubKey.Bytes()})
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"signature": []byte("forged")})
+		}))
+		defer forged.Close()
+
+		forgedRemote, err := signer.NewRemote(signer.RemoteConfig{URL: forged.URL})
+		require.NoError(t, err)
+
+		s, err := forgedRemote.Signer(ctx, addr.String())
+		require.NoError(t, err)
+
+		_, err = s.Sign(ctx, msg)
+		require.ErrorIs(t, err, signer.ErrInvalidSignature)
+	}
+}
+
+func TestRemoteKeys(t *testing.T) {
+	ctx := context.Background()
+
+	logger, deleteLogFile := chtestutil.MakeLoger()
+	defer deleteLogFile()
+
+	kr := keyring.NewInMemory(testutil.MakeTestEncodingConfig().Codec)
+
+	srv := httptest.NewServer(signer.NewServer(signer.ServerConfig{
+		Provider: signer.NewKeyring(kr),
+		Keyring:  kr,
+		Wallets:  dkeyring.NewArrayKeyring(nil),
+		Token:    "secret",
+		Logger:   logger,
+	}))
+	defer srv.Close()
+
+	remote, err := signer.NewRemote(signer.RemoteConfig{URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	keys := remote.Keys()
+
+	mnemonic := "radio distance sweet artefact attack liar until video army raccoon green error ceiling size spread burst " +
+		"galaxy bottom cave rubber setup west address must"
+
+	t.Log("Derives keys of the wallet kept by the signer")
+	{
+		require.NoError(t, remote.SaveMnemonic(ctx, "wallet", mnemonic))
+
+		address, err := remote.DeriveAddress(ctx, "wallet", sdk.FullFundraiserPath)
+		require.NoError(t, err)
+
+		record, err := remote.DeriveKey(ctx, "wallet", "account", sdk.FullFundraiserPath)
+		require.NoError(t, err)
+		require.Nil(t, record.GetLocal(), "the private key doesn't leave the signer")
+
+		addr, err := record.GetAddress()
+		require.NoError(t, err)
+		require.Equal(t, address, addr.String())
+
+		_, err = remote.DeriveKey(ctx, "wallet", "duplicate", sdk.FullFundraiserPath)
+		require.ErrorIs(t, err, signer.ErrKeyExists)
+
+		_, err = remote.DeriveKey(ctx, "unknown", "account2", sdk.FullFundraiserPath)
+		require.ErrorIs(t, err, signer.ErrKeyNotFound)
+	}
+
+	t.Log("Imports the private key and signs with it")
+	{
+		privKey := secp256k1.GenPrivKey()
+		address := sdk.AccAddress(privKey.PubKey().Address())
+
+		require.NoError(t, keys.ImportPrivKeyHex("imported", hex.EncodeToString(privKey.Bytes()), string(hd.Secp256k1Type)))
+
+		record, err := keys.KeyByAddress(address)
+		require.NoError(t, err)
+		require.Equal(t, "imported", record.Name)
+
+		s, err := remote.Signer(ctx, address.String())
+		require.NoError(t, err)
+
+		signature, err := s.Sign(ctx, []byte("sign bytes"))
+		require.NoError(t, err)
+		require.True(t, privKey.PubKey().VerifySignature([]byte("sign bytes"), signature))
+
+		_, err = keys.ExportPrivKeyArmor("imported", "")
+		require.ErrorIs(t, err, signer.ErrKeyExport)
+	}
+
+	t.Log("Renames and deletes keys")
+	{
+		require.NoError(t, keys.Rename("imported", "renamed"))
+
+		_, err := keys.Key("imported")
+		require.ErrorIs(t, err, sdkerrors.ErrKeyNotFound)
+
+		require.NoError(t, keys.Delete("renamed"))
+
+		_, err = kr.Key("renamed")
+		require.ErrorIs(t, err, sdkerrors.ErrKeyNotFound)
+	}
+
+	t.Log("Deletes the wallet")
+	{
+		require.NoError(t, remote.DeleteMnemonic(ctx, "wallet"))
+
+		_, err := remote.DeriveAddress(ctx, "wallet", sdk.FullFundraiserPath)
+		require.ErrorIs(t, err, signer.ErrKeyNotFound)
+	}
+}